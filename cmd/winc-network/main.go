@@ -28,7 +28,7 @@ func main() {
 	app.Flags = []cli.Flag{
 		cli.StringFlag{
 			Name:  "action",
-			Usage: "network action e.g. up,down,create,delete",
+			Usage: "network action e.g. up,down,update,inspect,create,delete",
 			Value: "",
 		},
 		cli.StringFlag{
@@ -100,7 +100,7 @@ func main() {
 		}
 		handle := context.String("handle")
 		action := context.String("action")
-		if (action == "up" || action == "down") && handle == "" {
+		if (action == "up" || action == "down" || action == "update" || action == "inspect") && handle == "" {
 			return fmt.Errorf("missing required flag 'handle'")
 		}
 
@@ -125,6 +125,31 @@ func main() {
 				return fmt.Errorf("networkUp: %s", err.Error())
 			}
 
+		case "update":
+			var inputs network.UpInputs
+			if err := json.NewDecoder(os.Stdin).Decode(&inputs); err != nil {
+				return fmt.Errorf("networkUpdate: %s", err.Error())
+			}
+
+			outputs, err := networkManager.Update(inputs)
+			if err != nil {
+				return fmt.Errorf("networkUpdate: %s", err.Error())
+			}
+
+			if err := json.NewEncoder(os.Stdout).Encode(outputs); err != nil {
+				return fmt.Errorf("networkUpdate: %s", err.Error())
+			}
+
+		case "inspect":
+			outputs, err := wireInspector(config, handle).Inspect()
+			if err != nil {
+				return fmt.Errorf("networkInspect: %s", err.Error())
+			}
+
+			if err := json.NewEncoder(os.Stdout).Encode(outputs); err != nil {
+				return fmt.Errorf("networkInspect: %s", err.Error())
+			}
+
 		case "create":
 			if err := networkManager.CreateHostNATNetwork(); err != nil {
 				return fmt.Errorf("network create: %s", err.Error())
@@ -171,20 +196,7 @@ func wireNetworkManager(config network.Config, handle string) (*network.NetworkM
 	hcsClient := &hcs.Client{}
 	runner := netsh.NewRunner(hcsClient, handle, config.WaitTimeoutInSeconds)
 
-	tracker := &port_allocator.Tracker{
-		StartPort: 40000,
-		Capacity:  5000,
-	}
-
-	locker := filelock.NewLocker("C:\\var\\vcap\\data\\winc-network\\port-state.json")
-
-	portAllocator := &port_allocator.PortAllocator{
-		Tracker:    tracker,
-		Serializer: &serial.Serial{},
-		Locker:     locker,
-	}
-
-	applier, err := wireApplier(runner, handle, portAllocator)
+	applier, err := wireApplier(runner, handle, wirePortAllocator())
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +218,32 @@ func wireNetworkManager(config network.Config, handle string) (*network.NetworkM
 	), nil
 }
 
+func wireInspector(config network.Config, handle string) *network.Inspector {
+	hcsClient := &hcs.Client{}
+
+	return network.NewInspector(
+		endpoint.NewEndpointManager(hcsClient, handle, config),
+		wirePortAllocator(),
+		mtu.New(handle, config.NetworkName, &netinterface.NetInterface{}),
+		handle,
+	)
+}
+
+func wirePortAllocator() *port_allocator.PortAllocator {
+	tracker := &port_allocator.Tracker{
+		StartPort: 40000,
+		Capacity:  5000,
+	}
+
+	locker := filelock.NewLocker("C:\\var\\vcap\\data\\winc-network\\port-state.json")
+
+	return &port_allocator.PortAllocator{
+		Tracker:    tracker,
+		Serializer: &serial.Serial{},
+		Locker:     locker,
+	}
+}
+
 func fatal(err error) {
 	logrus.Error(err)
 	fmt.Fprintln(os.Stderr, err)
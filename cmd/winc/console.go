@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"code.cloudfoundry.org/winc/runtime"
+	"golang.org/x/sys/windows"
+)
+
+// console is the Windows console winc was started from. It satisfies
+// runtime.Console so an attached TTY process can follow its size.
+type console struct {
+	in, out         windows.Handle
+	inMode, outMode uint32
+}
+
+// newConsole returns nil if stdin or stdout is not a console, e.g. when winc
+// is run with redirected IO
+func newConsole() *console {
+	c := &console{
+		in:  windows.Handle(os.Stdin.Fd()),
+		out: windows.Handle(os.Stdout.Fd()),
+	}
+
+	if err := windows.GetConsoleMode(c.in, &c.inMode); err != nil {
+		return nil
+	}
+	if err := windows.GetConsoleMode(c.out, &c.outMode); err != nil {
+		return nil
+	}
+
+	return c
+}
+
+func (c *console) Size() (uint16, uint16, error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(c.out, &info); err != nil {
+		return 0, 0, err
+	}
+
+	width := info.Window.Right - info.Window.Left + 1
+	height := info.Window.Bottom - info.Window.Top + 1
+	if width <= 0 || height <= 0 {
+		return 0, 0, errors.New("invalid console window size")
+	}
+
+	return uint16(width), uint16(height), nil
+}
+
+// SetRaw passes keystrokes through untouched as VT sequences and interprets
+// VT sequences written by the process, so the console behaves like a pty
+func (c *console) SetRaw() error {
+	inMode := c.inMode
+	inMode &^= windows.ENABLE_ECHO_INPUT | windows.ENABLE_LINE_INPUT | windows.ENABLE_PROCESSED_INPUT
+	inMode |= windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(c.in, inMode); err != nil {
+		return err
+	}
+
+	outMode := c.outMode | windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING | windows.DISABLE_NEWLINE_AUTO_RETURN
+	return windows.SetConsoleMode(c.out, outMode)
+}
+
+func (c *console) Reset() error {
+	if err := windows.SetConsoleMode(c.in, c.inMode); err != nil {
+		return err
+	}
+
+	return windows.SetConsoleMode(c.out, c.outMode)
+}
+
+// processIO connects a process to winc's own stdio or, when consoleSocket is
+// set, to a connection on that unix socket. Windows cannot pass a console
+// handle over a socket the way runc passes a pty master, so the caller gets
+// the console's IO streamed over the connection instead and propagates size
+// changes with 'winc resize'.
+func processIO(consoleSocket string, detach bool) (runtime.IO, func(), error) {
+	if consoleSocket == "" {
+		io := runtime.IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+		// the console is only used if the process has a terminal
+		if c := newConsole(); c != nil {
+			io.Console = c
+		}
+		return io, func() {}, nil
+	}
+
+	if detach {
+		return runtime.IO{}, nil, errors.New("--console-socket cannot be used with --detach")
+	}
+
+	conn, err := net.Dial("unix", consoleSocket)
+	if err != nil {
+		return runtime.IO{}, nil, fmt.Errorf("connecting to console socket: %s", err.Error())
+	}
+
+	io := runtime.IO{Stdin: conn, Stdout: conn, Stderr: conn}
+	// #nosec G104 - we don't need to capture errors from closing the console socket as the process has exited
+	return io, func() { conn.Close() }, nil
+}
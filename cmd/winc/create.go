@@ -19,7 +19,10 @@ your host.`,
 
 	The specification file includes an args parameter. The args parameter is used
 	to specify command(s) that get run when the container is started. To change the
-	command(s) that get executed on start, edit the args parameter of the spec`,
+	command(s) that get executed on start, edit the args parameter of the spec.
+
+	A container whose process requests a terminal can't be created, as there is
+	no console socket to hand the terminal to. Use the run command instead.`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  "bundle, b",
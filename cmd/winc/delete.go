@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/urfave/cli"
 )
 
@@ -22,6 +24,14 @@ status of "windows01" as "stopped" the following will delete resources held for
 			Name:  "force, f",
 			Usage: "Do not return an error if <container-id> does not exist",
 		},
+		cli.DurationFlag{
+			Name:  "timeout, t",
+			Usage: "how long to wait for each of shutting down and terminating a running container (defaults to stop_timeout_seconds from the config file, or 1m)",
+		},
+		cli.BoolFlag{
+			Name:  "pre-stop",
+			Usage: "send CTRL_SHUTDOWN to the init process and wait up to the timeout for it to exit before shutting down the container",
+		},
 	},
 
 	Action: func(context *cli.Context) error {
@@ -31,7 +41,12 @@ status of "windows01" as "stopped" the following will delete resources held for
 
 		containerId := context.Args().First()
 		force := context.Bool("force")
+		timeout := context.Duration("timeout")
+		if timeout < 0 {
+			return fmt.Errorf("invalid timeout: %s", timeout)
+		}
+		preStop := context.Bool("pre-stop")
 
-		return run.Delete(containerId, force)
+		return run.Delete(containerId, force, timeout, preStop)
 	},
 }
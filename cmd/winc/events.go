@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/urfave/cli"
 )
@@ -12,8 +13,11 @@ var eventsCommand = cli.Command{
 	ArgsUsage: `<container-id>
 
 Where "<container-id>" is your name for the instance of the container.`,
-	Description: `The events command displays information about the container.`,
+	Description: `The events command displays information about the container. By default
+the stats are displayed every interval as newline-delimited JSON, along with
+oom events when the container runs out of memory, until the container exits.`,
 	Flags: []cli.Flag{
+		cli.DurationFlag{Name: "interval", Value: 5 * time.Second, Usage: "set the stats collection interval"},
 		cli.BoolFlag{Name: "stats", Usage: "display the container's stats then exit"},
 	},
 	Action: func(context *cli.Context) error {
@@ -23,7 +27,8 @@ Where "<container-id>" is your name for the instance of the container.`,
 
 		containerId := context.Args().First()
 		showStats := context.Bool("stats")
+		interval := context.Duration("interval")
 
-		return run.Events(containerId, os.Stdout, showStats)
+		return run.Events(containerId, os.Stdout, showStats, interval)
 	},
 }
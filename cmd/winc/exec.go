@@ -1,9 +1,9 @@
 package main
 
 import (
+	"errors"
 	"os"
 
-	"code.cloudfoundry.org/winc/runtime"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/urfave/cli"
 )
@@ -51,10 +51,15 @@ following will output a list of processes running in the container:
 			Name:  "env, e",
 			Usage: "set environment variables",
 		},
-		// 	cli.BoolFlag{
-		// 		Name:  "tty, t",
-		// 		Usage: "allocate a pseudo-TTY",
-		// 	},
+		cli.BoolFlag{
+			Name:  "tty, t",
+			Usage: "allocate a pseudo-TTY",
+		},
+		cli.StringFlag{
+			Name:  "console-socket",
+			Value: "",
+			Usage: "path to a unix socket to stream the TTY's IO over, requires --tty",
+		},
 	},
 	Action: func(context *cli.Context) error {
 		if err := checkArgs(context, 1, minArgs); err != nil {
@@ -69,6 +74,8 @@ following will output a list of processes running in the container:
 		env := context.StringSlice("env")
 		pidFile := context.String("pid-file")
 		detach := context.Bool("detach")
+		tty := context.Bool("tty")
+		consoleSocket := context.String("console-socket")
 
 		processOverrides := &specs.Process{
 			Args: args,
@@ -76,11 +83,21 @@ following will output a list of processes running in the container:
 			User: specs.User{
 				Username: user,
 			},
-			Env: env,
+			Env:      env,
+			Terminal: tty,
+		}
+
+		if consoleSocket != "" && !tty {
+			return errors.New("--console-socket requires --tty")
+		}
+
+		io, closeIO, err := processIO(consoleSocket, detach)
+		if err != nil {
+			return err
 		}
 
-		io := runtime.IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
 		exitCode, err := run.Exec(containerId, processConfig, pidFile, processOverrides, io, detach)
+		closeIO()
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var gcCommand = cli.Command{
+	Name:  "gc",
+	Usage: "removes compute systems, volume mounts and state left behind by containers winc failed to clean up",
+	ArgsUsage: `
+
+Compute systems without a state directory under the given root, state
+directories without a compute system, and volume mounts and file mount
+directories that don't belong to a container are removed, as are volume
+mounts made by pid by older versions of winc whose process has exited.
+Containers that another winc command is operating on are skipped.
+
+A JSON report of what was removed is printed to stdout.
+
+EXAMPLE:
+To see what would be removed, without removing anything:
+       # winc gc --dry-run`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "report what would be removed without removing it",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 0, exactArgs); err != nil {
+			return err
+		}
+
+		return run.GC(context.Bool("dry-run"), os.Stdout)
+	},
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var killCommand = cli.Command{
+	Name:  "kill",
+	Usage: "kill sends the specified signal (default: SIGTERM) to the container",
+	ArgsUsage: `<container-id> [signal]
+
+Where "<container-id>" is the name for the instance of the container and
+"[signal]" is the signal to be sent to the container. Only SIGTERM, which
+shuts the container down gracefully, and SIGKILL, which terminates it
+immediately, are supported.
+
+EXAMPLE:
+For example, if the container id is "windows01" the following will send a
+"KILL" signal to the container:
+
+       # winc kill windows01 KILL`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all, a",
+			Usage: "kill all processes in the container instead of the container itself",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, minArgs); err != nil {
+			return err
+		}
+		if err := checkArgs(context, 2, maxArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+		signal := context.Args().Get(1)
+		if signal == "" {
+			signal = "SIGTERM"
+		}
+		all := context.Bool("all")
+
+		return run.Kill(containerId, signal, all)
+	},
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var listCommand = cli.Command{
+	Name:  "list",
+	Usage: "lists containers started by winc with the given root",
+	ArgsUsage: `
+
+Where the given root is specified via the global option "--root"
+(default: "C:\ProgramData\winc").
+
+Containers whose state directory has no matching compute system, or whose
+compute system has no matching state directory, are flagged as orphans.
+
+EXAMPLE 1:
+To list containers created via the default "--root":
+       # winc list
+
+EXAMPLE 2:
+To list containers created using a non-default value for "--root":
+       # winc --root value list`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "table",
+			Usage: `select one of: table or json`,
+		},
+		cli.BoolFlag{
+			Name:  "quiet, q",
+			Usage: "display only container IDs",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 0, exactArgs); err != nil {
+			return err
+		}
+
+		format := context.String("format")
+		quiet := context.Bool("quiet")
+
+		return run.List(format, quiet, os.Stdout)
+	},
+}
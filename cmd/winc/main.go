@@ -8,12 +8,14 @@ import (
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/hcsprocess"
+	"code.cloudfoundry.org/winc/runtime/hooks"
 	"code.cloudfoundry.org/winc/runtime/mount"
 	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
@@ -46,16 +48,24 @@ func (f *stateFactory) NewManager(logger *logrus.Entry, hcsClient *hcs.Client, w
 	return state.New(logger, hcsClient, winSyscall, id, rootDir)
 }
 
-type containerFactory struct{}
+type containerFactory struct {
+	fileMountsRoot string
+}
 
 func (f *containerFactory) NewManager(logger *logrus.Entry, hcsClient *hcs.Client, id string) runtime.ContainerManager {
-	return container.New(logger, hcsClient, id)
+	return container.New(logger, hcsClient, id, f.fileMountsRoot)
+}
+
+func (f *containerFactory) FileMounts() ([]string, error) {
+	return container.FileMounts(f.fileMountsRoot)
 }
 
-type processWrapper struct{}
+type processWrapper struct {
+	drainTimeout time.Duration
+}
 
 func (w *processWrapper) Wrap(p hcs.Process) runtime.WrappedProcess {
-	return hcsprocess.New(p)
+	return hcsprocess.New(p, w.drainTimeout)
 }
 
 func main() {
@@ -119,6 +129,14 @@ func main() {
 		startCommand,
 		execCommand,
 		eventsCommand,
+		killCommand,
+		listCommand,
+		pauseCommand,
+		resumeCommand,
+		updateCommand,
+		psCommand,
+		resizeCommand,
+		gcCommand,
 	}
 
 	app.Before = func(context *cli.Context) error {
@@ -184,13 +202,19 @@ func main() {
 			}
 		}
 
-		containerFactory := &containerFactory{}
+		mountRoot := config.MountRoot
+		if mountRoot == "" {
+			mountRoot = mount.DefaultRoot
+		}
+
+		containerFactory := &containerFactory{fileMountsRoot: container.FileMountsRoot(mountRoot)}
 		stateFactory := &stateFactory{}
-		mounter := &mount.Mounter{}
+		mounter := &mount.Mounter{Root: mountRoot}
 		hcsClient := &hcs.Client{}
-		processWrapper := &processWrapper{}
+		processWrapper := &processWrapper{drainTimeout: time.Duration(config.OutputDrainTimeoutSeconds) * time.Second}
+		hookRunner := &hooks.Runner{}
 
-		run = runtime.New(stateFactory, containerFactory, mounter, hcsClient, processWrapper, rootDir, credentialSpecPath, config)
+		run = runtime.New(stateFactory, containerFactory, mounter, hcsClient, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 		return nil
 	}
 
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var pauseCommand = cli.Command{
+	Name:  "pause",
+	Usage: "pause suspends all processes inside the container",
+	ArgsUsage: `<container-id>
+
+Where "<container-id>" is the name for the instance of the container to be
+paused. `,
+	Description: `The pause command suspends all processes in the instance of the container.
+
+Use winc list to identify instances of containers and their current status.`,
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+
+		return run.Pause(containerId)
+	},
+}
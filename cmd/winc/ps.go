@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var psCommand = cli.Command{
+	Name:      "ps",
+	Usage:     "displays the processes running inside a container",
+	ArgsUsage: `<container-id>`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "table",
+			Usage: `select one of: table or json`,
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+		format := context.String("format")
+
+		return run.Ps(containerId, format, os.Stdout)
+	},
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"math"
+
+	"github.com/urfave/cli"
+)
+
+var resizeCommand = cli.Command{
+	Name:  "resize",
+	Usage: "resize the console of a process that has a TTY",
+	ArgsUsage: `<container-id>
+
+Where "<container-id>" is the name for the instance of the container.`,
+	Description: `The resize command changes the console size of a process started with a
+TTY, e.g. when the terminal attached through --console-socket is resized.
+
+EXAMPLE:
+
+       # winc resize --width 120 --height 40 <container-id>`,
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "pid",
+			Usage: "process to resize, defaults to the container's init process",
+		},
+		cli.UintFlag{
+			Name:  "width",
+			Usage: "console width in columns",
+		},
+		cli.UintFlag{
+			Name:  "height",
+			Usage: "console height in rows",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+		pid := context.Int("pid")
+		width, err := uint16Flag(context, "width", 1, math.MaxUint16)
+		if err != nil {
+			return err
+		}
+		height, err := uint16Flag(context, "height", 1, math.MaxUint16)
+		if err != nil {
+			return err
+		}
+
+		return run.Resize(containerId, pid, width, height)
+	},
+}
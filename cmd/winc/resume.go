@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var resumeCommand = cli.Command{
+	Name:  "resume",
+	Usage: "resumes all processes that have been previously paused",
+	ArgsUsage: `<container-id>
+
+Where "<container-id>" is the name for the instance of the container to be
+resumed.`,
+	Description: `The resume command resumes all processes in the instance of the container.
+
+Use winc list to identify instances of containers and their current status.`,
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+
+		return run.Resume(containerId)
+	},
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"os"
 
-	"code.cloudfoundry.org/winc/runtime"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
@@ -38,6 +37,11 @@ command(s) that get executed on start, edit the args parameter of the spec.`,
 			Value: "",
 			Usage: "specify the file to write the process id to",
 		},
+		cli.StringFlag{
+			Name:  "console-socket",
+			Value: "",
+			Usage: "path to a unix socket to stream the TTY's IO over, if the spec requests a terminal",
+		},
 		cli.BoolFlag{
 			Name:  "no-new-keyring",
 			Usage: "ignored",
@@ -52,6 +56,7 @@ command(s) that get executed on start, edit the args parameter of the spec.`,
 		bundlePath := context.String("bundle")
 		detach := context.Bool("detach")
 		pidFile := context.String("pid-file")
+		consoleSocket := context.String("console-socket")
 
 		logger := logrus.WithFields(logrus.Fields{
 			"bundle":      bundlePath,
@@ -61,8 +66,13 @@ command(s) that get executed on start, edit the args parameter of the spec.`,
 		})
 		logger.Debug("creating container")
 
-		io := runtime.IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+		io, closeIO, err := processIO(consoleSocket, detach)
+		if err != nil {
+			return err
+		}
+
 		exitCode, err := run.Run(containerId, bundlePath, pidFile, io, detach)
+		closeIO()
 		if err != nil {
 			return err
 		}
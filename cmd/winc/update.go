@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/urfave/cli"
+)
+
+var updateCommand = cli.Command{
+	Name:      "update",
+	Usage:     "update container resource constraints",
+	ArgsUsage: `<container-id>`,
+	Description: `The update command changes the resource limits of a running container.
+
+The accepted format of the resources file is the "windows.resources" section of
+an OCI runtime spec, for example:
+
+{
+  "memory": {
+    "limit": 1073741824
+  },
+  "cpu": {
+    "shares": 5000
+  }
+}
+
+Note: if the file is set to "-", the resources are read from stdin, and any
+limits passed as flags take precedence over the file.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "resources, r",
+			Value: "",
+			Usage: `path to the file containing the resources to update or '-' to read from the standard input`,
+		},
+		cli.Uint64Flag{
+			Name:  "memory",
+			Usage: "memory limit (in bytes)",
+		},
+		cli.UintFlag{
+			Name:  "cpu-shares",
+			Usage: "CPU shares (relative weight between 1 and 10000)",
+		},
+		cli.UintFlag{
+			Name:  "cpu-maximum",
+			Usage: "portion of processor cycles the container can use, as a percentage times 100 (between 1 and 10000)",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+
+		resources := &specs.WindowsResources{}
+		if path := context.String("resources"); path != "" {
+			var r io.Reader = os.Stdin
+			if path != "-" {
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+
+			if err := json.NewDecoder(r).Decode(resources); err != nil {
+				return fmt.Errorf("resources: %s", err.Error())
+			}
+		}
+
+		if context.IsSet("memory") {
+			limit := context.Uint64("memory")
+			resources.Memory = &specs.WindowsMemoryResources{Limit: &limit}
+		}
+
+		if context.IsSet("cpu-shares") || context.IsSet("cpu-maximum") {
+			if resources.CPU == nil {
+				resources.CPU = &specs.WindowsCPUResources{}
+			}
+			if context.IsSet("cpu-shares") {
+				shares, err := uint16Flag(context, "cpu-shares", 1, 10000)
+				if err != nil {
+					return err
+				}
+				resources.CPU.Shares = &shares
+			}
+			if context.IsSet("cpu-maximum") {
+				maximum, err := uint16Flag(context, "cpu-maximum", 1, 10000)
+				if err != nil {
+					return err
+				}
+				resources.CPU.Maximum = &maximum
+			}
+		}
+
+		return run.Update(containerId, resources)
+	},
+}
+
+// uint16Flag range checks a uint flag before converting it, as a value that
+// doesn't fit would otherwise be truncated into a valid one
+func uint16Flag(context *cli.Context, name string, min, max uint) (uint16, error) {
+	value := context.Uint(name)
+	if value < min || value > max || value > math.MaxUint16 {
+		return 0, fmt.Errorf("%s %d must be between %d and %d", name, value, min, max)
+	}
+	return uint16(value), nil
+}
@@ -33,6 +33,14 @@ func (c *Client) IsPending(err error) bool {
 	return hcsshim.IsPending(err)
 }
 
+func (c *Client) IsNotExist(err error) bool {
+	return hcsshim.IsNotExist(err)
+}
+
+func (c *Client) IsAlreadyStopped(err error) bool {
+	return hcsshim.IsAlreadyStopped(err)
+}
+
 func (c *Client) GetContainerProperties(id string) (hcsshim.ContainerProperties, error) {
 	query := hcsshim.ComputeSystemQuery{
 		IDs: []string{id},
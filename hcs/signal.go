@@ -0,0 +1,89 @@
+package hcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// CtrlShutdown is delivered to a process as CTRL_SHUTDOWN_EVENT, giving it
+// the chance to exit cleanly before its container is shut down
+const CtrlShutdown = "CtrlShutdown"
+
+// hcsshim only exposes process signals through its internal packages, so
+// they are sent with vmcompute directly
+var (
+	vmcompute             = windows.NewLazySystemDLL("vmcompute.dll")
+	hcsOpenComputeSystem  = vmcompute.NewProc("HcsOpenComputeSystem")
+	hcsCloseComputeSystem = vmcompute.NewProc("HcsCloseComputeSystem")
+	hcsOpenProcess        = vmcompute.NewProc("HcsOpenProcess")
+	hcsCloseProcess       = vmcompute.NewProc("HcsCloseProcess")
+	hcsSignalProcess      = vmcompute.NewProc("HcsSignalProcess")
+)
+
+func (c *Client) SignalProcess(id string, pid int, signal string) error {
+	options, err := json.Marshal(struct{ Signal string }{Signal: signal})
+	if err != nil {
+		return err
+	}
+
+	idp, err := syscall.UTF16PtrFromString(id)
+	if err != nil {
+		return err
+	}
+
+	optionsp, err := syscall.UTF16PtrFromString(string(options))
+	if err != nil {
+		return err
+	}
+
+	var system uintptr
+	if err := hcsCall(hcsOpenComputeSystem, uintptr(unsafe.Pointer(idp)), uintptr(unsafe.Pointer(&system))); err != nil {
+		return err
+	}
+	// #nosec G104 - the handle is only used to send the signal
+	defer syscall.SyscallN(hcsCloseComputeSystem.Addr(), system)
+
+	var process uintptr
+	if err := hcsCall(hcsOpenProcess, system, uintptr(pid), uintptr(unsafe.Pointer(&process))); err != nil {
+		return err
+	}
+	// #nosec G104 - the handle is only used to send the signal
+	defer syscall.SyscallN(hcsCloseProcess.Addr(), process)
+
+	return hcsCall(hcsSignalProcess, process, uintptr(unsafe.Pointer(optionsp)))
+}
+
+// hcsCall calls a vmcompute function whose last argument is a result
+// document, which describes the failure when the call returns an error
+func hcsCall(proc *windows.LazyProc, args ...uintptr) error {
+	if err := proc.Find(); err != nil {
+		return err
+	}
+
+	var result *uint16
+	r0, _, _ := syscall.SyscallN(proc.Addr(), append(args, uintptr(unsafe.Pointer(&result)))...)
+
+	var details string
+	if result != nil {
+		details = windows.UTF16PtrToString(result)
+		windows.CoTaskMemFree(unsafe.Pointer(result))
+	}
+
+	if int32(r0) >= 0 {
+		return nil
+	}
+
+	// HRESULTs wrapping a win32 error are reported as the win32 error
+	if r0&0x1fff0000 == 0x00070000 {
+		r0 &= 0xffff
+	}
+
+	if details != "" {
+		return fmt.Errorf("%s: %s: %s", proc.Name, syscall.Errno(r0), details)
+	}
+	return fmt.Errorf("%s: %s", proc.Name, syscall.Errno(r0))
+}
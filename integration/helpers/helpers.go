@@ -191,6 +191,18 @@ func (h *Helpers) NetworkUp(id, input, networkConfigFile string) network.UpOutpu
 	return upOutput
 }
 
+func (h *Helpers) NetworkUpdate(id, input, networkConfigFile string) network.UpOutputs {
+	args := []string{"--action", "update", "--configFile", networkConfigFile, "--handle", id}
+	cmd := h.ExecCommand(h.wincNetworkBin, args...)
+	cmd.Stdin = strings.NewReader(input)
+	stdOut, _, err := h.Execute(cmd)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	var updateOutput network.UpOutputs
+	ExpectWithOffset(1, json.Unmarshal(stdOut.Bytes(), &updateOutput)).To(Succeed())
+	return updateOutput
+}
+
 func (h *Helpers) NetworkDown(id, networkConfigFile string) {
 	_, _, err := h.Execute(h.ExecCommand(h.wincNetworkBin, "--configFile", networkConfigFile, "--action", "down", "--handle", id))
 	ExpectWithOffset(1, err).NotTo(HaveOccurred())
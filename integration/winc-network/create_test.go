@@ -129,6 +129,35 @@ var _ = Describe("Create", func() {
 		})
 	})
 
+	Context("an IPv6 subnet is set in the config", func() {
+		BeforeEach(func() {
+			// derive the IPv6 subnet from the IPv4 gateway so that it's as unique as the NAT network
+			gateway := net.ParseIP(networkConfig.GatewayAddress).To4()
+			networkConfig.SubnetRangeIPv6 = fmt.Sprintf("fd00:%02x%02x:%02x%02x::/64", gateway[0], gateway[1], gateway[2], gateway[3])
+			networkConfig.GatewayAddressIPv6 = fmt.Sprintf("fd00:%02x%02x:%02x%02x::1", gateway[0], gateway[1], gateway[2], gateway[3])
+		})
+
+		It("creates the network with both subnet ranges", func() {
+			helpers.CreateNetwork(networkConfig, networkConfigFile)
+
+			natAdapter, err := net.InterfaceByName(fmt.Sprintf("vEthernet (%s)", networkConfig.NetworkName))
+			Expect(err).ToNot(HaveOccurred())
+
+			addrs, err := natAdapter.Addrs()
+			Expect(err).ToNot(HaveOccurred())
+
+			nets := []string{}
+			for _, a := range addrs {
+				_, network, err := net.ParseCIDR(a.String())
+				Expect(err).ToNot(HaveOccurred())
+				nets = append(nets, network.String())
+			}
+			_, ipv6Net, err := net.ParseCIDR(networkConfig.SubnetRangeIPv6)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nets).To(ContainElements(networkConfig.SubnetRange, ipv6Net.String()))
+		})
+	})
+
 	Context("mtu is set in the config", func() {
 		BeforeEach(func() {
 			networkConfig.MTU = 1400
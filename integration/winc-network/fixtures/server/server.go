@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
@@ -12,11 +13,15 @@ import (
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Printf("Usage: %s <port>", os.Args[0])
+		fmt.Printf("Usage: %s <port> [udp]", os.Args[0])
 		os.Exit(1)
 	}
 	port := os.Args[1]
 
+	if len(os.Args) > 2 && os.Args[2] == "udp" {
+		log.Fatal(serveUDP(port))
+	}
+
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "Response from server on port %s", port)
 	})
@@ -31,6 +36,27 @@ func main() {
 	log.Fatal(server.ListenAndServe())
 }
 
+// serveUDP replies to every datagram with the same response as the HTTP server
+func serveUDP(port string) error {
+	conn, err := net.ListenPacket("udp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 1024)
+	for {
+		_, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+
+		if _, err := conn.WriteTo([]byte(fmt.Sprintf("Response from server on port %s", port)), addr); err != nil {
+			return err
+		}
+	}
+}
+
 func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	_, err := io.Copy(io.Discard, r.Body)
 	if err != nil {
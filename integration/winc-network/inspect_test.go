@@ -0,0 +1,64 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"code.cloudfoundry.org/winc/network"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inspect", func() {
+	var upOutputs network.UpOutputs
+
+	BeforeEach(func() {
+		bundleSpec := helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+
+		helpers.RunContainer(bundleSpec, bundlePath, containerId)
+		networkConfig = helpers.GenerateNetworkConfig()
+		networkConfig.DNSServers = []string{"8.8.8.8"}
+		networkConfig.MTU = 1405
+		helpers.CreateNetwork(networkConfig, networkConfigFile)
+
+		upOutputs = helpers.NetworkUp(containerId, `{"Pid": 123, "Properties": {}, "netin": [{"host_port": 0, "container_port": 8080}]}`, networkConfigFile)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		deleteContainerAndNetwork(containerId, networkConfig)
+	})
+
+	It("prints the effective network config of the container", func() {
+		cmd := exec.Command(wincNetworkBin, "--configFile", networkConfigFile, "--action", "inspect", "--handle", containerId)
+		output, err := cmd.Output()
+		Expect(err).NotTo(HaveOccurred())
+
+		var inspectOutputs network.InspectOutputs
+		Expect(json.Unmarshal(output, &inspectOutputs)).To(Succeed())
+
+		Expect(allEndpoints(containerId)).To(ConsistOf(inspectOutputs.EndpointID))
+		Expect(inspectOutputs.IPAddress).To(Equal(upOutputs.Properties.ContainerIP))
+		Expect(inspectOutputs.MACAddress).NotTo(BeEmpty())
+		Expect(inspectOutputs.DNSServers).To(Equal([]string{"8.8.8.8"}))
+		Expect(inspectOutputs.MTU).To(Equal(1405))
+
+		var mappedPorts []struct{ HostPort uint16 }
+		Expect(json.Unmarshal([]byte(upOutputs.Properties.MappedPorts), &mappedPorts)).To(Succeed())
+		Expect(inspectOutputs.AllocatedPorts["tcp"]).To(ConsistOf(mappedPorts[0].HostPort))
+
+		Expect(inspectOutputs.Policies).To(ContainElement(ContainSubstring("NAT tcp host port")))
+		Expect(inspectOutputs.Policies).To(ContainElement(ContainSubstring("ACL allow out udp")))
+	})
+
+	Context("when the endpoint does not exist", func() {
+		It("errors", func() {
+			cmd := exec.Command(wincNetworkBin, "--configFile", networkConfigFile, "--action", "inspect", "--handle", "some-nonexistant-id")
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+			Expect(strings.TrimSpace(string(output))).To(ContainSubstring("networkInspect:"))
+		})
+	})
+})
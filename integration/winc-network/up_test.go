@@ -15,6 +15,7 @@ import (
 	"code.cloudfoundry.org/localip"
 	"code.cloudfoundry.org/winc/network/netinterface"
 	"code.cloudfoundry.org/winc/network/netrules"
+	"github.com/Microsoft/hcsshim"
 	"golang.org/x/sys/windows"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -68,6 +69,16 @@ var _ = Describe("Up", func() {
 			Expect(containerMtu).To(Equal(hostMtu))
 		})
 
+		It("reports the endpoint in the container's state", func() {
+			helpers.NetworkUp(containerId, `{"Pid": 123, "Properties": {} ,"netin": []}`, networkConfigFile)
+
+			endpoint, err := hcsshim.GetHNSEndpointByName(containerId)
+			Expect(err).ToNot(HaveOccurred())
+
+			state := helpers.GetContainerState(containerId)
+			Expect(state.Annotations).To(HaveKeyWithValue("org.cloudfoundry.winc.endpoint-id", endpoint.Id))
+		})
+
 		Context("stdin contains a net in rule", func() {
 			var (
 				hostPort1      uint16
@@ -134,6 +145,48 @@ var _ = Describe("Up", func() {
 				Expect(string(data)).To(Equal(fmt.Sprintf("Response from server on port %d", containerPort2)))
 			})
 
+			Context("the net in rule is for UDP", func() {
+				var udpContainerPort uint16
+
+				BeforeEach(func() {
+					udpContainerPort = 5353
+
+					_, _, err := helpers.ExecInContainer(containerId, []string{"c:\\server.exe", strconv.Itoa(int(udpContainerPort)), "udp"}, true)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("maps the port for UDP and forwards datagrams to the container", func() {
+					outputs := helpers.NetworkUp(containerId, fmt.Sprintf(`{"Pid": 123, "Properties": {} ,"netin": [{"host_port": 0, "container_port": %d, "protocol": "udp"}]}`, udpContainerPort), networkConfigFile)
+
+					mappedPorts := []netrules.PortMapping{}
+					Expect(json.Unmarshal([]byte(outputs.Properties.MappedPorts), &mappedPorts)).To(Succeed())
+
+					Expect(mappedPorts).To(HaveLen(1))
+					Expect(mappedPorts[0].ContainerPort).To(Equal(udpContainerPort))
+					Expect(mappedPorts[0].Protocol).To(Equal("udp"))
+
+					hostIP, err := localip.LocalIP()
+					Expect(err).NotTo(HaveOccurred())
+
+					address := fmt.Sprintf("%s:%d", hostIP, mappedPorts[0].HostPort)
+					Eventually(func() (string, error) {
+						return udpRequest(address)
+					}, "30s").Should(Equal(fmt.Sprintf("Response from server on port %d", udpContainerPort)))
+				})
+
+				It("maps the same host port for TCP and UDP when both are requested", func() {
+					outputs := helpers.NetworkUp(containerId, fmt.Sprintf(`{"Pid": 123, "Properties": {} ,"netin": [{"host_port": 0, "container_port": %d, "protocol": "both"}]}`, udpContainerPort), networkConfigFile)
+
+					mappedPorts := []netrules.PortMapping{}
+					Expect(json.Unmarshal([]byte(outputs.Properties.MappedPorts), &mappedPorts)).To(Succeed())
+
+					Expect(mappedPorts).To(HaveLen(2))
+					Expect(mappedPorts[0].Protocol).To(Equal("tcp"))
+					Expect(mappedPorts[1].Protocol).To(Equal("udp"))
+					Expect(mappedPorts[0].HostPort).To(Equal(mappedPorts[1].HostPort))
+				})
+			})
+
 			It("can hit a port on the container directly", func() {
 				helpers.NetworkUp(containerId, fmt.Sprintf(`{"Pid": 123, "Properties": {} ,"netin": [{"host_port": %d, "container_port": %d},{"host_port": %d, "container_port": %d}]}`, hostPort1, containerPort1, hostPort2, containerPort2), networkConfigFile)
 
@@ -505,8 +558,10 @@ var _ = Describe("Up", func() {
 			helpers.RunContainer(bundleSpec, bundlePath, containerId)
 			networkConfig = helpers.GenerateNetworkConfig()
 			networkConfig.MTU = 1405
-			helpers.CreateNetwork(networkConfig, networkConfigFile)
+		})
 
+		JustBeforeEach(func() {
+			helpers.CreateNetwork(networkConfig, networkConfigFile)
 		})
 
 		AfterEach(func() {
@@ -521,6 +576,27 @@ var _ = Describe("Up", func() {
 
 			Expect(containerMtu).To(Equal(uint32(1405)))
 		})
+
+		Context("the network is dual-stack", func() {
+			BeforeEach(func() {
+				// derive the IPv6 subnet from the IPv4 gateway so that it's as unique as the NAT network
+				gateway := net.ParseIP(networkConfig.GatewayAddress).To4()
+				networkConfig.SubnetRangeIPv6 = fmt.Sprintf("fd00:%02x%02x:%02x%02x::/64", gateway[0], gateway[1], gateway[2], gateway[3])
+				networkConfig.GatewayAddressIPv6 = fmt.Sprintf("fd00:%02x%02x:%02x%02x::1", gateway[0], gateway[1], gateway[2], gateway[3])
+			})
+
+			It("sets the network MTU on both of the container's interfaces", func() {
+				helpers.NetworkUp(containerId, `{"Pid": 123, "Properties": {} ,"netin": []}`, networkConfigFile)
+
+				containerMtu, err := n.GetMTU(fmt.Sprintf("vEthernet (%s)", containerId), windows.AF_INET)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(containerMtu).To(Equal(uint32(1405)))
+
+				containerMtu, err = n.GetMTU(fmt.Sprintf("vEthernet (%s)", containerId), windows.AF_INET6)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(containerMtu).To(Equal(uint32(1405)))
+			})
+		})
 	})
 
 	Context("custom DNS Servers", func() {
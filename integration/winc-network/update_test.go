@@ -0,0 +1,102 @@
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/winc/network"
+	"code.cloudfoundry.org/winc/network/netrules"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Update", func() {
+	var (
+		udpRule     netrules.NetOut
+		tcpRule     netrules.NetOut
+		mappedPorts string
+		endpointIDs []string
+	)
+
+	BeforeEach(func() {
+		bundleSpec := helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+
+		helpers.RunContainer(bundleSpec, bundlePath, containerId)
+		networkConfig = helpers.GenerateNetworkConfig()
+		helpers.CreateNetwork(networkConfig, networkConfigFile)
+
+		pid := helpers.GetContainerState(containerId).Pid
+		helpers.CopyFile(filepath.Join("c:\\", "proc", strconv.Itoa(pid), "root", "netout.exe"), netoutBin)
+
+		networks := []netrules.IPRange{{Start: net.ParseIP("8.8.5.5"), End: net.ParseIP("9.0.0.0")}}
+		ports := []netrules.PortRange{{Start: 40, End: 60}}
+		udpRule = netrules.NetOut{Protocol: netrules.ProtocolUDP, Networks: networks, Ports: ports}
+		tcpRule = netrules.NetOut{Protocol: netrules.ProtocolTCP, Networks: networks, Ports: ports}
+
+		netOutRules, err := json.Marshal([]netrules.NetOut{udpRule})
+		Expect(err).NotTo(HaveOccurred())
+
+		outputs := helpers.NetworkUp(containerId, fmt.Sprintf(`{"Pid": 123, "Properties": {}, "netin": [{"host_port": 0, "container_port": 8080}], "netout_rules": %s}`, string(netOutRules)), networkConfigFile)
+		mappedPorts = outputs.Properties.MappedPorts
+		endpointIDs = allEndpoints(containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		deleteContainerAndNetwork(containerId, networkConfig)
+	})
+
+	It("applies the new net out rules without recreating the endpoint", func() {
+		stdout, _, err := helpers.ExecInContainer(containerId, []string{"c:\\netout.exe", "--protocol", "tcp", "--addr", "8.8.8.8", "--port", "53"}, false)
+		Expect(err).To(HaveOccurred(), stdout.String())
+
+		netOutRules, err := json.Marshal([]netrules.NetOut{tcpRule})
+		Expect(err).NotTo(HaveOccurred())
+
+		helpers.NetworkUpdate(containerId, fmt.Sprintf(`{"Pid": 123, "Properties": {}, "netin": [{"host_port": 0, "container_port": 8080}], "netout_rules": %s}`, string(netOutRules)), networkConfigFile)
+
+		stdout, _, err = helpers.ExecInContainer(containerId, []string{"c:\\netout.exe", "--protocol", "tcp", "--addr", "8.8.8.8", "--port", "53"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.TrimSpace(stdout.String())).To(Equal("connected to 8.8.8.8:53 over tcp"))
+
+		stdout, _, err = helpers.ExecInContainer(containerId, []string{"c:\\netout.exe", "--protocol", "udp", "--addr", "8.8.8.8", "--port", "53"}, false)
+		Expect(err).To(HaveOccurred())
+		Expect(stdout.String()).To(ContainSubstring("8.8.8.8:53: i/o timeout"))
+
+		Expect(allEndpoints(containerId)).To(Equal(endpointIDs))
+	})
+
+	It("keeps the existing port mappings", func() {
+		outputs := helpers.NetworkUpdate(containerId, `{"Pid": 123, "Properties": {}, "netin": [{"host_port": 0, "container_port": 8080}]}`, networkConfigFile)
+		Expect(outputs.Properties.MappedPorts).To(Equal(mappedPorts))
+	})
+
+	It("releases the host ports of the port mappings it removes", func() {
+		outputs := helpers.NetworkUpdate(containerId, `{"Pid": 123, "Properties": {}, "netin": []}`, networkConfigFile)
+		Expect(outputs.Properties.MappedPorts).To(Equal("[]"))
+
+		cmd := exec.Command(wincNetworkBin, "--configFile", networkConfigFile, "--action", "inspect", "--handle", containerId)
+		output, err := cmd.Output()
+		Expect(err).NotTo(HaveOccurred())
+
+		var inspectOutputs network.InspectOutputs
+		Expect(json.Unmarshal(output, &inspectOutputs)).To(Succeed())
+		Expect(inspectOutputs.AllocatedPorts["tcp"]).To(BeEmpty())
+	})
+
+	Context("when the endpoint does not exist", func() {
+		It("errors", func() {
+			cmd := exec.Command(wincNetworkBin, "--configFile", networkConfigFile, "--action", "update", "--handle", "some-nonexistant-id")
+			cmd.Stdin = strings.NewReader(`{"Pid": 123, "Properties": {}}`)
+			output, err := cmd.CombinedOutput()
+			Expect(err).To(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring("networkUpdate:"))
+		})
+	})
+})
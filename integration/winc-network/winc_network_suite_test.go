@@ -217,3 +217,28 @@ func httpGetInto(address string, resp *http.Response) func() error {
 		return err
 	}
 }
+
+// udpRequest sends a datagram to address and returns the reply
+func udpRequest(address string) (string, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		return "", err
+	}
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf[:n]), nil
+}
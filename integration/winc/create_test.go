@@ -99,6 +99,20 @@ var _ = Describe("Create", func() {
 			})
 		})
 
+		Context("when the bundle config.json requests a terminal", func() {
+			BeforeEach(func() {
+				bundleSpec.Process.Terminal = true
+			})
+
+			It("errors without creating the container", func() {
+				helpers.GenerateBundle(bundleSpec, bundlePath)
+				_, stdErr, err := helpers.Execute(exec.Command(wincBin, "create", "-b", bundlePath, containerId))
+				Expect(err).To(HaveOccurred())
+				Expect(stdErr.String()).To(ContainSubstring("cannot allocate a tty for a container that is created without a console socket"))
+				Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+			})
+		})
+
 		Context("when the bundle config.json specifies bind mounts", func() {
 			var (
 				mountSource string
@@ -181,6 +195,35 @@ var _ = Describe("Create", func() {
 				})
 			})
 
+			Context("unknown mount options are specified", func() {
+				BeforeEach(func() {
+					bundleSpec.Mounts[0].Options = []string{"bind", "noexec", "ro", "nosuid"}
+				})
+
+				It("errors listing the unknown options", func() {
+					helpers.GenerateBundle(bundleSpec, bundlePath)
+					_, stdErr, err := helpers.Execute(exec.Command(wincBin, "create", "-b", bundlePath, containerId))
+					Expect(err).To(HaveOccurred())
+					Expect(stdErr.String()).To(ContainSubstring(fmt.Sprintf("unknown mount options for container %s: noexec, nosuid", containerId)))
+				})
+			})
+
+			Context("the create=dir mount option is specified and the source does not exist", func() {
+				BeforeEach(func() {
+					bundleSpec.Mounts[0].Source = filepath.Join(mountSource, "missing")
+					bundleSpec.Mounts[0].Options = []string{"bind", "rw", "create=dir"}
+				})
+
+				It("creates the source and mounts it", func() {
+					helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+					Expect(filepath.Join(mountSource, "missing")).To(BeADirectory())
+
+					_, _, err := helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "echo hello > " + filepath.Join(mountDest, "created")}, false)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(filepath.Join(mountSource, "missing", "created")).To(BeAnExistingFile())
+				})
+			})
+
 			Context("the source of the bind mount is a symlink", func() {
 				var symlinkDir string
 
@@ -275,43 +318,73 @@ var _ = Describe("Create", func() {
 			})
 
 			Context("when a file is supplied as a mount", func() {
-				var (
-					logFile   string
-					mountFile string
-				)
+				var mountFile string
 
 				BeforeEach(func() {
-					l, err := os.CreateTemp("", "winc.log")
-					Expect(err).ToNot(HaveOccurred())
-					Expect(l.Close()).To(Succeed())
-					logFile = l.Name()
-
 					m, err := os.CreateTemp("", "mountfile")
 					Expect(err).ToNot(HaveOccurred())
+					_, err = m.WriteString("hey-winc")
+					Expect(err).ToNot(HaveOccurred())
 					Expect(m.Close()).To(Succeed())
 					mountFile = m.Name()
 
 					bundleSpec.Mounts = append(bundleSpec.Mounts, specs.Mount{
 						Source:      mountFile,
-						Destination: "C:\\foobar",
+						Destination: "C:\\foobar\\config.txt",
 					})
 				})
 
 				AfterEach(func() {
-					Expect(os.RemoveAll(logFile)).To(Succeed())
 					Expect(os.RemoveAll(mountFile)).To(Succeed())
 				})
 
-				It("ignores it and logs that it did so", func() {
-					helpers.GenerateBundle(bundleSpec, bundlePath)
-					stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "--debug", "--log", logFile, "create", "-b", bundlePath, containerId))
+				It("mounts just that file at the destination", func() {
+					helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+
+					stdOut, stdErr, err := helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "type C:\\foobar\\config.txt"}, false)
 					Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+					Expect(stdOut.String()).To(ContainSubstring("hey-winc"))
 
-					contents, err := os.ReadFile(logFile)
-					Expect(err).ToNot(HaveOccurred())
-					Expect(string(contents)).To(ContainSubstring("mount is not a directory, ignoring"))
-					Expect(string(contents)).To(ContainSubstring(fmt.Sprintf(`"mount":"%s"`, strings.Replace(mountFile, `\`, `\\`, -1))))
+					stdOut, stdErr, err = helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "dir /B C:\\foobar"}, false)
+					Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+					Expect(strings.TrimSpace(stdOut.String())).To(Equal("config.txt"))
 				})
+
+				Context("when the file is mounted at the root of a drive", func() {
+					BeforeEach(func() {
+						bundleSpec.Mounts[len(bundleSpec.Mounts)-1].Destination = "C:\\config.txt"
+					})
+
+					It("errors and does not create the container", func() {
+						helpers.GenerateBundle(bundleSpec, bundlePath)
+						stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "create", "-b", bundlePath, containerId))
+						Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+						Expect(stdErr.String()).To(ContainSubstring("a file cannot be mounted at the root of a drive"))
+
+						Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+					})
+				})
+			})
+		})
+
+		Context("when the bundle config.json specifies cpu and storage limits", func() {
+			BeforeEach(func() {
+				count := uint64(1)
+				iops := uint64(1000)
+				bps := uint64(50 * 1024 * 1024)
+				bundleSpec.Windows.Resources = &specs.WindowsResources{
+					CPU:     &specs.WindowsCPUResources{Count: &count},
+					Storage: &specs.WindowsStorageResources{Iops: &iops, Bps: &bps},
+				}
+			})
+
+			It("creates and starts a container", func() {
+				helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+				Expect(helpers.ContainerExists(containerId)).To(BeTrue())
+
+				stdOut, stdErr, err := helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "echo hey-winc"}, false)
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdOut.String()).To(ContainSubstring("hey-winc"))
 			})
 		})
 
@@ -354,6 +427,28 @@ var _ = Describe("Create", func() {
 		})
 	})
 
+	Context("when the bundle config.json specifies a cpu count and a cpu maximum", func() {
+		BeforeEach(func() {
+			count := uint64(1)
+			maximum := uint16(5000)
+			bundleSpec.Windows.Resources = &specs.WindowsResources{
+				CPU: &specs.WindowsCPUResources{
+					Count:   &count,
+					Maximum: &maximum,
+				},
+			}
+		})
+
+		It("errors and does not create the container", func() {
+			helpers.GenerateBundle(bundleSpec, bundlePath)
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "create", "-b", bundlePath, containerId))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cpu count and cpu maximum cannot both be set"))
+
+			Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+		})
+	})
+
 	Context("when the mount source does not exist", func() {
 		BeforeEach(func() {
 			mountDest := "C:\\mnt"
@@ -57,6 +57,25 @@ var _ = Describe("Delete", func() {
 				// if not cleanly unmounted, the mount point is left as a symlink
 				_, err := os.Lstat(rootPath)
 				Expect(err).NotTo(BeNil())
+				Expect(filepath.Join("c:\\", "proc", "containers", containerId)).NotTo(BeADirectory())
+			})
+
+			Context("when the state file is corrupt", func() {
+				BeforeEach(func() {
+					stateFile := filepath.Join("C:\\", "ProgramData", "winc", containerId, "state.json")
+					Expect(os.WriteFile(stateFile, []byte(`{"version":1,"bun`), 0644)).To(Succeed())
+				})
+
+				It("still deletes the container and its state", func() {
+					helpers.DeleteContainer(containerId)
+					Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+					Expect(filepath.Join("C:\\", "ProgramData", "winc", containerId)).NotTo(BeADirectory())
+				})
+
+				It("unmounts sandbox.vhdx without knowing the pid", func() {
+					helpers.DeleteContainer(containerId)
+					Expect(filepath.Join("c:\\", "proc", "containers", containerId)).NotTo(BeADirectory())
+				})
 			})
 
 			Context("when passed the -force flag", func() {
@@ -67,6 +86,25 @@ var _ = Describe("Delete", func() {
 					Expect(helpers.ContainerExists(containerId)).To(BeFalse())
 				})
 			})
+
+			Context("when passed the --pre-stop and --timeout flags", func() {
+				It("deletes the container", func() {
+					cmd := exec.Command(wincBin, "delete", "--pre-stop", "--timeout", "10s", containerId)
+					stdOut, stdErr, err := helpers.Execute(cmd)
+					Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+					Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+				})
+			})
+
+			Context("when passed a negative timeout", func() {
+				It("errors without deleting the container", func() {
+					cmd := exec.Command(wincBin, "delete", "--timeout", "-1s", containerId)
+					stdOut, stdErr, err := helpers.Execute(cmd)
+					Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+					Expect(stdErr.String()).To(ContainSubstring("invalid timeout: -1s"))
+					Expect(helpers.ContainerExists(containerId)).To(BeTrue())
+				})
+			})
 		})
 	})
 
@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -45,10 +46,29 @@ var _ = Describe("Events", func() {
 		})
 
 		Context("when the container has been created", func() {
-			It("exits without error", func() {
-				cmd := exec.Command(wincBin, "events", containerId)
-				stdOut, stdErr, err := helpers.Execute(cmd)
-				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+			It("streams stats until the container exits", func() {
+				cmd := exec.Command(wincBin, "events", "--interval", "1s", containerId)
+				stdOut, err := cmd.StdoutPipe()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cmd.Start()).To(Succeed())
+
+				decoder := json.NewDecoder(stdOut)
+				var event struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				}
+				Expect(decoder.Decode(&event)).To(Succeed())
+				Expect(event.Type).To(Equal("stats"))
+				Expect(event.ID).To(Equal(containerId))
+
+				killOut, killErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "KILL"))
+				Expect(err).NotTo(HaveOccurred(), killOut.String(), killErr.String())
+
+				Eventually(func() string {
+					Expect(decoder.Decode(&event)).To(Succeed())
+					return event.Type
+				}, "30s").Should(Equal("exit"))
+				Expect(cmd.Wait()).To(Succeed())
 			})
 
 			Context("when passed the --stats flag", func() {
@@ -130,12 +150,22 @@ var _ = Describe("Events", func() {
 
 	Context("given a nonexistent container id", func() {
 		It("errors", func() {
-			cmd := exec.Command(wincBin, "events", "doesntexist")
+			cmd := exec.Command(wincBin, "events", "--stats", "doesntexist")
 			stdOut, stdErr, err := helpers.Execute(cmd)
 			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
 
 			Expect(stdErr.String()).To(ContainSubstring("hcs::OpenComputeSystem doesntexist"))
 			Expect(stdErr.String()).To(ContainSubstring("the specified identifier does not exist"))
 		})
+
+		Context("when streaming events", func() {
+			It("errors", func() {
+				cmd := exec.Command(wincBin, "events", "doesntexist")
+				stdOut, stdErr, err := helpers.Execute(cmd)
+				Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+
+				Expect(stdErr.String()).To(ContainSubstring("container not found: doesntexist"))
+			})
+		})
 	})
 })
@@ -3,6 +3,7 @@ package main_test
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -107,6 +108,48 @@ var _ = Describe("Exec", func() {
 				Expect(len(pl)).To(Equal(1))
 			})
 
+			It("runs the command line from the process.json as is", func() {
+				expectedSpec := processSpecGenerator()
+				expectedSpec.Args = nil
+				expectedSpec.CommandLine = `cmd.exe /C "echo hey-winc"`
+				config, err := json.Marshal(&expectedSpec)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(os.WriteFile(processConfig, config, 0666)).To(Succeed())
+
+				args := []string{"exec", "--process", processConfig, containerId}
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, args...))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdOut.String()).To(ContainSubstring("hey-winc"))
+			})
+
+			It("runs the args passed on the command line instead of the command line from the process.json", func() {
+				expectedSpec := processSpecGenerator()
+				expectedSpec.Args = nil
+				expectedSpec.CommandLine = `cmd.exe /C "echo hey-winc"`
+				config, err := json.Marshal(&expectedSpec)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(os.WriteFile(processConfig, config, 0666)).To(Succeed())
+
+				args := []string{"exec", "--process", processConfig, containerId, "cmd.exe", "/C", "echo from-args"}
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, args...))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdOut.String()).To(ContainSubstring("from-args"))
+				Expect(stdOut.String()).NotTo(ContainSubstring("hey-winc"))
+			})
+
+			It("rejects settings that are not supported on Windows", func() {
+				expectedSpec := processSpecGenerator()
+				expectedSpec.NoNewPrivileges = true
+				config, err := json.Marshal(&expectedSpec)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(os.WriteFile(processConfig, config, 0666)).To(Succeed())
+
+				args := []string{"exec", "--process", processConfig, containerId}
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, args...))
+				Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdErr.String()).To(ContainSubstring("noNewPrivileges is not supported on Windows"))
+			})
+
 			It("cleans errors returned from hcsshim", func() {
 				expectedSpec := processSpecGenerator()
 				expectedSpec.Args = []string{"some-invalid-command"}
@@ -233,6 +276,73 @@ var _ = Describe("Exec", func() {
 
 		})
 
+		Context("when the '--tty' flag is provided", func() {
+			It("runs the process with a console and merges stderr into stdout", func() {
+				cmd := exec.Command(wincBin, "exec", "--tty", containerId, "cmd.exe", "/C", "echo hey-winc 1>&2")
+				stdOut, stdErr, err := helpers.Execute(cmd)
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdOut.String()).To(ContainSubstring("hey-winc"))
+				Expect(stdErr.String()).NotTo(ContainSubstring("hey-winc"))
+			})
+
+			It("fails when detaching", func() {
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "exec", "--tty", "--detach", containerId, "cmd.exe"))
+				Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdErr.String()).To(ContainSubstring("cannot allocate a tty for a detached process"))
+			})
+
+			Context("when the '--console-socket' flag is provided", func() {
+				var (
+					socketDir string
+					listener  net.Listener
+				)
+
+				BeforeEach(func() {
+					var err error
+					socketDir, err = os.MkdirTemp("", "console-socket")
+					Expect(err).NotTo(HaveOccurred())
+
+					listener, err = net.Listen("unix", filepath.Join(socketDir, "console.sock"))
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				AfterEach(func() {
+					Expect(listener.Close()).To(Succeed())
+					Expect(os.RemoveAll(socketDir)).To(Succeed())
+				})
+
+				It("streams the console over the socket and can be resized", func() {
+					cmd := exec.Command(wincBin, "exec", "--tty", "--console-socket", filepath.Join(socketDir, "console.sock"), containerId, "powershell.exe", "-NoLogo")
+					session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+					Expect(err).NotTo(HaveOccurred())
+
+					conn, err := listener.Accept()
+					Expect(err).NotTo(HaveOccurred())
+					defer conn.Close()
+					console := gbytes.BufferReader(conn)
+
+					Eventually(func() []hcsshim.ProcessListItem {
+						return helpers.ContainerProcesses(containerId, "powershell.exe")
+					}, "10s").Should(HaveLen(1))
+					pl := helpers.ContainerProcesses(containerId, "powershell.exe")
+
+					stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "resize", "--pid", strconv.Itoa(int(pl[0].ProcessId)), "--width", "100", "--height", "30", containerId))
+					Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+					_, err = conn.Write([]byte("$Host.UI.RawUI.WindowSize.Width; exit 3\r\n"))
+					Expect(err).NotTo(HaveOccurred())
+					Eventually(console, "10s").Should(gbytes.Say("100"))
+					Eventually(session, "10s").Should(gexec.Exit(3))
+				})
+
+				It("errors rather than truncating a console size that is out of range", func() {
+					stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "resize", "--width", "65636", "--height", "30", containerId))
+					Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+					Expect(stdErr.String()).To(ContainSubstring("width 65636 must be between 1 and 65535"))
+				})
+			})
+		})
+
 		Context("when the '--pid-file' flag is provided", func() {
 			var pidFile string
 
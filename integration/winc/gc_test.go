@@ -0,0 +1,182 @@
+package main_test
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("GC", func() {
+	type gcReport struct {
+		DryRun         bool     `json:"dry_run"`
+		ComputeSystems []string `json:"compute_systems"`
+		StateDirs      []string `json:"state_dirs"`
+		Mounts         []string `json:"mounts"`
+		LegacyMounts   []int    `json:"legacy_mounts"`
+		FileMounts     []string `json:"file_mounts"`
+	}
+
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+		mountDir    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = os.MkdirTemp("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		helpers.StartContainer(containerId)
+		mountDir = filepath.Join("C:\\", "proc", "containers", containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	gc := func(args ...string) gcReport {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, append([]string{"gc"}, args...)...))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		var report gcReport
+		Expect(json.Unmarshal(stdOut.Bytes(), &report)).To(Succeed())
+		return report
+	}
+
+	It("leaves containers that aren't orphaned alone", func() {
+		report := gc()
+		Expect(report.ComputeSystems).NotTo(ContainElement(containerId))
+		Expect(report.StateDirs).NotTo(ContainElement(containerId))
+		Expect(report.Mounts).NotTo(ContainElement(containerId))
+		Expect(helpers.ContainerExists(containerId)).To(BeTrue())
+	})
+
+	Context("gc is run for another root dir", func() {
+		var otherRoot string
+
+		BeforeEach(func() {
+			var err error
+			otherRoot, err = os.MkdirTemp("", "winc-other-root")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(otherRoot)).To(Succeed())
+		})
+
+		It("leaves the container alone", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "--root", otherRoot, "gc"))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			var report gcReport
+			Expect(json.Unmarshal(stdOut.Bytes(), &report)).To(Succeed())
+			Expect(report.ComputeSystems).To(BeEmpty())
+
+			Expect(helpers.ContainerExists(containerId)).To(BeTrue())
+			Expect(mountDir).To(BeADirectory())
+		})
+	})
+
+	Context("a create crashed after linking its file mounts", func() {
+		var fileMountsDir string
+
+		BeforeEach(func() {
+			fileMountsDir = filepath.Join("C:\\", "proc", "file-mounts", containerId+"-crashed")
+			Expect(os.MkdirAll(filepath.Join(fileMountsDir, "0"), 0755)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(fileMountsDir)).To(Succeed())
+		})
+
+		It("removes the file mounts dir", func() {
+			report := gc()
+			Expect(report.FileMounts).To(ContainElement(containerId + "-crashed"))
+			Expect(report.FileMounts).NotTo(ContainElement(containerId))
+			Expect(fileMountsDir).NotTo(BeADirectory())
+		})
+	})
+
+	Context("an older version of winc left a volume mount made by pid", func() {
+		var (
+			legacyPid int
+			legacyDir string
+		)
+
+		BeforeEach(func() {
+			// pids are multiples of 4, so no process has this one
+			legacyPid = math.MaxInt32 - 3
+			legacyDir = filepath.Join("C:\\", "proc", strconv.Itoa(legacyPid))
+			Expect(os.MkdirAll(filepath.Join(legacyDir, "root"), 0755)).To(Succeed())
+
+			outBytes, err := exec.Command("mountvol", "C:\\", "/L").CombinedOutput()
+			Expect(err).NotTo(HaveOccurred())
+			volumeGuid := strings.TrimSpace(string(outBytes))
+			Expect(exec.Command("mountvol", filepath.Join(legacyDir, "root"), volumeGuid).Run()).To(Succeed())
+		})
+
+		AfterEach(func() {
+			if err := exec.Command("mountvol", filepath.Join(legacyDir, "root"), "/L").Run(); err == nil {
+				Expect(exec.Command("mountvol", filepath.Join(legacyDir, "root"), "/D").Run()).To(Succeed())
+			}
+			Expect(os.RemoveAll(legacyDir)).To(Succeed())
+		})
+
+		It("removes it", func() {
+			report := gc()
+			Expect(report.LegacyMounts).To(ContainElement(legacyPid))
+			Expect(legacyDir).NotTo(BeADirectory())
+		})
+	})
+
+	Context("the state dir of a running container has been lost", func() {
+		BeforeEach(func() {
+			Expect(os.RemoveAll(filepath.Join("C:\\", "ProgramData", "winc", containerId))).To(Succeed())
+		})
+
+		It("removes the compute system and the volume mount", func() {
+			report := gc()
+			Expect(report.DryRun).To(BeFalse())
+			Expect(report.ComputeSystems).To(ContainElement(containerId))
+			Expect(report.Mounts).To(ContainElement(containerId))
+
+			Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+			Expect(mountDir).NotTo(BeADirectory())
+		})
+
+		Context("when passed --dry-run", func() {
+			It("reports them without removing them", func() {
+				report := gc("--dry-run")
+				Expect(report.DryRun).To(BeTrue())
+				Expect(report.ComputeSystems).To(ContainElement(containerId))
+				Expect(report.Mounts).To(ContainElement(containerId))
+
+				Expect(helpers.ContainerExists(containerId)).To(BeTrue())
+				Expect(mountDir).To(BeADirectory())
+
+				gc()
+			})
+		})
+	})
+})
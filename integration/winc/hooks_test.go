@@ -0,0 +1,127 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Hooks", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+		hookDir     string
+		powershell  string
+	)
+
+	hook := func(outputFile string) specs.Hook {
+		return specs.Hook{
+			Path: powershell,
+			Args: []string{"powershell.exe", "-NoProfile", "-Command", "[Console]::In.ReadToEnd() | Set-Content -NoNewline -Path " + filepath.Join(hookDir, outputFile)},
+		}
+	}
+
+	readHookState := func(outputFile string) specs.State {
+		var state specs.State
+		contents, err := os.ReadFile(filepath.Join(hookDir, outputFile))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(contents, &state)).To(Succeed())
+		return state
+	}
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = os.MkdirTemp("", "winccontainer")
+		Expect(err).To(Succeed())
+		hookDir, err = os.MkdirTemp("", "winchooks")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+		powershell = filepath.Join(os.Getenv("SystemRoot"), "System32", "WindowsPowerShell", "v1.0", "powershell.exe")
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+		Expect(os.RemoveAll(hookDir)).To(Succeed())
+	})
+
+	It("runs the hooks on the host at each point in the container lifecycle", func() {
+		bundleSpec.Hooks = &specs.Hooks{
+			CreateRuntime: []specs.Hook{hook("createRuntime.json")},
+			Poststart:     []specs.Hook{hook("poststart.json")},
+			Poststop:      []specs.Hook{hook("poststop.json")},
+		}
+
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		createRuntime := readHookState("createRuntime.json")
+		Expect(createRuntime.ID).To(Equal(containerId))
+		Expect(createRuntime.Status).To(Equal(specs.StateCreating))
+		Expect(createRuntime.Bundle).To(Equal(bundlePath))
+
+		helpers.StartContainer(containerId)
+		poststart := readHookState("poststart.json")
+		Expect(poststart.Status).To(Equal(specs.StateRunning))
+		Expect(poststart.Pid).To(Equal(helpers.GetContainerState(containerId).Pid))
+
+		helpers.DeleteContainer(containerId)
+		poststop := readHookState("poststop.json")
+		Expect(poststop.Status).To(Equal(specs.StateStopped))
+	})
+
+	Context("when a poststart hook fails", func() {
+		BeforeEach(func() {
+			bundleSpec.Hooks = &specs.Hooks{
+				Poststart: []specs.Hook{
+					{
+						Path: powershell,
+						Args: []string{"powershell.exe", "-NoProfile", "-Command", "exit 1"},
+					},
+					hook("poststart.json"),
+				},
+			}
+		})
+
+		It("runs the remaining hooks and leaves the container running", func() {
+			helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+			helpers.StartContainer(containerId)
+
+			poststart := readHookState("poststart.json")
+			Expect(poststart.Status).To(Equal(specs.StateRunning))
+			Expect(helpers.GetContainerState(containerId).Status).To(Equal(specs.StateRunning))
+		})
+	})
+
+	Context("when a createRuntime hook fails", func() {
+		BeforeEach(func() {
+			bundleSpec.Hooks = &specs.Hooks{
+				CreateRuntime: []specs.Hook{{
+					Path: powershell,
+					Args: []string{"powershell.exe", "-NoProfile", "-Command", "exit 1"},
+				}},
+			}
+			helpers.GenerateBundle(bundleSpec, bundlePath)
+		})
+
+		It("fails to create the container and cleans it up", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "create", "-b", bundlePath, containerId))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("running createRuntime hooks"))
+			Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Kill", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = os.MkdirTemp("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		helpers.StartContainer(containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("stops the container with SIGTERM by default", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		Expect(helpers.GetContainerState(containerId).Status).To(Equal(specs.StateStopped))
+	})
+
+	It("stops the container with SIGKILL", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "KILL"))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		Expect(helpers.GetContainerState(containerId).Status).To(Equal(specs.StateStopped))
+	})
+
+	Context("when passed the --all flag", func() {
+		It("kills every process in the container", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", "--all", containerId, "KILL"))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			Expect(helpers.GetContainerState(containerId).Status).To(Equal(specs.StateStopped))
+		})
+	})
+
+	Context("when passed an unsupported signal", func() {
+		It("errors", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "HUP"))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("unsupported signal: HUP"))
+		})
+	})
+
+	Context("when the container has already been killed", func() {
+		It("errors", func() {
+			_, _, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "KILL"))
+			Expect(err).NotTo(HaveOccurred())
+
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "KILL"))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cannot kill a container in the stopped state"))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("List", func() {
+	type listEntry struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Bundle string `json:"bundle"`
+		Orphan string `json:"orphan"`
+	}
+
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = os.MkdirTemp("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("lists the container", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "list", "--format", "json"))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		var entries []listEntry
+		Expect(json.Unmarshal(stdOut.Bytes(), &entries)).To(Succeed())
+		Expect(entries).To(ContainElement(listEntry{ID: containerId, Status: "created", Bundle: bundlePath}))
+	})
+
+	Context("when passed --quiet", func() {
+		It("only prints the container ids", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "list", "--quiet"))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdOut.String()).To(ContainSubstring(containerId + "\n"))
+			Expect(stdOut.String()).NotTo(ContainSubstring("STATUS"))
+		})
+	})
+})
@@ -0,0 +1,60 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Pause and Resume", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = os.MkdirTemp("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		helpers.StartContainer(containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("pauses and resumes the container", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "pause", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(string(helpers.GetContainerState(containerId).Status)).To(Equal("paused"))
+
+		stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "resume", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(helpers.GetContainerState(containerId).Status).To(Equal(specs.StateRunning))
+	})
+
+	Context("when the container is not paused", func() {
+		It("fails to resume it", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "resume", containerId))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cannot resume a container in the running state"))
+		})
+	})
+})
@@ -0,0 +1,72 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Ps", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = os.MkdirTemp("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		helpers.StartContainer(containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("lists the processes running in the container", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "ps", "--format", "json", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		var processes []struct {
+			Pid   uint32 `json:"pid"`
+			Image string `json:"image"`
+		}
+		Expect(json.Unmarshal(stdOut.Bytes(), &processes)).To(Succeed())
+
+		pid := helpers.GetContainerState(containerId).Pid
+		found := false
+		for _, p := range processes {
+			if int(p.Pid) == pid {
+				found = true
+				Expect(strings.ToLower(p.Image)).To(Equal("cmd.exe"))
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
+	It("prints a table by default", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "ps", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(stdOut.String()).To(HavePrefix("PID"))
+		Expect(stdOut.String()).To(ContainSubstring("waitfor.exe"))
+	})
+})
@@ -133,6 +133,47 @@ var _ = Describe("Start", func() {
 		})
 	})
 
+	Context("the container is hyper-v isolated", func() {
+		BeforeEach(func() {
+			if os.Getenv("WINC_TEST_HYPERV") == "" {
+				Skip("WINC_TEST_HYPERV not set")
+			}
+
+			bundleSpec.Windows.HyperV = &specs.WindowsHyperV{}
+		})
+
+		JustBeforeEach(func() {
+			helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		})
+
+		It("starts the init process in the utility VM and reports it as running", func() {
+			helpers.StartContainer(containerId)
+
+			state := helpers.GetContainerState(containerId)
+			Expect(state.Status).To(Equal(specs.StateRunning))
+			Expect(state.Pid).NotTo(BeZero())
+			Expect(state.Annotations).To(HaveKeyWithValue("org.cloudfoundry.winc.isolation", "hyperv"))
+
+			pl := helpers.ContainerProcesses(containerId, "cmd.exe")
+			Expect(pl).To(HaveLen(1))
+			Expect(pl[0].ProcessId).To(Equal(uint32(state.Pid)))
+		})
+
+		Context("the init process exits", func() {
+			BeforeEach(func() {
+				bundleSpec.Process.Args = []string{"cmd.exe", "/C", "exit 0"}
+			})
+
+			It("reports the container as stopped", func() {
+				helpers.StartContainer(containerId)
+
+				Eventually(func() specs.ContainerState {
+					return helpers.GetContainerState(containerId).Status
+				}).Should(Equal(specs.StateStopped))
+			})
+		})
+	})
+
 	Context("the container has not been created", func() {
 		It("errors", func() {
 			_, stdErr, err := helpers.Execute(exec.Command(wincBin, "start", containerId))
@@ -55,6 +55,23 @@ var _ = Describe("State", func() {
 		})
 	})
 
+	Context("when the bundle config.json has annotations", func() {
+		BeforeEach(func() {
+			bundleSpec.Annotations = map[string]string{"some-key": "some-value"}
+			helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		})
+
+		It("reports them along with the winc annotations", func() {
+			state := helpers.GetContainerState(containerId)
+
+			Expect(state.Annotations).To(HaveKeyWithValue("some-key", "some-value"))
+			Expect(state.Annotations).To(HaveKeyWithValue("org.cloudfoundry.winc.hcs-system-id", containerId))
+			Expect(state.Annotations).To(HaveKeyWithValue("org.cloudfoundry.winc.isolation", "process"))
+			Expect(state.Annotations).To(HaveKeyWithValue("org.cloudfoundry.winc.volume-path", bundleSpec.Root.Path))
+			Expect(state.Annotations).To(HaveKey("org.cloudfoundry.winc.created"))
+		})
+	})
+
 	Context("the init process has already been started and is still running", func() {
 		BeforeEach(func() {
 			bundleSpec.Process = &specs.Process{
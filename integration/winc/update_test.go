@@ -0,0 +1,111 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Update", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = os.MkdirTemp("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		helpers.StartContainer(containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentSpecReport().Failed()
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("updates the limits and reports them in the state", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "update", "--memory", "268435456", "--cpu-shares", "5000", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "state", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		var state struct {
+			Resources specs.WindowsResources `json:"resources"`
+		}
+		Expect(json.Unmarshal(stdOut.Bytes(), &state)).To(Succeed())
+		Expect(*state.Resources.Memory.Limit).To(Equal(uint64(268435456)))
+		Expect(*state.Resources.CPU.Shares).To(Equal(uint16(5000)))
+	})
+
+	Context("when the limits are updated one at a time", func() {
+		It("keeps both in the state", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "update", "--memory", "268435456", containerId))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "update", "--cpu-maximum", "2500", containerId))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "state", containerId))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			var state struct {
+				Resources specs.WindowsResources `json:"resources"`
+			}
+			Expect(json.Unmarshal(stdOut.Bytes(), &state)).To(Succeed())
+			Expect(*state.Resources.Memory.Limit).To(Equal(uint64(268435456)))
+			Expect(*state.Resources.CPU.Maximum).To(Equal(uint16(2500)))
+		})
+
+		It("errors when the second cpu limit conflicts with the first", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "update", "--cpu-shares", "5000", containerId))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "update", "--cpu-maximum", "2500", containerId))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cpu shares and cpu maximum cannot both be set"))
+
+			stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "state", containerId))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			var state struct {
+				Resources specs.WindowsResources `json:"resources"`
+			}
+			Expect(json.Unmarshal(stdOut.Bytes(), &state)).To(Succeed())
+			Expect(*state.Resources.CPU.Shares).To(Equal(uint16(5000)))
+			Expect(state.Resources.CPU.Maximum).To(BeNil())
+		})
+	})
+
+	Context("when the resources are invalid", func() {
+		It("errors", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "update", "--cpu-shares", "5000", "--cpu-maximum", "5000", containerId))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cpu shares and cpu maximum cannot both be set"))
+		})
+
+		It("errors rather than truncating a cpu limit that is out of range", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "update", "--cpu-shares", "65537", containerId))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cpu-shares 65537 must be between 1 and 10000"))
+		})
+	})
+})
@@ -79,6 +79,14 @@ func (e *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
 		return hcsshim.HNSEndpoint{}, err
 	}
 
+	if e.config.SubnetRangeIPv6 != "" && attachedEndpoint.IPv6Address == nil {
+		if err := e.Delete(); err != nil {
+			logrus.Error(fmt.Sprintf("Error deleting endpoint %s: %s", attachedEndpoint.Id, err.Error()))
+		}
+
+		return hcsshim.HNSEndpoint{}, fmt.Errorf("endpoint %s was not assigned an ipv6 address", attachedEndpoint.Id)
+	}
+
 	return *attachedEndpoint, nil
 }
 
@@ -102,6 +110,76 @@ func (e *EndpointManager) attachEndpoint(endpoint *hcsshim.HNSEndpoint) (*hcsshi
 }
 
 func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hcsshim.NatPolicy, acls []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error) {
+	policies, err := marshalPolicies(nats, acls)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	endpoint.Policies = append(endpoint.Policies, policies...)
+
+	updatedEndpoint, err := e.hcsClient.UpdateEndpoint(&endpoint)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	return *updatedEndpoint, nil
+}
+
+func (e *EndpointManager) Get() (hcsshim.HNSEndpoint, error) {
+	endpoint, err := e.hcsClient.GetHNSEndpointByName(e.containerId)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	return *endpoint, nil
+}
+
+// UpdatePolicies replaces the NAT and ACL policies of the endpoint with nats
+// and acls, keeping its other policies. The endpoint is only updated if the
+// policies have changed.
+func (e *EndpointManager) UpdatePolicies(endpoint hcsshim.HNSEndpoint, nats []*hcsshim.NatPolicy, acls []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error) {
+	desired, err := marshalPolicies(nats, acls)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	kept := []json.RawMessage{}
+	current := []json.RawMessage{}
+	for _, raw := range endpoint.Policies {
+		var policy hcsshim.Policy
+		if err := json.Unmarshal(raw, &policy); err != nil {
+			return hcsshim.HNSEndpoint{}, err
+		}
+
+		if policy.Type == hcsshim.Nat || policy.Type == hcsshim.ACL {
+			current = append(current, raw)
+		} else {
+			kept = append(kept, raw)
+		}
+	}
+
+	added, removed, err := diffPolicies(current, desired)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	if added == 0 && removed == 0 {
+		logrus.Debugf("policies of endpoint %s are unchanged", endpoint.Id)
+		return endpoint, nil
+	}
+	logrus.Debugf("updating endpoint %s: adding %d policies, removing %d", endpoint.Id, added, removed)
+
+	endpoint.Policies = append(kept, desired...)
+
+	updatedEndpoint, err := e.hcsClient.UpdateEndpoint(&endpoint)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	return *updatedEndpoint, nil
+}
+
+func marshalPolicies(nats []*hcsshim.NatPolicy, acls []*hcsshim.ACLPolicy) ([]json.RawMessage, error) {
 	var policies []json.RawMessage
 
 	if len(acls) == 0 {
@@ -125,7 +203,7 @@ func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hc
 	for _, acl := range acls {
 		policy, err := json.Marshal(acl)
 		if err != nil {
-			return hcsshim.HNSEndpoint{}, err
+			return nil, err
 		}
 		policies = append(policies, policy)
 	}
@@ -133,19 +211,89 @@ func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hc
 	for _, nat := range nats {
 		policy, err := json.Marshal(nat)
 		if err != nil {
-			return hcsshim.HNSEndpoint{}, err
+			return nil, err
 		}
 		policies = append(policies, policy)
 	}
 
-	endpoint.Policies = append(endpoint.Policies, policies...)
+	return policies, nil
+}
 
-	updatedEndpoint, err := e.hcsClient.UpdateEndpoint(&endpoint)
-	if err != nil {
-		return hcsshim.HNSEndpoint{}, err
+// diffPolicies counts the policies in desired but not current, and in current
+// but not desired, ignoring the ids and defaults HNS fills in for the policies
+// it stores
+func diffPolicies(current, desired []json.RawMessage) (int, int, error) {
+	counts := map[string]int{}
+
+	for _, raw := range current {
+		key, err := policyKey(raw)
+		if err != nil {
+			return 0, 0, err
+		}
+		counts[key]++
 	}
 
-	return *updatedEndpoint, nil
+	for _, raw := range desired {
+		key, err := policyKey(raw)
+		if err != nil {
+			return 0, 0, err
+		}
+		counts[key]--
+	}
+
+	added, removed := 0, 0
+	for _, count := range counts {
+		if count > 0 {
+			removed += count
+		} else {
+			added -= count
+		}
+	}
+
+	return added, removed, nil
+}
+
+// policyKey identifies a NAT or ACL policy by the fields winc sets on it
+func policyKey(raw json.RawMessage) (string, error) {
+	var policy hcsshim.Policy
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return "", err
+	}
+
+	var key interface{}
+	switch policy.Type {
+	case hcsshim.Nat:
+		var nat hcsshim.NatPolicy
+		if err := json.Unmarshal(raw, &nat); err != nil {
+			return "", err
+		}
+		key = hcsshim.NatPolicy{
+			Type:         nat.Type,
+			Protocol:     strings.ToUpper(nat.Protocol),
+			InternalPort: nat.InternalPort,
+			ExternalPort: nat.ExternalPort,
+		}
+	case hcsshim.ACL:
+		var acl hcsshim.ACLPolicy
+		if err := json.Unmarshal(raw, &acl); err != nil {
+			return "", err
+		}
+		key = hcsshim.ACLPolicy{
+			Type:            acl.Type,
+			Action:          acl.Action,
+			Direction:       acl.Direction,
+			Protocol:        acl.Protocol,
+			LocalAddresses:  acl.LocalAddresses,
+			RemoteAddresses: acl.RemoteAddresses,
+			LocalPorts:      acl.LocalPorts,
+			RemotePorts:     acl.RemotePorts,
+		}
+	default:
+		return string(raw), nil
+	}
+
+	k, err := json.Marshal(key)
+	return string(k), err
 }
 
 func (e *EndpointManager) Delete() error {
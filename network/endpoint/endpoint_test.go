@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net"
 
 	"code.cloudfoundry.org/winc/network"
 	"code.cloudfoundry.org/winc/network/endpoint"
@@ -138,6 +139,46 @@ var _ = Describe("EndpointManager", func() {
 			})
 		})
 
+		Context("the network config has an IPv6 subnet", func() {
+			BeforeEach(func() {
+				config.SubnetRangeIPv6 = "fd00:1234::/64"
+				config.GatewayAddressIPv6 = "fd00:1234::1"
+				endpointManager = endpoint.NewEndpointManager(hcsClient, containerId, config)
+
+				hcsClient.GetHNSEndpointByIDReturns(&hcsshim.HNSEndpoint{
+					Id:          endpointId,
+					IPAddress:   net.ParseIP("172.30.0.5"),
+					IPv6Address: net.ParseIP("fd00:1234::5"),
+				}, nil)
+			})
+
+			It("returns the endpoint with both addresses", func() {
+				ep, err := endpointManager.Create()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ep.IPAddress.String()).To(Equal("172.30.0.5"))
+				Expect(ep.IPv6Address.String()).To(Equal("fd00:1234::5"))
+			})
+
+			Context("the endpoint is not assigned an IPv6 address", func() {
+				BeforeEach(func() {
+					hcsClient.GetHNSEndpointByIDReturns(&hcsshim.HNSEndpoint{
+						Id:        endpointId,
+						IPAddress: net.ParseIP("172.30.0.5"),
+					}, nil)
+					hcsClient.GetHNSEndpointByNameReturns(&hcsshim.HNSEndpoint{Id: endpointId}, nil)
+				})
+
+				It("detaches and deletes the endpoint and returns an error", func() {
+					_, err := endpointManager.Create()
+					Expect(err).To(MatchError("endpoint endpointid-abcd was not assigned an ipv6 address"))
+
+					Expect(hcsClient.HotDetachEndpointCallCount()).To(Equal(1))
+					Expect(hcsClient.DeleteEndpointCallCount()).To(Equal(1))
+					Expect(hcsClient.DeleteEndpointArgsForCall(0).Id).To(Equal(endpointId))
+				})
+			})
+		})
+
 		Context("attaching the endpoint fails", func() {
 			BeforeEach(func() {
 				hcsClient.HotAttachEndpointReturns(errors.New("couldn't attach endpoint"))
@@ -296,6 +337,129 @@ var _ = Describe("EndpointManager", func() {
 		})
 	})
 
+	Describe("Get", func() {
+		It("looks up the container's endpoint by name", func() {
+			hcsClient.GetHNSEndpointByNameReturns(&hcsshim.HNSEndpoint{Id: endpointId}, nil)
+
+			ep, err := endpointManager.Get()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ep.Id).To(Equal(endpointId))
+			Expect(hcsClient.GetHNSEndpointByNameArgsForCall(0)).To(Equal(containerId))
+		})
+
+		Context("the endpoint doesn't exist", func() {
+			BeforeEach(func() {
+				hcsClient.GetHNSEndpointByNameReturns(nil, hcsshim.EndpointNotFoundError{EndpointName: containerId})
+			})
+
+			It("returns an error", func() {
+				_, err := endpointManager.Get()
+				Expect(err).To(MatchError(hcsshim.EndpointNotFoundError{EndpointName: containerId}))
+			})
+		})
+	})
+
+	Describe("UpdatePolicies", func() {
+		var (
+			nat      *hcsshim.NatPolicy
+			inAcl    *hcsshim.ACLPolicy
+			outAcl   *hcsshim.ACLPolicy
+			qos      json.RawMessage
+			endpoint hcsshim.HNSEndpoint
+		)
+
+		BeforeEach(func() {
+			nat = &hcsshim.NatPolicy{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 111, ExternalPort: 222}
+			inAcl = &hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.In, Action: hcsshim.Allow, LocalPorts: "111"}
+			outAcl = &hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.Out, Action: hcsshim.Allow, RemoteAddresses: "10.0.0.0/8"}
+			qos = json.RawMessage(`{"Type":"QOS","MaximumOutgoingBandwidthInBytes":1000}`)
+
+			endpoint = hcsshim.HNSEndpoint{
+				Id: endpointId,
+				Policies: []json.RawMessage{
+					qos,
+					json.RawMessage(`{"Type":"ACL","Id":"hns-assigned-1","Action":"Allow","Direction":"In","LocalPorts":"111"}`),
+					json.RawMessage(`{"Type":"ACL","Id":"hns-assigned-2","Action":"Allow","Direction":"Out","RemoteAddresses":"10.0.0.0/8"}`),
+					json.RawMessage(`{"Type":"NAT","Protocol":"TCP","InternalPort":111,"ExternalPort":222}`),
+				},
+			}
+			hcsClient.UpdateEndpointReturns(&hcsshim.HNSEndpoint{Id: endpointId}, nil)
+		})
+
+		Context("the policies have not changed", func() {
+			It("does not update the endpoint", func() {
+				ep, err := endpointManager.UpdatePolicies(endpoint, []*hcsshim.NatPolicy{nat}, []*hcsshim.ACLPolicy{inAcl, outAcl})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ep).To(Equal(endpoint))
+				Expect(hcsClient.UpdateEndpointCallCount()).To(Equal(0))
+			})
+
+			Context("HNS has filled in defaults for the stored policies", func() {
+				BeforeEach(func() {
+					endpoint.Policies = []json.RawMessage{
+						qos,
+						json.RawMessage(`{"Type":"ACL","Id":"hns-assigned-1","Action":"Allow","Direction":"In","LocalPorts":"111","RuleType":"Switch","Priority":0,"Scope":0}`),
+						json.RawMessage(`{"Type":"ACL","Id":"hns-assigned-2","Action":"Allow","Direction":"Out","RemoteAddresses":"10.0.0.0/8","RuleType":"Switch","Priority":0,"Scope":0}`),
+						json.RawMessage(`{"Type":"NAT","Protocol":"tcp","InternalPort":111,"ExternalPort":222,"ExternalPortReserved":false,"Flags":0}`),
+					}
+				})
+
+				It("does not update the endpoint", func() {
+					ep, err := endpointManager.UpdatePolicies(endpoint, []*hcsshim.NatPolicy{nat}, []*hcsshim.ACLPolicy{inAcl, outAcl})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ep).To(Equal(endpoint))
+					Expect(hcsClient.UpdateEndpointCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("the policies have changed", func() {
+			It("replaces the NAT and ACL policies and keeps the others", func() {
+				newOutAcl := &hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.Out, Action: hcsshim.Allow, RemoteAddresses: "192.168.0.0/16"}
+
+				_, err := endpointManager.UpdatePolicies(endpoint, []*hcsshim.NatPolicy{nat}, []*hcsshim.ACLPolicy{inAcl, newOutAcl})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(hcsClient.UpdateEndpointCallCount()).To(Equal(1))
+				endpointToUpdate := hcsClient.UpdateEndpointArgsForCall(0)
+				Expect(endpointToUpdate.Id).To(Equal(endpointId))
+				Expect(endpointToUpdate.Policies).To(HaveLen(4))
+				Expect(endpointToUpdate.Policies[0]).To(Equal(qos))
+
+				acl := hcsshim.ACLPolicy{}
+				Expect(json.Unmarshal(endpointToUpdate.Policies[2], &acl)).To(Succeed())
+				Expect(acl).To(Equal(*newOutAcl))
+			})
+
+			Context("no ACLs are given", func() {
+				It("blocks all traffic", func() {
+					_, err := endpointManager.UpdatePolicies(endpoint, []*hcsshim.NatPolicy{}, []*hcsshim.ACLPolicy{})
+					Expect(err).NotTo(HaveOccurred())
+
+					endpointToUpdate := hcsClient.UpdateEndpointArgsForCall(0)
+					Expect(endpointToUpdate.Policies).To(HaveLen(3))
+
+					for _, pol := range endpointToUpdate.Policies[1:] {
+						acl := hcsshim.ACLPolicy{}
+						Expect(json.Unmarshal(pol, &acl)).To(Succeed())
+						Expect(acl.Action).To(Equal(hcsshim.Block))
+					}
+				})
+			})
+
+			Context("updating the endpoint fails", func() {
+				BeforeEach(func() {
+					hcsClient.UpdateEndpointReturns(nil, errors.New("cannot update endpoint"))
+				})
+
+				It("returns an error", func() {
+					_, err := endpointManager.UpdatePolicies(endpoint, []*hcsshim.NatPolicy{}, []*hcsshim.ACLPolicy{outAcl})
+					Expect(err).To(MatchError("cannot update endpoint"))
+				})
+			})
+		})
+	})
+
 	Describe("Delete", func() {
 		var endpoint *hcsshim.HNSEndpoint
 
@@ -22,3 +22,13 @@ type SameNATNetworkNameError struct {
 func (e *SameNATNetworkNameError) Error() string {
 	return fmt.Sprintf("nat network %s exists with subnets %+v", e.Name, e.Subnets)
 }
+
+type InvalidIPv6SubnetError struct {
+	SubnetRange    string
+	GatewayAddress string
+	Reason         string
+}
+
+func (e *InvalidIPv6SubnetError) Error() string {
+	return fmt.Sprintf("invalid ipv6 subnet %q with gateway %q: %s", e.SubnetRange, e.GatewayAddress, e.Reason)
+}
@@ -46,6 +46,33 @@ type EndpointManager struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	GetStub        func() (hcsshim.HNSEndpoint, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+	}
+	getReturns struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}
+	UpdatePoliciesStub        func(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)
+	updatePoliciesMutex       sync.RWMutex
+	updatePoliciesArgsForCall []struct {
+		arg1 hcsshim.HNSEndpoint
+		arg2 []*hcsshim.NatPolicy
+		arg3 []*hcsshim.ACLPolicy
+	}
+	updatePoliciesReturns struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}
+	updatePoliciesReturnsOnCall map[int]struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -235,6 +262,138 @@ func (fake *EndpointManager) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *EndpointManager) Get() (hcsshim.HNSEndpoint, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+	}{})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *EndpointManager) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *EndpointManager) GetCalls(stub func() (hcsshim.HNSEndpoint, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *EndpointManager) GetReturns(result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointManager) GetReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointManager) UpdatePolicies(arg1 hcsshim.HNSEndpoint, arg2 []*hcsshim.NatPolicy, arg3 []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error) {
+	var arg2Copy []*hcsshim.NatPolicy
+	if arg2 != nil {
+		arg2Copy = make([]*hcsshim.NatPolicy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []*hcsshim.ACLPolicy
+	if arg3 != nil {
+		arg3Copy = make([]*hcsshim.ACLPolicy, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.updatePoliciesMutex.Lock()
+	ret, specificReturn := fake.updatePoliciesReturnsOnCall[len(fake.updatePoliciesArgsForCall)]
+	fake.updatePoliciesArgsForCall = append(fake.updatePoliciesArgsForCall, struct {
+		arg1 hcsshim.HNSEndpoint
+		arg2 []*hcsshim.NatPolicy
+		arg3 []*hcsshim.ACLPolicy
+	}{arg1, arg2Copy, arg3Copy})
+	stub := fake.UpdatePoliciesStub
+	fakeReturns := fake.updatePoliciesReturns
+	fake.recordInvocation("UpdatePolicies", []interface{}{arg1, arg2Copy, arg3Copy})
+	fake.updatePoliciesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *EndpointManager) UpdatePoliciesCallCount() int {
+	fake.updatePoliciesMutex.RLock()
+	defer fake.updatePoliciesMutex.RUnlock()
+	return len(fake.updatePoliciesArgsForCall)
+}
+
+func (fake *EndpointManager) UpdatePoliciesCalls(stub func(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)) {
+	fake.updatePoliciesMutex.Lock()
+	defer fake.updatePoliciesMutex.Unlock()
+	fake.UpdatePoliciesStub = stub
+}
+
+func (fake *EndpointManager) UpdatePoliciesArgsForCall(i int) (hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) {
+	fake.updatePoliciesMutex.RLock()
+	defer fake.updatePoliciesMutex.RUnlock()
+	argsForCall := fake.updatePoliciesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *EndpointManager) UpdatePoliciesReturns(result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.updatePoliciesMutex.Lock()
+	defer fake.updatePoliciesMutex.Unlock()
+	fake.UpdatePoliciesStub = nil
+	fake.updatePoliciesReturns = struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointManager) UpdatePoliciesReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.updatePoliciesMutex.Lock()
+	defer fake.updatePoliciesMutex.Unlock()
+	fake.UpdatePoliciesStub = nil
+	if fake.updatePoliciesReturnsOnCall == nil {
+		fake.updatePoliciesReturnsOnCall = make(map[int]struct {
+			result1 hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.updatePoliciesReturnsOnCall[i] = struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *EndpointManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -244,6 +403,10 @@ func (fake *EndpointManager) Invocations() map[string][][]interface{} {
 	defer fake.createMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.updatePoliciesMutex.RLock()
+	defer fake.updatePoliciesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
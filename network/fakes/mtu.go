@@ -8,10 +8,24 @@ import (
 )
 
 type Mtu struct {
-	SetContainerStub        func(int) error
+	GetContainerStub        func(bool) (int, error)
+	getContainerMutex       sync.RWMutex
+	getContainerArgsForCall []struct {
+		arg1 bool
+	}
+	getContainerReturns struct {
+		result1 int
+		result2 error
+	}
+	getContainerReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	SetContainerStub        func(int, bool) error
 	setContainerMutex       sync.RWMutex
 	setContainerArgsForCall []struct {
 		arg1 int
+		arg2 bool
 	}
 	setContainerReturns struct {
 		result1 error
@@ -34,18 +48,83 @@ type Mtu struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *Mtu) SetContainer(arg1 int) error {
+func (fake *Mtu) GetContainer(arg1 bool) (int, error) {
+	fake.getContainerMutex.Lock()
+	ret, specificReturn := fake.getContainerReturnsOnCall[len(fake.getContainerArgsForCall)]
+	fake.getContainerArgsForCall = append(fake.getContainerArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.GetContainerStub
+	fakeReturns := fake.getContainerReturns
+	fake.recordInvocation("GetContainer", []interface{}{arg1})
+	fake.getContainerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Mtu) GetContainerCallCount() int {
+	fake.getContainerMutex.RLock()
+	defer fake.getContainerMutex.RUnlock()
+	return len(fake.getContainerArgsForCall)
+}
+
+func (fake *Mtu) GetContainerCalls(stub func(bool) (int, error)) {
+	fake.getContainerMutex.Lock()
+	defer fake.getContainerMutex.Unlock()
+	fake.GetContainerStub = stub
+}
+
+func (fake *Mtu) GetContainerArgsForCall(i int) bool {
+	fake.getContainerMutex.RLock()
+	defer fake.getContainerMutex.RUnlock()
+	argsForCall := fake.getContainerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Mtu) GetContainerReturns(result1 int, result2 error) {
+	fake.getContainerMutex.Lock()
+	defer fake.getContainerMutex.Unlock()
+	fake.GetContainerStub = nil
+	fake.getContainerReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mtu) GetContainerReturnsOnCall(i int, result1 int, result2 error) {
+	fake.getContainerMutex.Lock()
+	defer fake.getContainerMutex.Unlock()
+	fake.GetContainerStub = nil
+	if fake.getContainerReturnsOnCall == nil {
+		fake.getContainerReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.getContainerReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mtu) SetContainer(arg1 int, arg2 bool) error {
 	fake.setContainerMutex.Lock()
 	ret, specificReturn := fake.setContainerReturnsOnCall[len(fake.setContainerArgsForCall)]
 	fake.setContainerArgsForCall = append(fake.setContainerArgsForCall, struct {
 		arg1 int
-	}{arg1})
+		arg2 bool
+	}{arg1, arg2})
 	stub := fake.SetContainerStub
 	fakeReturns := fake.setContainerReturns
-	fake.recordInvocation("SetContainer", []interface{}{arg1})
+	fake.recordInvocation("SetContainer", []interface{}{arg1, arg2})
 	fake.setContainerMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -59,17 +138,17 @@ func (fake *Mtu) SetContainerCallCount() int {
 	return len(fake.setContainerArgsForCall)
 }
 
-func (fake *Mtu) SetContainerCalls(stub func(int) error) {
+func (fake *Mtu) SetContainerCalls(stub func(int, bool) error) {
 	fake.setContainerMutex.Lock()
 	defer fake.setContainerMutex.Unlock()
 	fake.SetContainerStub = stub
 }
 
-func (fake *Mtu) SetContainerArgsForCall(i int) int {
+func (fake *Mtu) SetContainerArgsForCall(i int) (int, bool) {
 	fake.setContainerMutex.RLock()
 	defer fake.setContainerMutex.RUnlock()
 	argsForCall := fake.setContainerArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *Mtu) SetContainerReturns(result1 error) {
@@ -159,6 +238,8 @@ func (fake *Mtu) SetNatReturnsOnCall(i int, result1 error) {
 func (fake *Mtu) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.getContainerMutex.RLock()
+	defer fake.getContainerMutex.RUnlock()
 	fake.setContainerMutex.RLock()
 	defer fake.setContainerMutex.RUnlock()
 	fake.setNatMutex.RLock()
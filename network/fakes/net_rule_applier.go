@@ -20,20 +20,20 @@ type NetRuleApplier struct {
 	cleanupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	InStub        func(netrules.NetIn, string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error)
+	InStub        func(netrules.NetIn, string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error)
 	inMutex       sync.RWMutex
 	inArgsForCall []struct {
 		arg1 netrules.NetIn
 		arg2 string
 	}
 	inReturns struct {
-		result1 *hcsshim.NatPolicy
-		result2 *hcsshim.ACLPolicy
+		result1 []*hcsshim.NatPolicy
+		result2 []*hcsshim.ACLPolicy
 		result3 error
 	}
 	inReturnsOnCall map[int]struct {
-		result1 *hcsshim.NatPolicy
-		result2 *hcsshim.ACLPolicy
+		result1 []*hcsshim.NatPolicy
+		result2 []*hcsshim.ACLPolicy
 		result3 error
 	}
 	OpenPortStub        func(uint32) error
@@ -61,6 +61,18 @@ type NetRuleApplier struct {
 		result1 *hcsshim.ACLPolicy
 		result2 error
 	}
+	ReleasePortStub        func(string, uint16) error
+	releasePortMutex       sync.RWMutex
+	releasePortArgsForCall []struct {
+		arg1 string
+		arg2 uint16
+	}
+	releasePortReturns struct {
+		result1 error
+	}
+	releasePortReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -118,7 +130,7 @@ func (fake *NetRuleApplier) CleanupReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *NetRuleApplier) In(arg1 netrules.NetIn, arg2 string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error) {
+func (fake *NetRuleApplier) In(arg1 netrules.NetIn, arg2 string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error) {
 	fake.inMutex.Lock()
 	ret, specificReturn := fake.inReturnsOnCall[len(fake.inArgsForCall)]
 	fake.inArgsForCall = append(fake.inArgsForCall, struct {
@@ -144,7 +156,7 @@ func (fake *NetRuleApplier) InCallCount() int {
 	return len(fake.inArgsForCall)
 }
 
-func (fake *NetRuleApplier) InCalls(stub func(netrules.NetIn, string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error)) {
+func (fake *NetRuleApplier) InCalls(stub func(netrules.NetIn, string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error)) {
 	fake.inMutex.Lock()
 	defer fake.inMutex.Unlock()
 	fake.InStub = stub
@@ -157,31 +169,31 @@ func (fake *NetRuleApplier) InArgsForCall(i int) (netrules.NetIn, string) {
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *NetRuleApplier) InReturns(result1 *hcsshim.NatPolicy, result2 *hcsshim.ACLPolicy, result3 error) {
+func (fake *NetRuleApplier) InReturns(result1 []*hcsshim.NatPolicy, result2 []*hcsshim.ACLPolicy, result3 error) {
 	fake.inMutex.Lock()
 	defer fake.inMutex.Unlock()
 	fake.InStub = nil
 	fake.inReturns = struct {
-		result1 *hcsshim.NatPolicy
-		result2 *hcsshim.ACLPolicy
+		result1 []*hcsshim.NatPolicy
+		result2 []*hcsshim.ACLPolicy
 		result3 error
 	}{result1, result2, result3}
 }
 
-func (fake *NetRuleApplier) InReturnsOnCall(i int, result1 *hcsshim.NatPolicy, result2 *hcsshim.ACLPolicy, result3 error) {
+func (fake *NetRuleApplier) InReturnsOnCall(i int, result1 []*hcsshim.NatPolicy, result2 []*hcsshim.ACLPolicy, result3 error) {
 	fake.inMutex.Lock()
 	defer fake.inMutex.Unlock()
 	fake.InStub = nil
 	if fake.inReturnsOnCall == nil {
 		fake.inReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.NatPolicy
-			result2 *hcsshim.ACLPolicy
+			result1 []*hcsshim.NatPolicy
+			result2 []*hcsshim.ACLPolicy
 			result3 error
 		})
 	}
 	fake.inReturnsOnCall[i] = struct {
-		result1 *hcsshim.NatPolicy
-		result2 *hcsshim.ACLPolicy
+		result1 []*hcsshim.NatPolicy
+		result2 []*hcsshim.ACLPolicy
 		result3 error
 	}{result1, result2, result3}
 }
@@ -312,6 +324,68 @@ func (fake *NetRuleApplier) OutReturnsOnCall(i int, result1 *hcsshim.ACLPolicy,
 	}{result1, result2}
 }
 
+func (fake *NetRuleApplier) ReleasePort(arg1 string, arg2 uint16) error {
+	fake.releasePortMutex.Lock()
+	ret, specificReturn := fake.releasePortReturnsOnCall[len(fake.releasePortArgsForCall)]
+	fake.releasePortArgsForCall = append(fake.releasePortArgsForCall, struct {
+		arg1 string
+		arg2 uint16
+	}{arg1, arg2})
+	stub := fake.ReleasePortStub
+	fakeReturns := fake.releasePortReturns
+	fake.recordInvocation("ReleasePort", []interface{}{arg1, arg2})
+	fake.releasePortMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *NetRuleApplier) ReleasePortCallCount() int {
+	fake.releasePortMutex.RLock()
+	defer fake.releasePortMutex.RUnlock()
+	return len(fake.releasePortArgsForCall)
+}
+
+func (fake *NetRuleApplier) ReleasePortCalls(stub func(string, uint16) error) {
+	fake.releasePortMutex.Lock()
+	defer fake.releasePortMutex.Unlock()
+	fake.ReleasePortStub = stub
+}
+
+func (fake *NetRuleApplier) ReleasePortArgsForCall(i int) (string, uint16) {
+	fake.releasePortMutex.RLock()
+	defer fake.releasePortMutex.RUnlock()
+	argsForCall := fake.releasePortArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *NetRuleApplier) ReleasePortReturns(result1 error) {
+	fake.releasePortMutex.Lock()
+	defer fake.releasePortMutex.Unlock()
+	fake.ReleasePortStub = nil
+	fake.releasePortReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *NetRuleApplier) ReleasePortReturnsOnCall(i int, result1 error) {
+	fake.releasePortMutex.Lock()
+	defer fake.releasePortMutex.Unlock()
+	fake.ReleasePortStub = nil
+	if fake.releasePortReturnsOnCall == nil {
+		fake.releasePortReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releasePortReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *NetRuleApplier) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -323,6 +397,8 @@ func (fake *NetRuleApplier) Invocations() map[string][][]interface{} {
 	defer fake.openPortMutex.RUnlock()
 	fake.outMutex.RLock()
 	defer fake.outMutex.RUnlock()
+	fake.releasePortMutex.RLock()
+	defer fake.releasePortMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
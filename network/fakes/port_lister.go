@@ -0,0 +1,116 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/network"
+)
+
+type PortLister struct {
+	AllocatedPortsStub        func(string) (map[string][]uint16, error)
+	allocatedPortsMutex       sync.RWMutex
+	allocatedPortsArgsForCall []struct {
+		arg1 string
+	}
+	allocatedPortsReturns struct {
+		result1 map[string][]uint16
+		result2 error
+	}
+	allocatedPortsReturnsOnCall map[int]struct {
+		result1 map[string][]uint16
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PortLister) AllocatedPorts(arg1 string) (map[string][]uint16, error) {
+	fake.allocatedPortsMutex.Lock()
+	ret, specificReturn := fake.allocatedPortsReturnsOnCall[len(fake.allocatedPortsArgsForCall)]
+	fake.allocatedPortsArgsForCall = append(fake.allocatedPortsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.AllocatedPortsStub
+	fakeReturns := fake.allocatedPortsReturns
+	fake.recordInvocation("AllocatedPorts", []interface{}{arg1})
+	fake.allocatedPortsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PortLister) AllocatedPortsCallCount() int {
+	fake.allocatedPortsMutex.RLock()
+	defer fake.allocatedPortsMutex.RUnlock()
+	return len(fake.allocatedPortsArgsForCall)
+}
+
+func (fake *PortLister) AllocatedPortsCalls(stub func(string) (map[string][]uint16, error)) {
+	fake.allocatedPortsMutex.Lock()
+	defer fake.allocatedPortsMutex.Unlock()
+	fake.AllocatedPortsStub = stub
+}
+
+func (fake *PortLister) AllocatedPortsArgsForCall(i int) string {
+	fake.allocatedPortsMutex.RLock()
+	defer fake.allocatedPortsMutex.RUnlock()
+	argsForCall := fake.allocatedPortsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PortLister) AllocatedPortsReturns(result1 map[string][]uint16, result2 error) {
+	fake.allocatedPortsMutex.Lock()
+	defer fake.allocatedPortsMutex.Unlock()
+	fake.AllocatedPortsStub = nil
+	fake.allocatedPortsReturns = struct {
+		result1 map[string][]uint16
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PortLister) AllocatedPortsReturnsOnCall(i int, result1 map[string][]uint16, result2 error) {
+	fake.allocatedPortsMutex.Lock()
+	defer fake.allocatedPortsMutex.Unlock()
+	fake.AllocatedPortsStub = nil
+	if fake.allocatedPortsReturnsOnCall == nil {
+		fake.allocatedPortsReturnsOnCall = make(map[int]struct {
+			result1 map[string][]uint16
+			result2 error
+		})
+	}
+	fake.allocatedPortsReturnsOnCall[i] = struct {
+		result1 map[string][]uint16
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PortLister) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.allocatedPortsMutex.RLock()
+	defer fake.allocatedPortsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PortLister) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ network.PortLister = new(PortLister)
@@ -0,0 +1,86 @@
+package network
+
+import (
+	"strings"
+)
+
+//go:generate counterfeiter -o fakes/port_lister.go --fake-name PortLister . PortLister
+type PortLister interface {
+	AllocatedPorts(handle string) (map[string][]uint16, error)
+}
+
+type InspectOutputs struct {
+	EndpointID     string              `json:"endpoint_id"`
+	IPAddress      string              `json:"ip_address"`
+	IPv6Address    string              `json:"ipv6_address,omitempty"`
+	MACAddress     string              `json:"mac_address"`
+	DNSServers     []string            `json:"dns_servers"`
+	MTU            int                 `json:"mtu"`
+	IPv6MTU        int                 `json:"ipv6_mtu,omitempty"`
+	AllocatedPorts map[string][]uint16 `json:"allocated_ports"`
+	Policies       []string            `json:"policies"`
+}
+
+// Inspector reports the effective network configuration of a container
+type Inspector struct {
+	endpointManager EndpointManager
+	portLister      PortLister
+	mtu             Mtu
+	containerId     string
+}
+
+func NewInspector(endpointManager EndpointManager, portLister PortLister, mtu Mtu, containerId string) *Inspector {
+	return &Inspector{
+		endpointManager: endpointManager,
+		portLister:      portLister,
+		mtu:             mtu,
+		containerId:     containerId,
+	}
+}
+
+func (i *Inspector) Inspect() (InspectOutputs, error) {
+	endpoint, err := i.endpointManager.Get()
+	if err != nil {
+		return InspectOutputs{}, err
+	}
+
+	policies, err := DecodePolicies(endpoint.Policies)
+	if err != nil {
+		return InspectOutputs{}, err
+	}
+
+	mtu, err := i.mtu.GetContainer(false)
+	if err != nil {
+		return InspectOutputs{}, err
+	}
+
+	ports, err := i.portLister.AllocatedPorts(i.containerId)
+	if err != nil {
+		return InspectOutputs{}, err
+	}
+
+	outputs := InspectOutputs{
+		EndpointID:     endpoint.Id,
+		IPAddress:      endpoint.IPAddress.String(),
+		MACAddress:     endpoint.MacAddress,
+		DNSServers:     []string{},
+		MTU:            mtu,
+		AllocatedPorts: ports,
+		Policies:       policies.Describe(),
+	}
+
+	if endpoint.IPv6Address != nil {
+		outputs.IPv6Address = endpoint.IPv6Address.String()
+
+		outputs.IPv6MTU, err = i.mtu.GetContainer(true)
+		if err != nil {
+			return InspectOutputs{}, err
+		}
+	}
+
+	if endpoint.DNSServerList != "" {
+		outputs.DNSServers = strings.Split(endpoint.DNSServerList, ",")
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,150 @@
+package network_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+
+	"code.cloudfoundry.org/winc/network"
+	"code.cloudfoundry.org/winc/network/fakes"
+	"github.com/Microsoft/hcsshim"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inspector", func() {
+	const containerId = "some-container-id"
+
+	var (
+		inspector       *network.Inspector
+		endpointManager *fakes.EndpointManager
+		portLister      *fakes.PortLister
+		mtu             *fakes.Mtu
+	)
+
+	BeforeEach(func() {
+		endpointManager = &fakes.EndpointManager{}
+		portLister = &fakes.PortLister{}
+		mtu = &fakes.Mtu{}
+
+		endpointManager.GetReturns(hcsshim.HNSEndpoint{
+			Id:            "some-endpoint-id",
+			IPAddress:     net.ParseIP("172.30.0.5"),
+			MacAddress:    "00-15-5D-12-34-56",
+			DNSServerList: "8.8.8.8,8.8.4.4",
+			Policies: []json.RawMessage{
+				json.RawMessage(`{"Type":"NAT","Protocol":"TCP","InternalPort":8080,"ExternalPort":40000}`),
+				json.RawMessage(`{"Type":"ACL","Id":"hns-assigned","Action":"Allow","Direction":"In","Protocol":6,"LocalAddresses":"172.30.0.5","LocalPorts":"8080"}`),
+				json.RawMessage(`{"Type":"ACL","Action":"Allow","Direction":"Out","Protocol":17,"LocalAddresses":"172.30.0.5","RemoteAddresses":"8.8.8.8/32","RemotePorts":"53"}`),
+				json.RawMessage(`{"Type":"ACL","Action":"Block","Direction":"Out","Protocol":256}`),
+				json.RawMessage(`{"Type":"QOS","MaximumOutgoingBandwidthInBytes":1000}`),
+				json.RawMessage(`{"Type":"OutBoundNAT"}`),
+			},
+		}, nil)
+		mtu.GetContainerReturns(1400, nil)
+		portLister.AllocatedPortsReturns(map[string][]uint16{"tcp": {40000}, "udp": {}}, nil)
+
+		inspector = network.NewInspector(endpointManager, portLister, mtu, containerId)
+	})
+
+	It("reports the effective network config of the container", func() {
+		outputs, err := inspector.Inspect()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(outputs.EndpointID).To(Equal("some-endpoint-id"))
+		Expect(outputs.IPAddress).To(Equal("172.30.0.5"))
+		Expect(outputs.IPv6Address).To(BeEmpty())
+		Expect(outputs.MACAddress).To(Equal("00-15-5D-12-34-56"))
+		Expect(outputs.DNSServers).To(Equal([]string{"8.8.8.8", "8.8.4.4"}))
+		Expect(outputs.MTU).To(Equal(1400))
+		Expect(outputs.IPv6MTU).To(BeZero())
+		Expect(mtu.GetContainerCallCount()).To(Equal(1))
+		Expect(mtu.GetContainerArgsForCall(0)).To(BeFalse())
+		Expect(outputs.AllocatedPorts).To(Equal(map[string][]uint16{"tcp": {40000}, "udp": {}}))
+
+		Expect(portLister.AllocatedPortsArgsForCall(0)).To(Equal(containerId))
+	})
+
+	It("describes each policy on the endpoint", func() {
+		outputs, err := inspector.Inspect()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(outputs.Policies).To(Equal([]string{
+			"NAT tcp host port 40000 to container port 8080",
+			"ACL allow in tcp local 172.30.0.5 ports 8080 remote any",
+			"ACL allow out udp local 172.30.0.5 remote 8.8.8.8/32 ports 53",
+			"ACL block out all local any remote any",
+			"QOS maximum outgoing bandwidth 1000 bytes/s",
+			`{"Type":"OutBoundNAT"}`,
+		}))
+	})
+
+	Context("the endpoint is dual-stack", func() {
+		BeforeEach(func() {
+			endpointManager.GetReturns(hcsshim.HNSEndpoint{
+				IPAddress:   net.ParseIP("172.30.0.5"),
+				IPv6Address: net.ParseIP("fd00:1234::5"),
+			}, nil)
+			mtu.GetContainerReturnsOnCall(1, 1280, nil)
+		})
+
+		It("reports the IPv6 address", func() {
+			outputs, err := inspector.Inspect()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs.IPv6Address).To(Equal("fd00:1234::5"))
+			Expect(outputs.DNSServers).To(BeEmpty())
+		})
+
+		It("reports the mtu of the IPv6 interface", func() {
+			outputs, err := inspector.Inspect()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs.MTU).To(Equal(1400))
+			Expect(outputs.IPv6MTU).To(Equal(1280))
+			Expect(mtu.GetContainerArgsForCall(1)).To(BeTrue())
+		})
+
+		Context("getting the IPv6 mtu fails", func() {
+			BeforeEach(func() {
+				mtu.GetContainerReturnsOnCall(1, 0, errors.New("no IPv6 interface"))
+			})
+
+			It("returns an error", func() {
+				_, err := inspector.Inspect()
+				Expect(err).To(MatchError("no IPv6 interface"))
+			})
+		})
+	})
+
+	Context("getting the endpoint fails", func() {
+		BeforeEach(func() {
+			endpointManager.GetReturns(hcsshim.HNSEndpoint{}, errors.New("no endpoint"))
+		})
+
+		It("returns an error", func() {
+			_, err := inspector.Inspect()
+			Expect(err).To(MatchError("no endpoint"))
+		})
+	})
+
+	Context("getting the mtu fails", func() {
+		BeforeEach(func() {
+			mtu.GetContainerReturns(0, errors.New("no interface"))
+		})
+
+		It("returns an error", func() {
+			_, err := inspector.Inspect()
+			Expect(err).To(MatchError("no interface"))
+		})
+	})
+
+	Context("listing the allocated ports fails", func() {
+		BeforeEach(func() {
+			portLister.AllocatedPortsReturns(nil, errors.New("decoding state file: potato"))
+		})
+
+		It("returns an error", func() {
+			_, err := inspector.Inspect()
+			Expect(err).To(MatchError("decoding state file: potato"))
+		})
+	})
+})
@@ -30,7 +30,9 @@ func New(containerId string, networkName string, netInterface NetInterface) *Mtu
 	}
 }
 
-func (m *Mtu) SetContainer(mtu int) error {
+// SetContainer applies the mtu to the container's IPv4 interface, and to its
+// IPv6 interface as well when the container is dual-stack
+func (m *Mtu) SetContainer(mtu int, ipv6 bool) error {
 	if mtu == 0 {
 		adapterInfo, err := m.netInterface.ByName(fmt.Sprintf("vEthernet (%s)", m.networkName))
 		if err != nil {
@@ -44,7 +46,31 @@ func (m *Mtu) SetContainer(mtu int) error {
 	}
 
 	interfaceAlias := fmt.Sprintf("vEthernet (%s)", m.containerId)
-	return m.netInterface.SetMTU(interfaceAlias, uint32(mtu), windows.AF_INET)
+	if err := m.netInterface.SetMTU(interfaceAlias, uint32(mtu), windows.AF_INET); err != nil {
+		return err
+	}
+
+	if !ipv6 {
+		return nil
+	}
+	return m.netInterface.SetMTU(interfaceAlias, uint32(mtu), windows.AF_INET6)
+}
+
+// GetContainer returns the mtu of the container's IPv4 interface, or of its
+// IPv6 interface when ipv6 is set
+func (m *Mtu) GetContainer(ipv6 bool) (int, error) {
+	family := uint32(windows.AF_INET)
+	if ipv6 {
+		family = windows.AF_INET6
+	}
+
+	interfaceAlias := fmt.Sprintf("vEthernet (%s)", m.containerId)
+	mtu, err := m.netInterface.GetMTU(interfaceAlias, family)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(mtu), nil
 }
 
 func (m *Mtu) SetNat(mtu int) error {
@@ -1,6 +1,7 @@
 package mtu_test
 
 import (
+	"errors"
 	"fmt"
 
 	"code.cloudfoundry.org/localip"
@@ -28,7 +29,7 @@ var _ = Describe("Mtu", func() {
 
 	Describe("SetContainer", func() {
 		It("applies the mtu to the container", func() {
-			Expect(m.SetContainer(1405)).To(Succeed())
+			Expect(m.SetContainer(1405, false)).To(Succeed())
 
 			Expect(netInterface.SetMTUCallCount()).To(Equal(1))
 			alias, mtu, family := netInterface.SetMTUArgsForCall(0)
@@ -47,7 +48,7 @@ var _ = Describe("Mtu", func() {
 			})
 
 			It("sets the container MTU to the NAT network MTU", func() {
-				Expect(m.SetContainer(0)).To(Succeed())
+				Expect(m.SetContainer(0, false)).To(Succeed())
 
 				Expect(netInterface.ByNameCallCount()).To(Equal(1))
 				Expect(netInterface.ByNameArgsForCall(0)).To(Equal(natNetworkName))
@@ -64,6 +65,69 @@ var _ = Describe("Mtu", func() {
 				Expect(family).To(Equal(uint32(windows.AF_INET)))
 			})
 		})
+
+		Context("the container is dual-stack", func() {
+			It("applies the mtu to both of the container's interfaces", func() {
+				Expect(m.SetContainer(1405, true)).To(Succeed())
+
+				Expect(netInterface.SetMTUCallCount()).To(Equal(2))
+				alias, mtu, family := netInterface.SetMTUArgsForCall(0)
+				Expect(alias).To(Equal("vEthernet (containerabc)"))
+				Expect(mtu).To(Equal(uint32(1405)))
+				Expect(family).To(Equal(uint32(windows.AF_INET)))
+
+				alias, mtu, family = netInterface.SetMTUArgsForCall(1)
+				Expect(alias).To(Equal("vEthernet (containerabc)"))
+				Expect(mtu).To(Equal(uint32(1405)))
+				Expect(family).To(Equal(uint32(windows.AF_INET6)))
+			})
+
+			Context("setting the IPv4 mtu fails", func() {
+				BeforeEach(func() {
+					netInterface.SetMTUReturnsOnCall(0, errors.New("no interface"))
+				})
+
+				It("returns an error without setting the IPv6 mtu", func() {
+					Expect(m.SetContainer(1405, true)).To(MatchError("no interface"))
+					Expect(netInterface.SetMTUCallCount()).To(Equal(1))
+				})
+			})
+		})
+	})
+
+	Describe("GetContainer", func() {
+		It("returns the mtu of the container interface", func() {
+			netInterface.GetMTUReturns(1405, nil)
+
+			Expect(m.GetContainer(false)).To(Equal(1405))
+
+			alias, family := netInterface.GetMTUArgsForCall(0)
+			Expect(alias).To(Equal("vEthernet (containerabc)"))
+			Expect(family).To(Equal(uint32(windows.AF_INET)))
+		})
+
+		Context("getting the mtu fails", func() {
+			BeforeEach(func() {
+				netInterface.GetMTUReturns(0, errors.New("no interface"))
+			})
+
+			It("returns an error", func() {
+				_, err := m.GetContainer(false)
+				Expect(err).To(MatchError("no interface"))
+			})
+		})
+	})
+
+	Describe("GetContainer for IPv6", func() {
+		It("returns the mtu of the container's IPv6 interface", func() {
+			netInterface.GetMTUReturns(1280, nil)
+
+			Expect(m.GetContainer(true)).To(Equal(1280))
+
+			alias, family := netInterface.GetMTUArgsForCall(0)
+			Expect(alias).To(Equal("vEthernet (containerabc)"))
+			Expect(family).To(Equal(uint32(windows.AF_INET6)))
+		})
 	})
 
 	Describe("SetNat", func() {
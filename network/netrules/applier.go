@@ -16,8 +16,9 @@ type NetShRunner interface {
 
 //go:generate counterfeiter -o fakes/port_allocator.go --fake-name PortAllocator . PortAllocator
 type PortAllocator interface {
-	AllocatePort(handle string, port uint16) (uint16, error)
+	AllocatePort(handle string, protocols []string, port uint16) (uint16, error)
 	ReleaseAllPorts(handle string) error
+	ReleasePort(handle string, protocols []string, port uint16) error
 }
 
 type Applier struct {
@@ -34,46 +35,66 @@ func NewApplier(netSh NetShRunner, containerId string, portAllocator PortAllocat
 	}
 }
 
-func (a *Applier) In(rule NetIn, containerIP string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error) {
+// In returns a NAT and an ACL policy for each protocol the rule maps. When a
+// rule maps both TCP and UDP, the same host port is used for each.
+func (a *Applier) In(rule NetIn, containerIP string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error) {
+	protocols, err := rule.Protocols()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	externalPort := rule.HostPort
 
 	if externalPort == 0 {
-		allocatedPort, err := a.portAllocator.AllocatePort(a.containerId, 0)
+		allocatedPort, err := a.portAllocator.AllocatePort(a.containerId, protocols, 0)
 		if err != nil {
 			return nil, nil, err
 		}
 		externalPort = uint16(allocatedPort)
 	}
 
-	return &hcsshim.NatPolicy{
+	nats := []*hcsshim.NatPolicy{}
+	acls := []*hcsshim.ACLPolicy{}
+
+	for _, protocol := range protocols {
+		nats = append(nats, &hcsshim.NatPolicy{
 			Type:         hcsshim.Nat,
-			Protocol:     "TCP",
+			Protocol:     strings.ToUpper(protocol),
 			ExternalPort: uint16(externalPort),
 			InternalPort: uint16(rule.ContainerPort),
-		}, &hcsshim.ACLPolicy{
+		})
+
+		acls = append(acls, &hcsshim.ACLPolicy{
 			Type:           hcsshim.ACL,
 			Action:         hcsshim.Allow,
 			Direction:      hcsshim.In,
-			Protocol:       uint16(firewall.NET_FW_IP_PROTOCOL_TCP),
+			Protocol:       uint16(FirewallProtocol(protocol)),
 			LocalAddresses: containerIP,
 			LocalPorts:     strconv.FormatUint(uint64(rule.ContainerPort), 10),
-		}, nil
+		})
+	}
+
+	return nats, acls, nil
 }
 
 func (a *Applier) Out(rule NetOut, containerIP string) (*hcsshim.ACLPolicy, error) {
 	rAddrs := []string{}
 
 	for _, ipr := range rule.Networks {
-		rAddrs = append(rAddrs, IPRangeToCIDRs(ipr)...)
+		cidrs, err := IPRangeToCIDRs(ipr)
+		if err != nil {
+			return nil, err
+		}
+		rAddrs = append(rAddrs, cidrs...)
 	}
 
-	// if any IP CIDRS are 0.0.0.0/0, all remote destinations are allowed.
-	// However, passing 0.0.0.0/0 directly in our ACLPolicy doesn't actually
-	// have that effect.
+	// if any IP CIDRS are 0.0.0.0/0 or ::/0, all remote destinations are
+	// allowed. However, passing 0.0.0.0/0 or ::/0 directly in our ACLPolicy
+	// doesn't actually have that effect.
 	// So just don't specfiy anything in our ACLPolicy -- this allows acces
 	// to all remote destinations
 	for _, addr := range rAddrs {
-		if addr == "0.0.0.0/0" {
+		if addr == "0.0.0.0/0" || addr == "::/0" {
 			rAddrs = []string{}
 			break
 		}
@@ -109,6 +130,13 @@ func (a *Applier) OpenPort(port uint32) error {
 	args := []string{"http", "add", "urlacl", fmt.Sprintf("url=http://*:%d/", port), "user=Users"}
 	return a.netSh.RunContainer(args)
 }
+
+// ReleasePort returns a host port to the pool once it is no longer mapped for
+// protocol
+func (a *Applier) ReleasePort(protocol string, port uint16) error {
+	return a.portAllocator.ReleasePort(a.containerId, []string{protocol}, port)
+}
+
 func (a *Applier) Cleanup() error {
 	return a.portAllocator.ReleaseAllPorts(a.containerId)
 }
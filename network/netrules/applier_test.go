@@ -69,7 +69,7 @@ var _ = Describe("Applier", func() {
 		})
 
 		It("returns the correct nat and acl policies", func() {
-			nat, acl, err := applier.In(netInRule, containerIP)
+			nats, acls, err := applier.In(netInRule, containerIP)
 			Expect(err).NotTo(HaveOccurred())
 
 			expectedNat := hcsshim.NatPolicy{
@@ -78,7 +78,7 @@ var _ = Describe("Applier", func() {
 				InternalPort: 1000,
 				ExternalPort: 2000,
 			}
-			Expect(*nat).To(Equal(expectedNat))
+			Expect(nats).To(Equal([]*hcsshim.NatPolicy{&expectedNat}))
 
 			expectedAcl := hcsshim.ACLPolicy{
 				Type:           hcsshim.ACL,
@@ -88,7 +88,62 @@ var _ = Describe("Applier", func() {
 				LocalAddresses: "5.4.3.2",
 				LocalPorts:     "1000",
 			}
-			Expect(*acl).To(Equal(expectedAcl))
+			Expect(acls).To(Equal([]*hcsshim.ACLPolicy{&expectedAcl}))
+		})
+
+		Context("the rule is for UDP", func() {
+			BeforeEach(func() {
+				netInRule.Protocol = "udp"
+			})
+
+			It("returns UDP nat and acl policies", func() {
+				nats, acls, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(nats).To(HaveLen(1))
+				Expect(nats[0].Protocol).To(Equal("UDP"))
+				Expect(acls).To(HaveLen(1))
+				Expect(acls[0].Protocol).To(Equal(uint16(17)))
+			})
+		})
+
+		Context("the rule is for both TCP and UDP", func() {
+			BeforeEach(func() {
+				netInRule = netrules.NetIn{
+					ContainerPort: 1000,
+					HostPort:      0,
+					Protocol:      "both",
+				}
+				portAllocator.AllocatePortReturns(1234, nil)
+			})
+
+			It("returns nat and acl policies for each protocol with the same host port", func() {
+				nats, acls, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1000, ExternalPort: 1234},
+					{Type: hcsshim.Nat, Protocol: "UDP", InternalPort: 1000, ExternalPort: 1234},
+				}))
+				Expect(acls).To(HaveLen(2))
+				Expect(acls[0].Protocol).To(Equal(uint16(6)))
+				Expect(acls[1].Protocol).To(Equal(uint16(17)))
+
+				Expect(portAllocator.AllocatePortCallCount()).To(Equal(1))
+				_, protocols, _ := portAllocator.AllocatePortArgsForCall(0)
+				Expect(protocols).To(Equal([]string{"tcp", "udp"}))
+			})
+		})
+
+		Context("the protocol is invalid", func() {
+			BeforeEach(func() {
+				netInRule.Protocol = "icmp"
+			})
+
+			It("returns an error", func() {
+				_, _, err := applier.In(netInRule, containerIP)
+				Expect(err).To(MatchError("invalid netin protocol: icmp"))
+			})
 		})
 
 		Context("the host port is zero", func() {
@@ -101,7 +156,7 @@ var _ = Describe("Applier", func() {
 			})
 
 			It("uses the port allocator to find an open host port", func() {
-				nat, acl, err := applier.In(netInRule, containerIP)
+				nats, acls, err := applier.In(netInRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
 				expectedNat := hcsshim.NatPolicy{
@@ -110,7 +165,7 @@ var _ = Describe("Applier", func() {
 					InternalPort: 1000,
 					ExternalPort: 1234,
 				}
-				Expect(*nat).To(Equal(expectedNat))
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{&expectedNat}))
 
 				expectedAcl := hcsshim.ACLPolicy{
 					Type:           hcsshim.ACL,
@@ -120,11 +175,12 @@ var _ = Describe("Applier", func() {
 					LocalAddresses: "5.4.3.2",
 					LocalPorts:     "1000",
 				}
-				Expect(*acl).To(Equal(expectedAcl))
+				Expect(acls).To(Equal([]*hcsshim.ACLPolicy{&expectedAcl}))
 
 				Expect(portAllocator.AllocatePortCallCount()).To(Equal(1))
-				id, p := portAllocator.AllocatePortArgsForCall(0)
+				id, protocols, p := portAllocator.AllocatePortArgsForCall(0)
 				Expect(id).To(Equal(containerId))
+				Expect(protocols).To(Equal([]string{"tcp"}))
 				Expect(p).To(Equal(uint16(0)))
 			})
 
@@ -277,6 +333,60 @@ var _ = Describe("Applier", func() {
 			})
 		})
 
+		Context("netout contains IPv6 ranges", func() {
+			BeforeEach(func() {
+				netOutRule.Networks = []netrules.IPRange{
+					ipRangeFromIP(net.ParseIP("2001:db8::1")),
+					netrules.IPRange{
+						Start: net.ParseIP("2001:db8:1::"),
+						End:   net.ParseIP("2001:db8:1::ff"),
+					},
+				}
+				netOutRule.Protocol = netrules.ProtocolTCP
+			})
+
+			It("returns an HNS ACL with IPv6 remote addresses", func() {
+				acl, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(acl.RemoteAddresses).To(Equal("2001:db8::1/128,2001:db8:1::/120"))
+			})
+		})
+
+		Context("netout contains an ip range that resolves to ::/0", func() {
+			BeforeEach(func() {
+				netOutRule.Networks = []netrules.IPRange{
+					ipRangeFromIP(net.ParseIP("8.8.8.8")),
+					netrules.IPRange{
+						Start: net.ParseIP("::"),
+						End:   net.ParseIP("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+					},
+				}
+				netOutRule.Protocol = netrules.ProtocolAll
+			})
+
+			It("returns an HNS ACL with empty remote addresses", func() {
+				acl, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(acl.RemoteAddresses).To(BeEmpty())
+			})
+		})
+
+		Context("netout contains an ip range that mixes IPv4 and IPv6", func() {
+			BeforeEach(func() {
+				netOutRule.Networks = []netrules.IPRange{
+					netrules.IPRange{
+						Start: net.ParseIP("10.0.0.0"),
+						End:   net.ParseIP("2001:db8::1"),
+					},
+				}
+			})
+
+			It("returns an error", func() {
+				_, err := applier.Out(netOutRule, containerIP)
+				Expect(err).To(MatchError("invalid ip range 10.0.0.0-2001:db8::1: start and end must both be IPv4 or both be IPv6 addresses"))
+			})
+		})
+
 		Context("an invalid protocol is specified", func() {
 			BeforeEach(func() {
 				netOutRule.Protocol = 7
@@ -289,6 +399,18 @@ var _ = Describe("Applier", func() {
 		})
 	})
 
+	Describe("ReleasePort", func() {
+		It("de-allocates the port for the protocol", func() {
+			Expect(applier.ReleasePort("udp", 1234)).To(Succeed())
+
+			Expect(portAllocator.ReleasePortCallCount()).To(Equal(1))
+			handle, protocols, port := portAllocator.ReleasePortArgsForCall(0)
+			Expect(handle).To(Equal(containerId))
+			Expect(protocols).To(Equal([]string{"udp"}))
+			Expect(port).To(Equal(uint16(1234)))
+		})
+	})
+
 	Describe("Cleanup", func() {
 		It("de-allocates all the ports", func() {
 			Expect(applier.Cleanup()).To(Succeed())
@@ -0,0 +1,12 @@
+package netrules
+
+import "fmt"
+
+type InvalidIPRangeError struct {
+	Range  IPRange
+	Reason string
+}
+
+func (e *InvalidIPRangeError) Error() string {
+	return fmt.Sprintf("invalid ip range %s: %s", e.Range, e.Reason)
+}
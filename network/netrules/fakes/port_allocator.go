@@ -8,11 +8,12 @@ import (
 )
 
 type PortAllocator struct {
-	AllocatePortStub        func(string, uint16) (uint16, error)
+	AllocatePortStub        func(string, []string, uint16) (uint16, error)
 	allocatePortMutex       sync.RWMutex
 	allocatePortArgsForCall []struct {
 		arg1 string
-		arg2 uint16
+		arg2 []string
+		arg3 uint16
 	}
 	allocatePortReturns struct {
 		result1 uint16
@@ -33,23 +34,42 @@ type PortAllocator struct {
 	releaseAllPortsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ReleasePortStub        func(string, []string, uint16) error
+	releasePortMutex       sync.RWMutex
+	releasePortArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 uint16
+	}
+	releasePortReturns struct {
+		result1 error
+	}
+	releasePortReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *PortAllocator) AllocatePort(arg1 string, arg2 uint16) (uint16, error) {
+func (fake *PortAllocator) AllocatePort(arg1 string, arg2 []string, arg3 uint16) (uint16, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
 	fake.allocatePortMutex.Lock()
 	ret, specificReturn := fake.allocatePortReturnsOnCall[len(fake.allocatePortArgsForCall)]
 	fake.allocatePortArgsForCall = append(fake.allocatePortArgsForCall, struct {
 		arg1 string
-		arg2 uint16
-	}{arg1, arg2})
+		arg2 []string
+		arg3 uint16
+	}{arg1, arg2Copy, arg3})
 	stub := fake.AllocatePortStub
 	fakeReturns := fake.allocatePortReturns
-	fake.recordInvocation("AllocatePort", []interface{}{arg1, arg2})
+	fake.recordInvocation("AllocatePort", []interface{}{arg1, arg2Copy, arg3})
 	fake.allocatePortMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -63,17 +83,17 @@ func (fake *PortAllocator) AllocatePortCallCount() int {
 	return len(fake.allocatePortArgsForCall)
 }
 
-func (fake *PortAllocator) AllocatePortCalls(stub func(string, uint16) (uint16, error)) {
+func (fake *PortAllocator) AllocatePortCalls(stub func(string, []string, uint16) (uint16, error)) {
 	fake.allocatePortMutex.Lock()
 	defer fake.allocatePortMutex.Unlock()
 	fake.AllocatePortStub = stub
 }
 
-func (fake *PortAllocator) AllocatePortArgsForCall(i int) (string, uint16) {
+func (fake *PortAllocator) AllocatePortArgsForCall(i int) (string, []string, uint16) {
 	fake.allocatePortMutex.RLock()
 	defer fake.allocatePortMutex.RUnlock()
 	argsForCall := fake.allocatePortArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *PortAllocator) AllocatePortReturns(result1 uint16, result2 error) {
@@ -163,6 +183,74 @@ func (fake *PortAllocator) ReleaseAllPortsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *PortAllocator) ReleasePort(arg1 string, arg2 []string, arg3 uint16) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.releasePortMutex.Lock()
+	ret, specificReturn := fake.releasePortReturnsOnCall[len(fake.releasePortArgsForCall)]
+	fake.releasePortArgsForCall = append(fake.releasePortArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 uint16
+	}{arg1, arg2Copy, arg3})
+	stub := fake.ReleasePortStub
+	fakeReturns := fake.releasePortReturns
+	fake.recordInvocation("ReleasePort", []interface{}{arg1, arg2Copy, arg3})
+	fake.releasePortMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PortAllocator) ReleasePortCallCount() int {
+	fake.releasePortMutex.RLock()
+	defer fake.releasePortMutex.RUnlock()
+	return len(fake.releasePortArgsForCall)
+}
+
+func (fake *PortAllocator) ReleasePortCalls(stub func(string, []string, uint16) error) {
+	fake.releasePortMutex.Lock()
+	defer fake.releasePortMutex.Unlock()
+	fake.ReleasePortStub = stub
+}
+
+func (fake *PortAllocator) ReleasePortArgsForCall(i int) (string, []string, uint16) {
+	fake.releasePortMutex.RLock()
+	defer fake.releasePortMutex.RUnlock()
+	argsForCall := fake.releasePortArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *PortAllocator) ReleasePortReturns(result1 error) {
+	fake.releasePortMutex.Lock()
+	defer fake.releasePortMutex.Unlock()
+	fake.ReleasePortStub = nil
+	fake.releasePortReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PortAllocator) ReleasePortReturnsOnCall(i int, result1 error) {
+	fake.releasePortMutex.Lock()
+	defer fake.releasePortMutex.Unlock()
+	fake.ReleasePortStub = nil
+	if fake.releasePortReturnsOnCall == nil {
+		fake.releasePortReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releasePortReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *PortAllocator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -170,6 +258,8 @@ func (fake *PortAllocator) Invocations() map[string][][]interface{} {
 	defer fake.allocatePortMutex.RUnlock()
 	fake.releaseAllPortsMutex.RLock()
 	defer fake.releaseAllPortsMutex.RUnlock()
+	fake.releasePortMutex.RLock()
+	defer fake.releasePortMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -8,11 +8,12 @@ import (
 )
 
 type PortAllocator struct {
-	AllocatePortStub        func(string, uint16) (uint16, error)
+	AllocatePortStub        func(string, []string, uint16) (uint16, error)
 	allocatePortMutex       sync.RWMutex
 	allocatePortArgsForCall []struct {
 		arg1 string
-		arg2 uint16
+		arg2 []string
+		arg3 uint16
 	}
 	allocatePortReturns struct {
 		result1 uint16
@@ -37,19 +38,25 @@ type PortAllocator struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *PortAllocator) AllocatePort(arg1 string, arg2 uint16) (uint16, error) {
+func (fake *PortAllocator) AllocatePort(arg1 string, arg2 []string, arg3 uint16) (uint16, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
 	fake.allocatePortMutex.Lock()
 	ret, specificReturn := fake.allocatePortReturnsOnCall[len(fake.allocatePortArgsForCall)]
 	fake.allocatePortArgsForCall = append(fake.allocatePortArgsForCall, struct {
 		arg1 string
-		arg2 uint16
-	}{arg1, arg2})
+		arg2 []string
+		arg3 uint16
+	}{arg1, arg2Copy, arg3})
 	stub := fake.AllocatePortStub
 	fakeReturns := fake.allocatePortReturns
-	fake.recordInvocation("AllocatePort", []interface{}{arg1, arg2})
+	fake.recordInvocation("AllocatePort", []interface{}{arg1, arg2Copy, arg3})
 	fake.allocatePortMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -63,17 +70,17 @@ func (fake *PortAllocator) AllocatePortCallCount() int {
 	return len(fake.allocatePortArgsForCall)
 }
 
-func (fake *PortAllocator) AllocatePortCalls(stub func(string, uint16) (uint16, error)) {
+func (fake *PortAllocator) AllocatePortCalls(stub func(string, []string, uint16) (uint16, error)) {
 	fake.allocatePortMutex.Lock()
 	defer fake.allocatePortMutex.Unlock()
 	fake.AllocatePortStub = stub
 }
 
-func (fake *PortAllocator) AllocatePortArgsForCall(i int) (string, uint16) {
+func (fake *PortAllocator) AllocatePortArgsForCall(i int) (string, []string, uint16) {
 	fake.allocatePortMutex.RLock()
 	defer fake.allocatePortMutex.RUnlock()
 	argsForCall := fake.allocatePortArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *PortAllocator) AllocatePortReturns(result1 uint16, result2 error) {
@@ -3,6 +3,7 @@ package firewallapplier
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"code.cloudfoundry.org/winc/network/firewall"
 	"code.cloudfoundry.org/winc/network/netrules"
@@ -16,7 +17,7 @@ type NetShRunner interface {
 
 //go:generate counterfeiter -o fakes/port_allocator.go --fake-name PortAllocator . PortAllocator
 type PortAllocator interface {
-	AllocatePort(handle string, port uint16) (uint16, error)
+	AllocatePort(handle string, protocols []string, port uint16) (uint16, error)
 	ReleaseAllPorts(handle string) error
 }
 
@@ -43,44 +44,63 @@ func NewApplier(netSh NetShRunner, containerId string, portAllocator PortAllocat
 	}
 }
 
-func (a *Applier) In(rule netrules.NetIn, containerIP string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error) {
+func (a *Applier) In(rule netrules.NetIn, containerIP string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error) {
+	protocols, err := rule.Protocols()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	externalPort := rule.HostPort
 
 	if externalPort == 0 {
-		allocatedPort, err := a.portAllocator.AllocatePort(a.containerId, 0)
+		allocatedPort, err := a.portAllocator.AllocatePort(a.containerId, protocols, 0)
 		if err != nil {
 			return nil, nil, err
 		}
 		externalPort = allocatedPort
 	}
 
-	fr := firewall.Rule{
-		Name:           a.containerId,
-		Action:         firewall.NET_FW_ACTION_ALLOW,
-		Direction:      firewall.NET_FW_RULE_DIR_IN,
-		Protocol:       firewall.NET_FW_IP_PROTOCOL_TCP,
-		LocalAddresses: containerIP,
-		LocalPorts:     strconv.FormatUint(uint64(rule.ContainerPort), 10),
-	}
+	nats := []*hcsshim.NatPolicy{}
 
-	if err := a.firewall.CreateRule(fr); err != nil {
-		return nil, nil, err
-	}
+	for _, protocol := range protocols {
+		fr := firewall.Rule{
+			Name:           a.containerId,
+			Action:         firewall.NET_FW_ACTION_ALLOW,
+			Direction:      firewall.NET_FW_RULE_DIR_IN,
+			Protocol:       netrules.FirewallProtocol(protocol),
+			LocalAddresses: containerIP,
+			LocalPorts:     strconv.FormatUint(uint64(rule.ContainerPort), 10),
+		}
 
-	if err := a.OpenPort(rule.ContainerPort); err != nil {
-		return nil, nil, err
+		if err := a.firewall.CreateRule(fr); err != nil {
+			return nil, nil, err
+		}
+
+		nats = append(nats, &hcsshim.NatPolicy{
+			Type:         hcsshim.Nat,
+			Protocol:     strings.ToUpper(protocol),
+			InternalPort: uint16(rule.ContainerPort),
+			ExternalPort: uint16(externalPort),
+		})
 	}
 
-	return &hcsshim.NatPolicy{
-		Type:         hcsshim.Nat,
-		Protocol:     "TCP",
-		InternalPort: uint16(rule.ContainerPort),
-		ExternalPort: uint16(externalPort),
-	}, nil, nil
+	// url reservations are only for HTTP, which is served over TCP
+	if protocols[0] == netrules.NetInProtocolTCP {
+		if err := a.OpenPort(rule.ContainerPort); err != nil {
+			return nil, nil, err
+		}
+	}
 
+	return nats, nil, nil
 }
 
 func (a *Applier) Out(rule netrules.NetOut, containerIP string) (*hcsshim.ACLPolicy, error) {
+	for _, ipr := range rule.Networks {
+		if err := ipr.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	fr := firewall.Rule{
 		Name:            a.containerId,
 		Action:          firewall.NET_FW_ACTION_ALLOW,
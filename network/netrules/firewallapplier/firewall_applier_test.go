@@ -60,7 +60,7 @@ var _ = Describe("Firewall Applier", func() {
 		})
 
 		It("returns the correct Nat Policy", func() {
-			nat, _, err := applier.In(netInRule, containerIP)
+			nats, _, err := applier.In(netInRule, containerIP)
 			Expect(err).NotTo(HaveOccurred())
 
 			expectedNat := hcsshim.NatPolicy{
@@ -70,7 +70,7 @@ var _ = Describe("Firewall Applier", func() {
 				ExternalPort: 2000,
 			}
 
-			Expect(*nat).To(Equal(expectedNat))
+			Expect(nats).To(Equal([]*hcsshim.NatPolicy{&expectedNat}))
 		})
 
 		It("opens the port inside the container", func() {
@@ -93,6 +93,38 @@ var _ = Describe("Firewall Applier", func() {
 			})
 		})
 
+		Context("the rule is for both TCP and UDP", func() {
+			BeforeEach(func() {
+				netInRule.Protocol = "both"
+			})
+
+			It("creates a firewall rule and returns a Nat Policy for each protocol", func() {
+				nats, _, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fw.CreateRuleCallCount()).To(Equal(2))
+				Expect(fw.CreateRuleArgsForCall(0).Protocol).To(Equal(firewall.NET_FW_IP_PROTOCOL_TCP))
+				Expect(fw.CreateRuleArgsForCall(1).Protocol).To(Equal(firewall.NET_FW_IP_PROTOCOL_UDP))
+
+				Expect(nats).To(HaveLen(2))
+				Expect(nats[0].Protocol).To(Equal("TCP"))
+				Expect(nats[1].Protocol).To(Equal("UDP"))
+				Expect(nats[1].ExternalPort).To(Equal(uint16(2000)))
+			})
+		})
+
+		Context("the rule is for UDP", func() {
+			BeforeEach(func() {
+				netInRule.Protocol = "udp"
+			})
+
+			It("doesn't open the port for HTTP", func() {
+				_, _, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(netSh.RunContainerCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("the host port is zero", func() {
 			BeforeEach(func() {
 				netInRule = netrules.NetIn{
@@ -104,7 +136,7 @@ var _ = Describe("Firewall Applier", func() {
 
 			It("uses the port allocator to find an open host port", func() {
 
-				nat, _, err := applier.In(netInRule, containerIP)
+				nats, _, err := applier.In(netInRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
 				expectedNat := hcsshim.NatPolicy{
@@ -114,11 +146,12 @@ var _ = Describe("Firewall Applier", func() {
 					ExternalPort: 1234,
 				}
 
-				Expect(*nat).To(Equal(expectedNat))
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{&expectedNat}))
 
 				Expect(portAllocator.AllocatePortCallCount()).To(Equal(1))
-				id, p := portAllocator.AllocatePortArgsForCall(0)
+				id, protocols, p := portAllocator.AllocatePortArgsForCall(0)
 				Expect(id).To(Equal(containerId))
+				Expect(protocols).To(Equal([]string{"tcp"}))
 				Expect(p).To(Equal(uint16(0)))
 			})
 
@@ -255,6 +288,21 @@ var _ = Describe("Firewall Applier", func() {
 			})
 		})
 
+		Context("an ip range mixes IPv4 and IPv6", func() {
+			JustBeforeEach(func() {
+				netOutRule.Networks = []netrules.IPRange{
+					{Start: net.ParseIP("10.0.0.0"), End: net.ParseIP("2001:db8::1")},
+				}
+			})
+
+			It("returns an error", func() {
+				_, err := applier.Out(netOutRule, containerIP)
+
+				Expect(err).To(MatchError("invalid ip range 10.0.0.0-2001:db8::1: start and end must both be IPv4 or both be IPv6 addresses"))
+				Expect(fw.CreateRuleCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("an invalid protocol is specified", func() {
 			BeforeEach(func() {
 				protocol = 7
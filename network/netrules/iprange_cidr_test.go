@@ -66,6 +66,37 @@ var _ = Describe("IPRangeToCIDRs", func() {
 				"5.6.7.0/29",
 				"5.6.7.8/32"},
 		),
+
+		Entry("::-::", ipRange("::-::"), []string{"::/128"}),
+		Entry("::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", ipRange("::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"), []string{"::/0"}),
+		Entry("2001:db8::-2001:db8::ffff", ipRange("2001:db8::-2001:db8::ffff"), []string{"2001:db8::/112"}),
+		Entry("2001:db8::1-2001:db8::2", ipRange("2001:db8::1-2001:db8::2"), []string{"2001:db8::1/128", "2001:db8::2/128"}),
+		Entry("2001:db8::ff-2001:db8::1:0", ipRange("2001:db8::ff-2001:db8::1:0"),
+			[]string{
+				"2001:db8::ff/128",
+				"2001:db8::100/120",
+				"2001:db8::200/119",
+				"2001:db8::400/118",
+				"2001:db8::800/117",
+				"2001:db8::1000/116",
+				"2001:db8::2000/115",
+				"2001:db8::4000/114",
+				"2001:db8::8000/113",
+				"2001:db8::1:0/128"},
+		),
+	)
+
+	DescribeTable("rejecting invalid IP ranges",
+		func(ipr netrules.IPRange, reason string) {
+			_, err := netrules.IPRangeToCIDRs(ipr)
+			Expect(err).To(MatchError(&netrules.InvalidIPRangeError{Range: ipr, Reason: reason}))
+		},
+
+		Entry("IPv4 start and IPv6 end", ipRange("10.0.0.0-2001:db8::"), "start and end must both be IPv4 or both be IPv6 addresses"),
+		Entry("IPv6 start and IPv4 end", ipRange("2001:db8::-10.0.0.0"), "start and end must both be IPv4 or both be IPv6 addresses"),
+		Entry("missing end", netrules.IPRange{Start: net.ParseIP("10.0.0.0")}, "start and end are required"),
+		Entry("IPv4 start after end", ipRange("10.0.0.2-10.0.0.1"), "start must not be after end"),
+		Entry("IPv6 start after end", ipRange("2001:db8::2-2001:db8::1"), "start must not be after end"),
 	)
 })
 
@@ -2,18 +2,54 @@ package netrules
 
 import (
 	"fmt"
+	"math/big"
 	"net"
 	"strings"
+
+	"code.cloudfoundry.org/winc/network/firewall"
 )
 
 type PortMapping struct {
 	HostPort      uint16
 	ContainerPort uint16
+	Protocol      string
 }
 
+// The protocols a NetIn rule can map, which is TCP if unset
+const (
+	NetInProtocolTCP  = "tcp"
+	NetInProtocolUDP  = "udp"
+	NetInProtocolBoth = "both"
+)
+
 type NetIn struct {
 	HostPort      uint16 `json:"host_port"`
 	ContainerPort uint16 `json:"container_port"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// Protocols returns the protocols a NetIn rule maps, each of which is
+// NetInProtocolTCP or NetInProtocolUDP
+func (n NetIn) Protocols() ([]string, error) {
+	switch n.Protocol {
+	case "", NetInProtocolTCP:
+		return []string{NetInProtocolTCP}, nil
+	case NetInProtocolUDP:
+		return []string{NetInProtocolUDP}, nil
+	case NetInProtocolBoth:
+		return []string{NetInProtocolTCP, NetInProtocolUDP}, nil
+	default:
+		return nil, fmt.Errorf("invalid netin protocol: %s", n.Protocol)
+	}
+}
+
+// FirewallProtocol converts one of the protocols returned by NetIn.Protocols
+// for windows firewall
+func FirewallProtocol(protocol string) firewall.Protocol {
+	if protocol == NetInProtocolUDP {
+		return firewall.NET_FW_IP_PROTOCOL_UDP
+	}
+	return firewall.NET_FW_IP_PROTOCOL_TCP
 }
 
 type NetOut struct {
@@ -72,55 +108,77 @@ func FirewallRulePortRange(ports []PortRange) string {
 	return strings.Join(output, ",")
 }
 
-func IPRangeToCIDRs(iprange IPRange) []string {
-	start := ipToUint(iprange.Start)
-	end := ipToUint(iprange.End)
+// Validate checks that a range has both a start and an end, that they are
+// either both IPv4 or both IPv6 addresses, and that start is not after end
+func (ir IPRange) Validate() error {
+	if ir.Start == nil || ir.End == nil {
+		return &InvalidIPRangeError{Range: ir, Reason: "start and end are required"}
+	}
+
+	if (ir.Start.To4() == nil) != (ir.End.To4() == nil) {
+		return &InvalidIPRangeError{Range: ir, Reason: "start and end must both be IPv4 or both be IPv6 addresses"}
+	}
+
+	if ipToInt(ir.Start).Cmp(ipToInt(ir.End)) > 0 {
+		return &InvalidIPRangeError{Range: ir, Reason: "start must not be after end"}
+	}
+
+	return nil
+}
+
+// IPRangeToCIDRs returns the smallest list of CIDR blocks that exactly covers
+// an IPv4 or IPv6 range
+func IPRangeToCIDRs(iprange IPRange) ([]string, error) {
+	if err := iprange.Validate(); err != nil {
+		return nil, err
+	}
+
+	bits := 32
+	if iprange.Start.To4() == nil {
+		bits = 128
+	}
+
+	start := ipToInt(iprange.Start)
+	end := ipToInt(iprange.End)
 	r := []string{}
 
-	for start <= end {
-		maskLen := uint32(32)
-		for maskLen > 0 {
-			if start != first(start, maskLen-1) || end < last(start, maskLen-1) {
+	for start.Cmp(end) <= 0 {
+		hostBits := 0
+		for hostBits < bits {
+			if !aligned(start, hostBits+1) || last(start, hostBits+1).Cmp(end) > 0 {
 				break
 			}
-			maskLen--
-		}
-
-		r = append(r, cidrFromIntMask(start, maskLen))
-		start = last(start, maskLen)
-		if start == 0xffffffff {
-			break
+			hostBits++
 		}
 
-		start++
+		r = append(r, cidrFromInt(start, bits, bits-hostBits))
+		start = new(big.Int).Add(last(start, hostBits), big.NewInt(1))
 	}
 
-	return r
+	return r, nil
 }
 
-func ipToUint(ip net.IP) uint32 {
-	ip = ip.To4()
-	return uint32(ip[0])<<24 + uint32(ip[1])<<16 + uint32(ip[2])<<8 + uint32(ip[3])
+func ipToInt(ip net.IP) *big.Int {
+	if ip4 := ip.To4(); ip4 != nil {
+		return new(big.Int).SetBytes(ip4)
+	}
+	return new(big.Int).SetBytes(ip.To16())
 }
 
-func cidrFromIntMask(start uint32, maskLen uint32) string {
-	ip := net.IPv4(
-		byte((start&0xff000000)>>24),
-		byte((start&0x00ff0000)>>16),
-		byte((start&0x0000ff00)>>8),
-		byte(start&0x000000ff),
-	)
+func cidrFromInt(start *big.Int, bits, maskLen int) string {
+	ip := net.IP(start.FillBytes(make([]byte, bits/8)))
 	return fmt.Sprintf("%s/%d", ip.String(), maskLen)
 }
 
-func first(start uint32, maskLen uint32) uint32 {
-	return start & bitMask(maskLen)
-}
-
-func last(start uint32, maskLen uint32) uint32 {
-	return (start & bitMask(maskLen)) | (^bitMask(maskLen))
+// aligned reports whether start is the first address of a block with
+// hostBits host bits
+func aligned(start *big.Int, hostBits int) bool {
+	return start.Sign() == 0 || start.TrailingZeroBits() >= uint(hostBits)
 }
 
-func bitMask(len uint32) uint32 {
-	return 0xffffffff << (32 - len)
+// last returns the last address of the block with hostBits host bits that
+// starts at start
+func last(start *big.Int, hostBits int) *big.Int {
+	size := new(big.Int).Lsh(big.NewInt(1), uint(hostBits))
+	return size.Add(size, start).Sub(size, big.NewInt(1))
 }
@@ -16,16 +16,18 @@ import (
 
 //go:generate counterfeiter -o fakes/net_rule_applier.go --fake-name NetRuleApplier . NetRuleApplier
 type NetRuleApplier interface {
-	In(netrules.NetIn, string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error)
+	In(netrules.NetIn, string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error)
 	Out(netrules.NetOut, string) (*hcsshim.ACLPolicy, error)
 	Cleanup() error
 	OpenPort(port uint32) error
+	ReleasePort(string, uint16) error
 }
 
 //go:generate counterfeiter -o fakes/mtu.go --fake-name Mtu . Mtu
 type Mtu interface {
 	SetNat(int) error
-	SetContainer(int) error
+	SetContainer(int, bool) error
+	GetContainer(bool) (int, error)
 }
 
 //go:generate counterfeiter -o fakes/endpoint_manager.go --fake-name EndpointManager . EndpointManager
@@ -33,6 +35,8 @@ type EndpointManager interface {
 	Create() (hcsshim.HNSEndpoint, error)
 	Delete() error
 	ApplyPolicies(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)
+	Get() (hcsshim.HNSEndpoint, error)
+	UpdatePolicies(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)
 }
 
 //go:generate counterfeiter -o fakes/hcs_client.go --fake-name HCSClient . HCSClient
@@ -47,6 +51,8 @@ type Config struct {
 	NetworkName                   string   `json:"network_name"`
 	SubnetRange                   string   `json:"subnet_range"`
 	GatewayAddress                string   `json:"gateway_address"`
+	SubnetRangeIPv6               string   `json:"subnet_range_ipv6"`
+	GatewayAddressIPv6            string   `json:"gateway_address_ipv6"`
 	DNSServers                    []string `json:"dns_servers"`
 	MaximumOutgoingBandwidth      uint64   `json:"maximum_outgoing_bandwidth"`
 	DNSSuffix                     []string `json:"search_domains"`
@@ -70,6 +76,7 @@ func (u *UpInputs) IsEmpty() bool {
 type UpOutputs struct {
 	Properties struct {
 		ContainerIP      string `json:"garden.network.container-ip"`
+		ContainerIPv6    string `json:"garden.network.container-ipv6,omitempty"`
 		DeprecatedHostIP string `json:"garden.network.host-ip"`
 		MappedPorts      string `json:"garden.network.mapped-ports"`
 	} `json:"properties"`
@@ -104,10 +111,13 @@ func (n *NetworkManager) CreateHostNATNetwork() error {
 		}
 	}
 
-	subnets := []hcsshim.Subnet{{AddressPrefix: n.config.SubnetRange, GatewayAddress: n.config.GatewayAddress}}
+	subnets, err := n.subnets()
+	if err != nil {
+		return err
+	}
 
 	if existingNetwork != nil {
-		if len(existingNetwork.Subnets) == 1 && subnetsMatch(existingNetwork.Subnets[0], subnets[0]) {
+		if allSubnetsMatch(existingNetwork.Subnets, subnets) {
 			return nil
 		}
 
@@ -143,6 +153,62 @@ func (n *NetworkManager) CreateHostNATNetwork() error {
 	return n.mtu.SetNat(n.config.MTU)
 }
 
+// subnets returns the IPv4 subnet, followed by the IPv6 subnet when the
+// network is configured to be dual-stack
+func (n *NetworkManager) subnets() ([]hcsshim.Subnet, error) {
+	subnets := []hcsshim.Subnet{{AddressPrefix: n.config.SubnetRange, GatewayAddress: n.config.GatewayAddress}}
+
+	if n.config.SubnetRangeIPv6 == "" && n.config.GatewayAddressIPv6 == "" {
+		return subnets, nil
+	}
+
+	if err := validateIPv6Subnet(n.config.SubnetRangeIPv6, n.config.GatewayAddressIPv6); err != nil {
+		return nil, err
+	}
+
+	return append(subnets, hcsshim.Subnet{AddressPrefix: n.config.SubnetRangeIPv6, GatewayAddress: n.config.GatewayAddressIPv6}), nil
+}
+
+func validateIPv6Subnet(subnetRange, gatewayAddress string) error {
+	invalid := func(reason string) error {
+		return &InvalidIPv6SubnetError{SubnetRange: subnetRange, GatewayAddress: gatewayAddress, Reason: reason}
+	}
+
+	if subnetRange == "" || gatewayAddress == "" {
+		return invalid("subnet range and gateway address are both required")
+	}
+
+	ip, ipNet, err := net.ParseCIDR(subnetRange)
+	if err != nil || ip.To4() != nil {
+		return invalid("subnet range must be an IPv6 CIDR")
+	}
+
+	gateway := net.ParseIP(gatewayAddress)
+	if gateway == nil || gateway.To4() != nil {
+		return invalid("gateway address must be an IPv6 address")
+	}
+
+	if !ipNet.Contains(gateway) {
+		return invalid("gateway address must be within the subnet range")
+	}
+
+	return nil
+}
+
+func allSubnetsMatch(existing, desired []hcsshim.Subnet) bool {
+	if len(existing) != len(desired) {
+		return false
+	}
+
+	for i := range desired {
+		if !subnetsMatch(existing[i], desired[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func subnetsMatch(a, b hcsshim.Subnet) bool {
 	return (a.AddressPrefix == b.AddressPrefix) && (a.GatewayAddress == b.GatewayAddress)
 }
@@ -195,18 +261,13 @@ func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 	hnsNats := []*hcsshim.NatPolicy{}
 
 	for _, rule := range inputs.NetIn {
-		nat, acl, err := n.applier.In(rule, createdEndpoint.IPAddress.String())
+		nats, acls, err := n.applier.In(rule, createdEndpoint.IPAddress.String())
 		if err != nil {
 			return outputs, err
 		}
 
-		if nat != nil {
-			hnsNats = append(hnsNats, nat)
-		}
-
-		if acl != nil {
-			hnsAcls = append(hnsAcls, acl)
-		}
+		hnsNats = append(hnsNats, nats...)
+		hnsAcls = append(hnsAcls, acls...)
 	}
 
 	// This is required for running .NET applications
@@ -238,9 +299,31 @@ func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 		logrus.Debugf("input.Properties doesn't contain ports - .Net apps aren't supported")
 	}
 
+	outAcls, err := n.netOut(inputs.NetOut, createdEndpoint)
+	if err != nil {
+		return outputs, err
+	}
+	hnsAcls = append(hnsAcls, outAcls...)
+
+	if _, err := n.endpointManager.ApplyPolicies(createdEndpoint, hnsNats, hnsAcls); err != nil {
+		return outputs, err
+	}
+	logrus.Debugf("applied network mappings %s", createdEndpoint.Name)
+
+	if err := n.mtu.SetContainer(n.config.MTU, createdEndpoint.IPv6Address != nil); err != nil {
+		return outputs, err
+	}
+	logrus.Debugf("applied container MTU %d", n.config.MTU)
+
+	return upOutputs(createdEndpoint, hnsNats)
+}
+
+// netOut returns the ACL policies for the given egress rules, along with
+// rules allowing DNS to the configured servers
+func (n *NetworkManager) netOut(rules []netrules.NetOut, endpoint hcsshim.HNSEndpoint) ([]*hcsshim.ACLPolicy, error) {
 	for _, dnsServer := range n.config.DNSServers {
 		serverIP := net.ParseIP(dnsServer)
-		inputs.NetOut = append(inputs.NetOut,
+		rules = append(rules,
 			netrules.NetOut{
 				Protocol: netrules.ProtocolTCP,
 				Networks: []netrules.IPRange{{Start: serverIP, End: serverIP}},
@@ -254,32 +337,36 @@ func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 		)
 	}
 
-	for _, rule := range inputs.NetOut {
-		acl, err := n.applier.Out(rule, createdEndpoint.IPAddress.String())
+	// egress rules apply to both of a dual-stack container's addresses
+	localAddresses := endpoint.IPAddress.String()
+	if endpoint.IPv6Address != nil {
+		localAddresses = fmt.Sprintf("%s,%s", localAddresses, endpoint.IPv6Address)
+	}
+
+	acls := []*hcsshim.ACLPolicy{}
+	for _, rule := range rules {
+		acl, err := n.applier.Out(rule, localAddresses)
 		if err != nil {
-			return outputs, err
+			return nil, err
 		}
 
 		if acl != nil {
-			hnsAcls = append(hnsAcls, acl)
+			acls = append(acls, acl)
 		}
 	}
 
-	if _, err := n.endpointManager.ApplyPolicies(createdEndpoint, hnsNats, hnsAcls); err != nil {
-		return outputs, err
-	}
-	logrus.Debugf("applied network mappings %s", createdEndpoint.Name)
+	return acls, nil
+}
 
-	if err := n.mtu.SetContainer(n.config.MTU); err != nil {
-		return outputs, err
-	}
-	logrus.Debugf("applied container MTU %d", n.config.MTU)
+func upOutputs(endpoint hcsshim.HNSEndpoint, nats []*hcsshim.NatPolicy) (UpOutputs, error) {
+	outputs := UpOutputs{}
 
 	mappedPorts := []netrules.PortMapping{}
-	for _, nat := range hnsNats {
+	for _, nat := range nats {
 		mappedPorts = append(mappedPorts, netrules.PortMapping{
 			ContainerPort: nat.InternalPort,
 			HostPort:      nat.ExternalPort,
+			Protocol:      strings.ToLower(nat.Protocol),
 		})
 	}
 	portBytes, err := json.Marshal(mappedPorts)
@@ -288,12 +375,88 @@ func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 	}
 
 	outputs.Properties.MappedPorts = string(portBytes)
-	outputs.Properties.ContainerIP = createdEndpoint.IPAddress.String()
+	outputs.Properties.ContainerIP = endpoint.IPAddress.String()
+	if endpoint.IPv6Address != nil {
+		outputs.Properties.ContainerIPv6 = endpoint.IPv6Address.String()
+	}
 	outputs.Properties.DeprecatedHostIP = "255.255.255.255"
 
 	return outputs, nil
 }
 
+// Update replaces the NetIn and NetOut rules of a running container's
+// endpoint. NetIn rules that are already mapped keep their host port; ports
+// allocated for rules that are no longer requested are not released until Down.
+// Application ports are not reopened, as their url reservations already exist.
+func (n *NetworkManager) Update(inputs UpInputs) (UpOutputs, error) {
+	logrus.Debugf("start networkmanager update %s", n.containerId)
+
+	// see Up
+	if inputs.IsEmpty() && n.config.AllowOutboundTrafficByDefault {
+		inputs.NetOut = []netrules.NetOut{{Protocol: netrules.ProtocolAll}}
+	}
+
+	endpoint, err := n.endpointManager.Get()
+	if err != nil {
+		return UpOutputs{}, err
+	}
+
+	current, err := DecodePolicies(endpoint.Policies)
+	if err != nil {
+		return UpOutputs{}, err
+	}
+
+	hnsAcls := []*hcsshim.ACLPolicy{}
+	hnsNats := []*hcsshim.NatPolicy{}
+
+	for _, rule := range inputs.NetIn {
+		nats, acls, mapped := current.netInMapping(rule)
+		if !mapped {
+			nats, acls, err = n.applier.In(rule, endpoint.IPAddress.String())
+			if err != nil {
+				return UpOutputs{}, err
+			}
+		}
+
+		hnsNats = append(hnsNats, nats...)
+		hnsAcls = append(hnsAcls, acls...)
+	}
+
+	outAcls, err := n.netOut(inputs.NetOut, endpoint)
+	if err != nil {
+		return UpOutputs{}, err
+	}
+	hnsAcls = append(hnsAcls, outAcls...)
+
+	if _, err := n.endpointManager.UpdatePolicies(endpoint, hnsNats, hnsAcls); err != nil {
+		return UpOutputs{}, err
+	}
+
+	// host ports of the rules the update removed can be allocated again
+	for _, nat := range current.NATs {
+		if mapsHostPort(hnsNats, nat) {
+			continue
+		}
+		if err := n.applier.ReleasePort(strings.ToLower(nat.Protocol), nat.ExternalPort); err != nil {
+			return UpOutputs{}, err
+		}
+	}
+	logrus.Debugf("finished networkmanager update %s", n.containerId)
+
+	return upOutputs(endpoint, hnsNats)
+}
+
+// mapsHostPort reports whether one of nats maps the host port of nat for the
+// same protocol
+func mapsHostPort(nats []*hcsshim.NatPolicy, nat *hcsshim.NatPolicy) bool {
+	for _, n := range nats {
+		if n.ExternalPort == nat.ExternalPort && strings.EqualFold(n.Protocol, nat.Protocol) {
+			return true
+		}
+	}
+	return false
+}
+
 func (n *NetworkManager) Down() error {
 	deleteErr := n.endpointManager.Delete()
 	cleanupErr := n.applier.Cleanup()
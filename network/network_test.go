@@ -1,6 +1,7 @@
 package network_test
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"net"
@@ -139,6 +140,74 @@ var _ = Describe("NetworkManager", func() {
 			})
 		})
 
+		Context("an IPv6 subnet is configured", func() {
+			BeforeEach(func() {
+				config.SubnetRangeIPv6 = "fd00:1234::/64"
+				config.GatewayAddressIPv6 = "fd00:1234::1"
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+			})
+
+			It("creates a dual-stack network", func() {
+				Expect(networkManager.CreateHostNATNetwork()).To(Succeed())
+
+				net, _ := hcsClient.CreateNetworkArgsForCall(0)
+				Expect(net.Subnets).To(Equal([]hcsshim.Subnet{
+					{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"},
+					{AddressPrefix: "fd00:1234::/64", GatewayAddress: "fd00:1234::1"},
+				}))
+			})
+
+			Context("the network already exists with only the IPv4 subnet", func() {
+				BeforeEach(func() {
+					hnsNetwork = &hcsshim.HNSNetwork{
+						Name:    "unit-test-name",
+						Subnets: []hcsshim.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}},
+					}
+					hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
+				})
+
+				It("returns an error", func() {
+					err := networkManager.CreateHostNATNetwork()
+					Expect(err).To(BeAssignableToTypeOf(&network.SameNATNetworkNameError{}))
+				})
+			})
+
+			Context("the network already exists with both subnets", func() {
+				BeforeEach(func() {
+					hnsNetwork = &hcsshim.HNSNetwork{
+						Name: "unit-test-name",
+						Subnets: []hcsshim.Subnet{
+							{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"},
+							{AddressPrefix: "fd00:1234::/64", GatewayAddress: "fd00:1234::1"},
+						},
+					}
+					hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
+				})
+
+				It("does not create the network", func() {
+					Expect(networkManager.CreateHostNATNetwork()).To(Succeed())
+					Expect(hcsClient.CreateNetworkCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		DescribeTable("the IPv6 subnet is invalid",
+			func(subnetRange, gatewayAddress, reason string) {
+				config.SubnetRangeIPv6 = subnetRange
+				config.GatewayAddressIPv6 = gatewayAddress
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+
+				err := networkManager.CreateHostNATNetwork()
+				Expect(err).To(MatchError(&network.InvalidIPv6SubnetError{SubnetRange: subnetRange, GatewayAddress: gatewayAddress, Reason: reason}))
+				Expect(hcsClient.CreateNetworkCallCount()).To(Equal(0))
+			},
+			Entry("missing gateway", "fd00:1234::/64", "", "subnet range and gateway address are both required"),
+			Entry("missing subnet", "", "fd00:1234::1", "subnet range and gateway address are both required"),
+			Entry("IPv4 subnet", "10.0.0.0/24", "fd00:1234::1", "subnet range must be an IPv6 CIDR"),
+			Entry("IPv4 gateway", "fd00:1234::/64", "10.0.0.1", "gateway address must be an IPv6 address"),
+			Entry("gateway outside subnet", "fd00:1234::/64", "fd00:5678::1", "gateway address must be within the subnet range"),
+		)
+
 		Context("GetHNSNetwork returns a non network not found error", func() {
 			BeforeEach(func() {
 				hcsClient.GetHNSNetworkByNameReturns(nil, errors.New("some HNS error"))
@@ -289,8 +358,8 @@ var _ = Describe("NetworkManager", func() {
 				Protocol:  17,
 			}
 
-			netRuleApplier.InReturnsOnCall(0, nat1, inAcl1, nil)
-			netRuleApplier.InReturnsOnCall(1, nat2, inAcl2, nil)
+			netRuleApplier.InReturnsOnCall(0, []*hcsshim.NatPolicy{nat1}, []*hcsshim.ACLPolicy{inAcl1}, nil)
+			netRuleApplier.InReturnsOnCall(1, []*hcsshim.NatPolicy{nat2}, []*hcsshim.ACLPolicy{inAcl2}, nil)
 
 			netRuleApplier.OutReturnsOnCall(0, outAcl1, nil)
 			netRuleApplier.OutReturnsOnCall(1, outAcl2, nil)
@@ -304,7 +373,7 @@ var _ = Describe("NetworkManager", func() {
 
 			Expect(output.Properties.ContainerIP).To(Equal(containerIP.String()))
 			Expect(output.Properties.DeprecatedHostIP).To(Equal("255.255.255.255"))
-			Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":111,"ContainerPort":666},{"HostPort":222,"ContainerPort":888}]`))
+			Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":111,"ContainerPort":666,"Protocol":"tcp"},{"HostPort":222,"ContainerPort":888,"Protocol":"tcp"}]`))
 
 			Expect(endpointManager.CreateCallCount()).To(Equal(1))
 
@@ -355,8 +424,66 @@ var _ = Describe("NetworkManager", func() {
 			Expect(receivedAclPolicies).To(Equal(expectedAclPolicies))
 
 			Expect(mtu.SetContainerCallCount()).To(Equal(1))
-			receivedMtu := mtu.SetContainerArgsForCall(0)
+			receivedMtu, ipv6 := mtu.SetContainerArgsForCall(0)
 			Expect(receivedMtu).To(Equal(1434))
+			Expect(ipv6).To(BeFalse())
+		})
+
+		Context("when a netin rule maps both TCP and UDP", func() {
+			BeforeEach(func() {
+				udpNat := *nat1
+				udpNat.Protocol = "UDP"
+				udpAcl := *inAcl1
+				udpAcl.Protocol = 17
+
+				netRuleApplier.InReturnsOnCall(0, []*hcsshim.NatPolicy{nat1, &udpNat}, []*hcsshim.ACLPolicy{inAcl1, &udpAcl}, nil)
+			})
+
+			It("applies the policies for both and maps the port for each protocol", func() {
+				output, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":111,"ContainerPort":666,"Protocol":"tcp"},{"HostPort":111,"ContainerPort":666,"Protocol":"udp"},{"HostPort":222,"ContainerPort":888,"Protocol":"tcp"}]`))
+
+				_, nats, acls := endpointManager.ApplyPoliciesArgsForCall(0)
+				Expect(nats).To(HaveLen(3))
+				Expect(acls).To(HaveLen(5))
+			})
+		})
+
+		Context("when the endpoint is dual-stack", func() {
+			BeforeEach(func() {
+				createdEndpoint.IPv6Address = net.ParseIP("fd00:1234::5")
+				endpointManager.CreateReturns(createdEndpoint, nil)
+			})
+
+			It("reports the container's IPv6 address", func() {
+				output, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(output.Properties.ContainerIP).To(Equal("111.222.33.44"))
+				Expect(output.Properties.ContainerIPv6).To(Equal("fd00:1234::5"))
+			})
+
+			It("applies net out rules to both addresses and net in rules to the IPv4 address", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, inIP := netRuleApplier.InArgsForCall(0)
+				Expect(inIP).To(Equal("111.222.33.44"))
+
+				_, outIPs := netRuleApplier.OutArgsForCall(0)
+				Expect(outIPs).To(Equal("111.222.33.44,fd00:1234::5"))
+			})
+
+			It("applies the mtu to the IPv6 interface as well", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				receivedMtu, ipv6 := mtu.SetContainerArgsForCall(0)
+				Expect(receivedMtu).To(Equal(1434))
+				Expect(ipv6).To(BeTrue())
+			})
 		})
 
 		Context("when the config specifies DNS servers", func() {
@@ -569,6 +696,198 @@ var _ = Describe("NetworkManager", func() {
 		})
 	})
 
+	Describe("Update", func() {
+		var (
+			inputs   network.UpInputs
+			endpoint hcsshim.HNSEndpoint
+			outAcl   *hcsshim.ACLPolicy
+		)
+
+		BeforeEach(func() {
+			endpoint = hcsshim.HNSEndpoint{
+				Id:        "some-endpoint-id",
+				IPAddress: net.ParseIP("111.222.33.44"),
+				Policies: []json.RawMessage{
+					json.RawMessage(`{"Type":"NAT","Protocol":"TCP","InternalPort":8080,"ExternalPort":40001}`),
+					json.RawMessage(`{"Type":"ACL","Id":"hns-assigned","Action":"Allow","Direction":"In","Protocol":6,"LocalAddresses":"111.222.33.44","LocalPorts":"8080"}`),
+					json.RawMessage(`{"Type":"ACL","Action":"Allow","Direction":"Out","Protocol":6}`),
+				},
+			}
+			endpointManager.GetReturns(endpoint, nil)
+
+			outAcl = &hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.Out, Action: hcsshim.Allow, Protocol: 17}
+			netRuleApplier.OutReturns(outAcl, nil)
+
+			inputs = network.UpInputs{
+				NetIn:  []netrules.NetIn{{ContainerPort: 8080}},
+				NetOut: []netrules.NetOut{{Protocol: netrules.ProtocolUDP}},
+			}
+		})
+
+		It("keeps existing port mappings and replaces the egress rules", func() {
+			output, err := networkManager.Update(inputs)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(netRuleApplier.InCallCount()).To(Equal(0))
+
+			Expect(netRuleApplier.OutCallCount()).To(Equal(1))
+			rule, localAddresses := netRuleApplier.OutArgsForCall(0)
+			Expect(rule).To(Equal(netrules.NetOut{Protocol: netrules.ProtocolUDP}))
+			Expect(localAddresses).To(Equal("111.222.33.44"))
+
+			Expect(endpointManager.UpdatePoliciesCallCount()).To(Equal(1))
+			ep, nats, acls := endpointManager.UpdatePoliciesArgsForCall(0)
+			Expect(ep).To(Equal(endpoint))
+			Expect(nats).To(Equal([]*hcsshim.NatPolicy{{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 8080, ExternalPort: 40001}}))
+			Expect(acls).To(Equal([]*hcsshim.ACLPolicy{
+				{Type: hcsshim.ACL, Action: hcsshim.Allow, Direction: hcsshim.In, Protocol: 6, LocalAddresses: "111.222.33.44", LocalPorts: "8080"},
+				outAcl,
+			}))
+
+			Expect(output.Properties.ContainerIP).To(Equal("111.222.33.44"))
+			Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":40001,"ContainerPort":8080,"Protocol":"tcp"}]`))
+		})
+
+		It("does not tear down the endpoint", func() {
+			_, err := networkManager.Update(inputs)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(endpointManager.CreateCallCount()).To(Equal(0))
+			Expect(endpointManager.DeleteCallCount()).To(Equal(0))
+			Expect(endpointManager.ApplyPoliciesCallCount()).To(Equal(0))
+		})
+
+		It("does not release the host ports that are still mapped", func() {
+			_, err := networkManager.Update(inputs)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(netRuleApplier.ReleasePortCallCount()).To(Equal(0))
+		})
+
+		Context("a netin rule is not yet mapped", func() {
+			var newNat *hcsshim.NatPolicy
+
+			BeforeEach(func() {
+				inputs.NetIn = append(inputs.NetIn, netrules.NetIn{ContainerPort: 9090, Protocol: netrules.NetInProtocolBoth})
+
+				newNat = &hcsshim.NatPolicy{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 9090, ExternalPort: 40002}
+				netRuleApplier.InReturns([]*hcsshim.NatPolicy{newNat}, nil, nil)
+			})
+
+			It("maps it", func() {
+				_, err := networkManager.Update(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(netRuleApplier.InCallCount()).To(Equal(1))
+				rule, containerIP := netRuleApplier.InArgsForCall(0)
+				Expect(rule).To(Equal(netrules.NetIn{ContainerPort: 9090, Protocol: netrules.NetInProtocolBoth}))
+				Expect(containerIP).To(Equal("111.222.33.44"))
+
+				_, nats, _ := endpointManager.UpdatePoliciesArgsForCall(0)
+				Expect(nats).To(HaveLen(2))
+				Expect(nats[1]).To(Equal(newNat))
+			})
+		})
+
+		Context("a netin rule is no longer requested", func() {
+			BeforeEach(func() {
+				inputs.NetIn = nil
+			})
+
+			It("removes its mapping", func() {
+				_, err := networkManager.Update(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, nats, acls := endpointManager.UpdatePoliciesArgsForCall(0)
+				Expect(nats).To(BeEmpty())
+				Expect(acls).To(Equal([]*hcsshim.ACLPolicy{outAcl}))
+			})
+
+			It("releases its host port after updating the endpoint", func() {
+				_, err := networkManager.Update(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(netRuleApplier.ReleasePortCallCount()).To(Equal(1))
+				protocol, port := netRuleApplier.ReleasePortArgsForCall(0)
+				Expect(protocol).To(Equal("tcp"))
+				Expect(port).To(Equal(uint16(40001)))
+			})
+
+			Context("updating the endpoint fails", func() {
+				BeforeEach(func() {
+					endpointManager.UpdatePoliciesReturns(hcsshim.HNSEndpoint{}, errors.New("cannot update endpoint"))
+				})
+
+				It("keeps its host port", func() {
+					_, err := networkManager.Update(inputs)
+					Expect(err).To(MatchError("cannot update endpoint"))
+					Expect(netRuleApplier.ReleasePortCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("releasing its host port fails", func() {
+				BeforeEach(func() {
+					netRuleApplier.ReleasePortReturns(errors.New("decoding state file: potato"))
+				})
+
+				It("returns an error", func() {
+					_, err := networkManager.Update(inputs)
+					Expect(err).To(MatchError("decoding state file: potato"))
+				})
+			})
+		})
+
+		Context("when the config specifies DNS servers", func() {
+			BeforeEach(func() {
+				config.DNSServers = []string{"8.8.8.8"}
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+			})
+
+			It("keeps the DNS netout rules", func() {
+				_, err := networkManager.Update(inputs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(netRuleApplier.OutCallCount()).To(Equal(3))
+			})
+		})
+
+		Context("getting the endpoint fails", func() {
+			BeforeEach(func() {
+				endpointManager.GetReturns(hcsshim.HNSEndpoint{}, errors.New("no endpoint"))
+			})
+
+			It("returns an error", func() {
+				_, err := networkManager.Update(inputs)
+				Expect(err).To(MatchError("no endpoint"))
+				Expect(endpointManager.UpdatePoliciesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("net out fails", func() {
+			BeforeEach(func() {
+				netRuleApplier.OutReturns(nil, errors.New("couldn't allocate"))
+			})
+
+			It("returns an error without cleaning up", func() {
+				_, err := networkManager.Update(inputs)
+				Expect(err).To(MatchError("couldn't allocate"))
+				Expect(endpointManager.UpdatePoliciesCallCount()).To(Equal(0))
+				Expect(netRuleApplier.CleanupCallCount()).To(Equal(0))
+				Expect(endpointManager.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("updating the endpoint fails", func() {
+			BeforeEach(func() {
+				endpointManager.UpdatePoliciesReturns(hcsshim.HNSEndpoint{}, errors.New("cannot update endpoint"))
+			})
+
+			It("returns an error", func() {
+				_, err := networkManager.Update(inputs)
+				Expect(err).To(MatchError("cannot update endpoint"))
+			})
+		})
+	})
+
 	Describe("Down", func() {
 		It("deletes the endpoint and cleans up the ports and firewall rules", func() {
 			Expect(networkManager.Down()).To(Succeed())
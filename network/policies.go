@@ -0,0 +1,154 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/winc/network/firewall"
+	"code.cloudfoundry.org/winc/network/netrules"
+	"github.com/Microsoft/hcsshim"
+)
+
+// EndpointPolicies are the policies of an HNS endpoint, decoded by type
+type EndpointPolicies struct {
+	NATs  []*hcsshim.NatPolicy
+	ACLs  []*hcsshim.ACLPolicy
+	QoS   []*hcsshim.QosPolicy
+	Other []json.RawMessage
+}
+
+func DecodePolicies(policies []json.RawMessage) (EndpointPolicies, error) {
+	decoded := EndpointPolicies{}
+
+	for _, raw := range policies {
+		var policy hcsshim.Policy
+		if err := json.Unmarshal(raw, &policy); err != nil {
+			return EndpointPolicies{}, err
+		}
+
+		var err error
+		switch policy.Type {
+		case hcsshim.Nat:
+			nat := &hcsshim.NatPolicy{}
+			err = json.Unmarshal(raw, nat)
+			decoded.NATs = append(decoded.NATs, nat)
+		case hcsshim.ACL:
+			acl := &hcsshim.ACLPolicy{}
+			err = json.Unmarshal(raw, acl)
+			decoded.ACLs = append(decoded.ACLs, acl)
+		case hcsshim.QOS:
+			qos := &hcsshim.QosPolicy{}
+			err = json.Unmarshal(raw, qos)
+			decoded.QoS = append(decoded.QoS, qos)
+		default:
+			decoded.Other = append(decoded.Other, raw)
+		}
+
+		if err != nil {
+			return EndpointPolicies{}, err
+		}
+	}
+
+	return decoded, nil
+}
+
+// Describe returns a human-readable description of each policy
+func (p EndpointPolicies) Describe() []string {
+	descriptions := []string{}
+
+	for _, nat := range p.NATs {
+		descriptions = append(descriptions, fmt.Sprintf("NAT %s host port %d to container port %d",
+			strings.ToLower(nat.Protocol), nat.ExternalPort, nat.InternalPort))
+	}
+
+	for _, acl := range p.ACLs {
+		descriptions = append(descriptions, fmt.Sprintf("ACL %s %s %s local %s remote %s",
+			strings.ToLower(string(acl.Action)),
+			strings.ToLower(string(acl.Direction)),
+			protocolName(acl.Protocol),
+			addressAndPorts(acl.LocalAddresses, acl.LocalPorts),
+			addressAndPorts(acl.RemoteAddresses, acl.RemotePorts)))
+	}
+
+	for _, qos := range p.QoS {
+		descriptions = append(descriptions, fmt.Sprintf("QOS maximum outgoing bandwidth %d bytes/s", qos.MaximumOutgoingBandwidthInBytes))
+	}
+
+	for _, other := range p.Other {
+		descriptions = append(descriptions, string(other))
+	}
+
+	return descriptions
+}
+
+func protocolName(protocol uint16) string {
+	switch firewall.Protocol(protocol) {
+	case firewall.NET_FW_IP_PROTOCOL_TCP:
+		return "tcp"
+	case firewall.NET_FW_IP_PROTOCOL_UDP:
+		return "udp"
+	case firewall.NET_FW_IP_PROTOCOL_ICMP:
+		return "icmp"
+	case 0, firewall.NET_FW_IP_PROTOCOL_ANY:
+		return "all"
+	default:
+		return fmt.Sprintf("protocol %d", protocol)
+	}
+}
+
+// addressAndPorts describes an ACL endpoint, where empty addresses or ports
+// match any
+func addressAndPorts(addresses, ports string) string {
+	if addresses == "" {
+		addresses = "any"
+	}
+	if ports == "" {
+		return addresses
+	}
+	return fmt.Sprintf("%s ports %s", addresses, ports)
+}
+
+// netInMapping returns the NAT and inbound ACL policies already applied for
+// rule, and whether every protocol of the rule is mapped
+func (p EndpointPolicies) netInMapping(rule netrules.NetIn) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, bool) {
+	protocols, err := rule.Protocols()
+	if err != nil {
+		return nil, nil, false
+	}
+
+	nats := []*hcsshim.NatPolicy{}
+	acls := []*hcsshim.ACLPolicy{}
+	containerPort := strconv.FormatUint(uint64(rule.ContainerPort), 10)
+
+	for _, protocol := range protocols {
+		var nat *hcsshim.NatPolicy
+		for _, n := range p.NATs {
+			if n.InternalPort == rule.ContainerPort &&
+				strings.EqualFold(n.Protocol, protocol) &&
+				(rule.HostPort == 0 || n.ExternalPort == rule.HostPort) {
+				nat = n
+				break
+			}
+		}
+
+		if nat == nil {
+			return nil, nil, false
+		}
+		nats = append(nats, nat)
+
+		for _, acl := range p.ACLs {
+			if acl.Direction == hcsshim.In &&
+				acl.LocalPorts == containerPort &&
+				acl.Protocol == uint16(netrules.FirewallProtocol(protocol)) {
+				// HNS assigns ids to the policies it stores
+				inAcl := *acl
+				inAcl.Id = ""
+				acls = append(acls, &inAcl)
+			}
+		}
+	}
+
+	return nats, acls, true
+}
@@ -8,11 +8,12 @@ import (
 )
 
 type Tracker struct {
-	AcquireOneStub        func(*port_allocator.Pool, string) (uint16, error)
+	AcquireOneStub        func(*port_allocator.Pool, string, []string) (uint16, error)
 	acquireOneMutex       sync.RWMutex
 	acquireOneArgsForCall []struct {
 		arg1 *port_allocator.Pool
 		arg2 string
+		arg3 []string
 	}
 	acquireOneReturns struct {
 		result1 uint16
@@ -33,6 +34,20 @@ type Tracker struct {
 	inRangeReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	ReleaseStub        func(*port_allocator.Pool, string, []string, uint16) error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct {
+		arg1 *port_allocator.Pool
+		arg2 string
+		arg3 []string
+		arg4 uint16
+	}
+	releaseReturns struct {
+		result1 error
+	}
+	releaseReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ReleaseAllStub        func(*port_allocator.Pool, string) error
 	releaseAllMutex       sync.RWMutex
 	releaseAllArgsForCall []struct {
@@ -49,19 +64,25 @@ type Tracker struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *Tracker) AcquireOne(arg1 *port_allocator.Pool, arg2 string) (uint16, error) {
+func (fake *Tracker) AcquireOne(arg1 *port_allocator.Pool, arg2 string, arg3 []string) (uint16, error) {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
 	fake.acquireOneMutex.Lock()
 	ret, specificReturn := fake.acquireOneReturnsOnCall[len(fake.acquireOneArgsForCall)]
 	fake.acquireOneArgsForCall = append(fake.acquireOneArgsForCall, struct {
 		arg1 *port_allocator.Pool
 		arg2 string
-	}{arg1, arg2})
+		arg3 []string
+	}{arg1, arg2, arg3Copy})
 	stub := fake.AcquireOneStub
 	fakeReturns := fake.acquireOneReturns
-	fake.recordInvocation("AcquireOne", []interface{}{arg1, arg2})
+	fake.recordInvocation("AcquireOne", []interface{}{arg1, arg2, arg3Copy})
 	fake.acquireOneMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -75,17 +96,17 @@ func (fake *Tracker) AcquireOneCallCount() int {
 	return len(fake.acquireOneArgsForCall)
 }
 
-func (fake *Tracker) AcquireOneCalls(stub func(*port_allocator.Pool, string) (uint16, error)) {
+func (fake *Tracker) AcquireOneCalls(stub func(*port_allocator.Pool, string, []string) (uint16, error)) {
 	fake.acquireOneMutex.Lock()
 	defer fake.acquireOneMutex.Unlock()
 	fake.AcquireOneStub = stub
 }
 
-func (fake *Tracker) AcquireOneArgsForCall(i int) (*port_allocator.Pool, string) {
+func (fake *Tracker) AcquireOneArgsForCall(i int) (*port_allocator.Pool, string, []string) {
 	fake.acquireOneMutex.RLock()
 	defer fake.acquireOneMutex.RUnlock()
 	argsForCall := fake.acquireOneArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *Tracker) AcquireOneReturns(result1 uint16, result2 error) {
@@ -175,6 +196,75 @@ func (fake *Tracker) InRangeReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *Tracker) Release(arg1 *port_allocator.Pool, arg2 string, arg3 []string, arg4 uint16) error {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.releaseMutex.Lock()
+	ret, specificReturn := fake.releaseReturnsOnCall[len(fake.releaseArgsForCall)]
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
+		arg1 *port_allocator.Pool
+		arg2 string
+		arg3 []string
+		arg4 uint16
+	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.ReleaseStub
+	fakeReturns := fake.releaseReturns
+	fake.recordInvocation("Release", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.releaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Tracker) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *Tracker) ReleaseCalls(stub func(*port_allocator.Pool, string, []string, uint16) error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = stub
+}
+
+func (fake *Tracker) ReleaseArgsForCall(i int) (*port_allocator.Pool, string, []string, uint16) {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	argsForCall := fake.releaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *Tracker) ReleaseReturns(result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Tracker) ReleaseReturnsOnCall(i int, result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	if fake.releaseReturnsOnCall == nil {
+		fake.releaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Tracker) ReleaseAll(arg1 *port_allocator.Pool, arg2 string) error {
 	fake.releaseAllMutex.Lock()
 	ret, specificReturn := fake.releaseAllReturnsOnCall[len(fake.releaseAllArgsForCall)]
@@ -244,6 +334,8 @@ func (fake *Tracker) Invocations() map[string][][]interface{} {
 	defer fake.acquireOneMutex.RUnlock()
 	fake.inRangeMutex.RLock()
 	defer fake.inRangeMutex.RUnlock()
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
 	fake.releaseAllMutex.RLock()
 	defer fake.releaseAllMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
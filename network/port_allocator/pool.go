@@ -3,42 +3,89 @@ package port_allocator
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
 )
 
 var ErrorPortPoolExhausted = errors.New("port pool exhausted")
 
+// Pool records which handle acquired each port. TCP and UDP ports are
+// separate, so the same port can be acquired by different handles for each.
 type Pool struct {
-	AcquiredPorts map[uint16]string
+	AcquiredPorts    map[uint16]string
+	AcquiredUDPPorts map[uint16]string
 }
 
-func (p *Pool) MarshalJSON() ([]byte, error) {
-	var jsonData struct {
-		AcquiredPorts map[string][]uint16 `json:"acquired_ports"`
-	}
-	jsonData.AcquiredPorts = make(map[string][]uint16)
+type poolJSON struct {
+	AcquiredPorts    map[string][]uint16 `json:"acquired_ports"`
+	AcquiredUDPPorts map[string][]uint16 `json:"acquired_udp_ports,omitempty"`
+}
 
-	for port, handle := range p.AcquiredPorts {
-		jsonData.AcquiredPorts[handle] = append(jsonData.AcquiredPorts[handle], port)
-	}
-	return json.Marshal(jsonData)
+func (p *Pool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(poolJSON{
+		AcquiredPorts:    portsByHandle(p.AcquiredPorts),
+		AcquiredUDPPorts: portsByHandle(p.AcquiredUDPPorts),
+	})
 }
 
 func (p *Pool) UnmarshalJSON(bytes []byte) error {
-	var jsonData struct {
-		AcquiredPorts map[string][]uint16 `json:"acquired_ports"`
-	}
+	var jsonData poolJSON
 	err := json.Unmarshal(bytes, &jsonData)
 	if err != nil {
 		return err
 	}
 
-	p.AcquiredPorts = make(map[uint16]string)
-	for handle, ports := range jsonData.AcquiredPorts {
-		for _, port := range ports {
-			p.AcquiredPorts[port] = handle
+	p.AcquiredPorts = handlesByPort(jsonData.AcquiredPorts)
+	p.AcquiredUDPPorts = handlesByPort(jsonData.AcquiredUDPPorts)
+	return nil
+}
+
+func portsByHandle(acquired map[uint16]string) map[string][]uint16 {
+	ports := make(map[string][]uint16)
+	for port, handle := range acquired {
+		ports[handle] = append(ports[handle], port)
+	}
+	return ports
+}
+
+func handlesByPort(ports map[string][]uint16) map[uint16]string {
+	acquired := make(map[uint16]string)
+	for handle, handlePorts := range ports {
+		for _, port := range handlePorts {
+			acquired[port] = handle
 		}
 	}
-	return nil
+	return acquired
+}
+
+// handlePorts returns the ports acquired by handle in ascending order
+func handlePorts(acquired map[uint16]string, handle string) []uint16 {
+	ports := []uint16{}
+	for port, h := range acquired {
+		if h == handle {
+			ports = append(ports, port)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+// acquired returns the ports acquired for a protocol, either "tcp" or "udp"
+func (p *Pool) acquired(protocol string) (map[uint16]string, error) {
+	switch protocol {
+	case "tcp":
+		if p.AcquiredPorts == nil {
+			p.AcquiredPorts = make(map[uint16]string)
+		}
+		return p.AcquiredPorts, nil
+	case "udp":
+		if p.AcquiredUDPPorts == nil {
+			p.AcquiredUDPPorts = make(map[uint16]string)
+		}
+		return p.AcquiredUDPPorts, nil
+	default:
+		return nil, fmt.Errorf("invalid protocol: %s", protocol)
+	}
 }
 
 type Tracker struct {
@@ -50,15 +97,23 @@ func (t *Tracker) InRange(port uint16) bool {
 	return port >= t.StartPort && port < t.StartPort+t.Capacity
 }
 
-func (t *Tracker) AcquireOne(pool *Pool, handler string) (uint16, error) {
-	if pool.AcquiredPorts == nil {
-		pool.AcquiredPorts = make(map[uint16]string)
+// AcquireOne acquires the first port that is free for every one of protocols
+func (t *Tracker) AcquireOne(pool *Pool, handler string, protocols []string) (uint16, error) {
+	pools := []map[uint16]string{}
+	for _, protocol := range protocols {
+		acquired, err := pool.acquired(protocol)
+		if err != nil {
+			return 0, err
+		}
+		pools = append(pools, acquired)
 	}
 
 	for i := uint16(0); i < t.Capacity; i++ {
 		candidatePort := t.StartPort + i
-		if !contains(pool.AcquiredPorts, candidatePort) {
-			pool.AcquiredPorts[candidatePort] = handler
+		if !containsAny(pools, candidatePort) {
+			for _, acquired := range pools {
+				acquired[candidatePort] = handler
+			}
 			return candidatePort, nil
 		}
 	}
@@ -66,15 +121,35 @@ func (t *Tracker) AcquireOne(pool *Pool, handler string) (uint16, error) {
 }
 
 func (t *Tracker) ReleaseAll(pool *Pool, handle string) error {
-	for port, h := range pool.AcquiredPorts {
-		if h == handle {
-			delete(pool.AcquiredPorts, port)
+	for _, acquired := range []map[uint16]string{pool.AcquiredPorts, pool.AcquiredUDPPorts} {
+		for port, h := range acquired {
+			if h == handle {
+				delete(acquired, port)
+			}
 		}
 	}
 	return nil
 }
 
-func contains(list map[uint16]string, candidate uint16) bool {
-	_, ok := list[candidate]
-	return ok
+// Release releases port for each of protocols, if it was acquired by handle
+func (t *Tracker) Release(pool *Pool, handle string, protocols []string, port uint16) error {
+	for _, protocol := range protocols {
+		acquired, err := pool.acquired(protocol)
+		if err != nil {
+			return err
+		}
+		if acquired[port] == handle {
+			delete(acquired, port)
+		}
+	}
+	return nil
+}
+
+func containsAny(pools []map[uint16]string, candidate uint16) bool {
+	for _, acquired := range pools {
+		if _, ok := acquired[candidate]; ok {
+			return true
+		}
+	}
+	return false
 }
@@ -16,6 +16,9 @@ var _ = Describe("Tracker", func() {
 	var (
 		pool    *port_allocator.Pool
 		tracker *port_allocator.Tracker
+		tcp     = []string{"tcp"}
+		udp     = []string{"udp"}
+		both    = []string{"tcp", "udp"}
 	)
 	BeforeEach(func() {
 		pool = &port_allocator.Pool{}
@@ -27,7 +30,7 @@ var _ = Describe("Tracker", func() {
 
 	Describe("AcquireOne", func() {
 		It("reserves and returns a port from the pool", func() {
-			newPort, err := tracker.AcquireOne(pool, "some-handle")
+			newPort, err := tracker.AcquireOne(pool, "some-handle", tcp)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(newPort).To(BeInRange(100, 110))
 			Expect(pool.AcquiredPorts).To(Equal(map[uint16]string{newPort: "some-handle"}))
@@ -35,9 +38,9 @@ var _ = Describe("Tracker", func() {
 
 		Context("when acquiring multiple ports", func() {
 			It("gives unique ports", func() {
-				firstPort, err := tracker.AcquireOne(pool, "some-handle")
+				firstPort, err := tracker.AcquireOne(pool, "some-handle", tcp)
 				Expect(err).NotTo(HaveOccurred())
-				secondPort, err := tracker.AcquireOne(pool, "some-handle")
+				secondPort, err := tracker.AcquireOne(pool, "some-handle", tcp)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(pool.AcquiredPorts).To(HaveLen(2))
@@ -57,13 +60,49 @@ var _ = Describe("Tracker", func() {
 			})
 
 			It("reserves and returns that unacquired port", func() {
-				port, err := tracker.AcquireOne(pool, "some-handle")
+				port, err := tracker.AcquireOne(pool, "some-handle", tcp)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(port).To(Equal(uint16(101)))
 				Expect(pool.AcquiredPorts).To(HaveKey(uint16(101)))
 			})
 		})
 
+		Context("when acquiring a UDP port", func() {
+			BeforeEach(func() {
+				pool.AcquiredPorts = map[uint16]string{100: "tcp-handle"}
+			})
+
+			It("tracks it separately from TCP ports", func() {
+				port, err := tracker.AcquireOne(pool, "some-handle", udp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(port).To(Equal(uint16(100)))
+				Expect(pool.AcquiredUDPPorts).To(Equal(map[uint16]string{100: "some-handle"}))
+				Expect(pool.AcquiredPorts).To(Equal(map[uint16]string{100: "tcp-handle"}))
+			})
+		})
+
+		Context("when acquiring a port for both TCP and UDP", func() {
+			BeforeEach(func() {
+				pool.AcquiredPorts = map[uint16]string{100: "some-handle"}
+				pool.AcquiredUDPPorts = map[uint16]string{101: "some-handle"}
+			})
+
+			It("reserves the first port that is free for both", func() {
+				port, err := tracker.AcquireOne(pool, "some-handle", both)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(port).To(Equal(uint16(102)))
+				Expect(pool.AcquiredPorts).To(HaveKeyWithValue(uint16(102), "some-handle"))
+				Expect(pool.AcquiredUDPPorts).To(HaveKeyWithValue(uint16(102), "some-handle"))
+			})
+		})
+
+		Context("when the protocol is invalid", func() {
+			It("returns an error", func() {
+				_, err := tracker.AcquireOne(pool, "some-handle", []string{"sctp"})
+				Expect(err).To(MatchError("invalid protocol: sctp"))
+			})
+		})
+
 		Context("when the pool has reached capacity", func() {
 			BeforeEach(func() {
 				tracker.Capacity = 2
@@ -74,7 +113,7 @@ var _ = Describe("Tracker", func() {
 			})
 
 			It("returns a useful error", func() {
-				_, err := tracker.AcquireOne(pool, "some-handle")
+				_, err := tracker.AcquireOne(pool, "some-handle", tcp)
 				Expect(err).To(Equal(port_allocator.ErrorPortPoolExhausted))
 			})
 		})
@@ -88,7 +127,7 @@ var _ = Describe("Tracker", func() {
 				exp.Sample(func(idx int) {
 					exp.MeasureDuration("runtime", func() {
 						i++
-						_, err := tracker.AcquireOne(pool, "some-handle")
+						_, err := tracker.AcquireOne(pool, "some-handle", tcp)
 						Expect(err).NotTo(HaveOccurred())
 					})
 
@@ -105,17 +144,49 @@ var _ = Describe("Tracker", func() {
 			var err error
 			for i := uint16(0); i < tracker.Capacity; i++ {
 				if i%2 == 0 {
-					_, err = tracker.AcquireOne(pool, "some-handle")
+					_, err = tracker.AcquireOne(pool, "some-handle", tcp)
 				} else {
-					_, err = tracker.AcquireOne(pool, "some-handle2")
+					_, err = tracker.AcquireOne(pool, "some-handle2", tcp)
 				}
 			}
 			Expect(err).NotTo(HaveOccurred())
 			Expect(tracker.ReleaseAll(pool, "some-handle")).To(Succeed())
-			reacquired, err := tracker.AcquireOne(pool, "some-handle")
+			reacquired, err := tracker.AcquireOne(pool, "some-handle", tcp)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(reacquired).To(Equal(uint16(100)))
 		})
+
+		It("releases UDP ports too", func() {
+			_, err := tracker.AcquireOne(pool, "some-handle", both)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tracker.ReleaseAll(pool, "some-handle")).To(Succeed())
+			Expect(pool.AcquiredPorts).To(BeEmpty())
+			Expect(pool.AcquiredUDPPorts).To(BeEmpty())
+		})
+
+		It("can release a single port for one protocol", func() {
+			port, err := tracker.AcquireOne(pool, "some-handle", both)
+			Expect(err).NotTo(HaveOccurred())
+			other, err := tracker.AcquireOne(pool, "some-handle", tcp)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tracker.Release(pool, "some-handle", udp, port)).To(Succeed())
+			Expect(pool.AcquiredPorts).To(Equal(map[uint16]string{port: "some-handle", other: "some-handle"}))
+			Expect(pool.AcquiredUDPPorts).To(BeEmpty())
+
+			reacquired, err := tracker.AcquireOne(pool, "some-handle2", udp)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reacquired).To(Equal(port))
+		})
+
+		It("does not release a port acquired by another handle", func() {
+			port, err := tracker.AcquireOne(pool, "some-handle2", tcp)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tracker.Release(pool, "some-handle", tcp, port)).To(Succeed())
+			Expect(pool.AcquiredPorts).To(Equal(map[uint16]string{port: "some-handle2"}))
+		})
 	})
 
 	Describe("InRange", func() {
@@ -159,6 +230,23 @@ var _ = Describe("Tracker", func() {
 				"some-handle2": [ 105 ]
 			} }`))
 		})
+
+		It("marshals UDP ports separately", func() {
+			pool.AcquiredPorts = map[uint16]string{42: "some-handle"}
+			pool.AcquiredUDPPorts = map[uint16]string{42: "some-handle2"}
+
+			bytes, err := json.Marshal(pool)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(bytes).To(MatchJSON(`{
+				"acquired_ports": { "some-handle": [ 42 ] },
+				"acquired_udp_ports": { "some-handle2": [ 42 ] }
+			}`))
+
+			var newPool port_allocator.Pool
+			Expect(json.Unmarshal(bytes, &newPool)).To(Succeed())
+			Expect(newPool.AcquiredUDPPorts).To(Equal(pool.AcquiredUDPPorts))
+		})
 	})
 })
 
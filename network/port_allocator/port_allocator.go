@@ -10,8 +10,9 @@ import (
 
 //go:generate counterfeiter -o fakes/tracker.go --fake-name Tracker . tracker
 type tracker interface {
-	AcquireOne(pool *Pool, handle string) (uint16, error)
+	AcquireOne(pool *Pool, handle string, protocols []string) (uint16, error)
 	ReleaseAll(pool *Pool, handle string) error
+	Release(pool *Pool, handle string, protocols []string, port uint16) error
 	InRange(port uint16) bool
 }
 
@@ -21,7 +22,9 @@ type PortAllocator struct {
 	Locker     filelock.FileLocker
 }
 
-func (p *PortAllocator) AllocatePort(handle string, port uint16) (uint16, error) {
+// AllocatePort acquires a port from the pool that is free for each of
+// protocols, unless port is given, in which case it must be outside the pool
+func (p *PortAllocator) AllocatePort(handle string, protocols []string, port uint16) (uint16, error) {
 	if port != 0 {
 		if p.Tracker.InRange(port) {
 			return 0, errors.New("cannot specify port from allocation range")
@@ -42,7 +45,7 @@ func (p *PortAllocator) AllocatePort(handle string, port uint16) (uint16, error)
 		return 0, fmt.Errorf("decoding state file: %s", err)
 	}
 
-	newPort, err := p.Tracker.AcquireOne(pool, handle)
+	newPort, err := p.Tracker.AcquireOne(pool, handle, protocols)
 	if err != nil {
 		return 0, fmt.Errorf("acquire port: %s", err)
 	}
@@ -55,6 +58,53 @@ func (p *PortAllocator) AllocatePort(handle string, port uint16) (uint16, error)
 	return newPort, nil
 }
 
+// AllocatedPorts returns the ports acquired by handle, keyed by protocol
+func (p *PortAllocator) AllocatedPorts(handle string) (map[string][]uint16, error) {
+	file, err := p.Locker.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open lock: %s", err)
+	}
+	defer file.Close() // defer not tested
+
+	pool := &Pool{}
+	err = p.Serializer.DecodeAll(file, pool)
+	if err != nil {
+		return nil, fmt.Errorf("decoding state file: %s", err)
+	}
+
+	return map[string][]uint16{
+		"tcp": handlePorts(pool.AcquiredPorts, handle),
+		"udp": handlePorts(pool.AcquiredUDPPorts, handle),
+	}, nil
+}
+
+// ReleasePort returns a port acquired by handle to the pool for each of
+// protocols, so that it can be acquired again once it is no longer mapped
+func (p *PortAllocator) ReleasePort(handle string, protocols []string, port uint16) error {
+	file, err := p.Locker.Open()
+	if err != nil {
+		return fmt.Errorf("open lock: %s", err)
+	}
+	defer file.Close() // defer not tested
+
+	pool := &Pool{}
+	err = p.Serializer.DecodeAll(file, pool)
+	if err != nil {
+		return fmt.Errorf("decoding state file: %s", err)
+	}
+
+	if err := p.Tracker.Release(pool, handle, protocols, port); err != nil {
+		return fmt.Errorf("release port: %s", err)
+	}
+
+	err = p.Serializer.EncodeAndOverwrite(file, pool)
+	if err != nil {
+		return fmt.Errorf("encode and overwrite: %s", err)
+	}
+
+	return nil
+}
+
 func (p *PortAllocator) ReleaseAllPorts(handle string) error {
 	file, err := p.Locker.Open()
 	if err != nil {
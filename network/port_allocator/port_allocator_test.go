@@ -2,6 +2,7 @@ package port_allocator_test
 
 import (
 	"errors"
+	"io"
 	"os"
 
 	filelockfakes "code.cloudfoundry.org/filelock/fakes"
@@ -41,7 +42,7 @@ var _ = Describe("PortAllocator", func() {
 
 	Describe("AllocatePort", func() {
 		It("deserializes the pool from the locked file", func() {
-			_, err := portAllocator.AllocatePort("some-handle", 0)
+			_, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 0)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(serializer.DecodeAllCallCount()).To(Equal(1))
@@ -52,16 +53,17 @@ var _ = Describe("PortAllocator", func() {
 
 		Context("when the passed in port is 0", func() {
 			It("acquires the port from the pool", func() {
-				_, err := portAllocator.AllocatePort("some-handle", 0)
+				_, err := portAllocator.AllocatePort("some-handle", []string{"tcp", "udp"}, 0)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(serializer.DecodeAllCallCount()).To(Equal(1))
 				Expect(tracker.AcquireOneCallCount()).To(Equal(1))
 
 				_, pool := serializer.DecodeAllArgsForCall(0)
-				receivedPool, receivedHandle := tracker.AcquireOneArgsForCall(0)
+				receivedPool, receivedHandle, receivedProtocols := tracker.AcquireOneArgsForCall(0)
 				Expect(receivedPool).To(Equal(pool))
 				Expect(receivedHandle).To(Equal("some-handle"))
+				Expect(receivedProtocols).To(Equal([]string{"tcp", "udp"}))
 			})
 		})
 
@@ -70,7 +72,7 @@ var _ = Describe("PortAllocator", func() {
 				tracker.InRangeReturns(false)
 			})
 			It("noops and returns the port", func() {
-				port, err := portAllocator.AllocatePort("some-handle", 42)
+				port, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 42)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(tracker.AcquireOneCallCount()).To(Equal(0))
@@ -83,13 +85,13 @@ var _ = Describe("PortAllocator", func() {
 				tracker.InRangeReturns(true)
 			})
 			It("returns an error", func() {
-				_, err := portAllocator.AllocatePort("some-handle", 42)
+				_, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 42)
 				Expect(err).To(MatchError(errors.New("cannot specify port from allocation range")))
 			})
 		})
 
 		It("re-serializes the pool to the locked file", func() {
-			_, err := portAllocator.AllocatePort("some-handle", 0)
+			_, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 0)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(1))
@@ -102,7 +104,7 @@ var _ = Describe("PortAllocator", func() {
 		})
 
 		It("returns the port", func() {
-			port, err := portAllocator.AllocatePort("some-handle", 0)
+			port, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 0)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(port).To(Equal(uint16(111)))
 		})
@@ -112,7 +114,7 @@ var _ = Describe("PortAllocator", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			locker.OpenReturns(file, nil)
-			_, err = portAllocator.AllocatePort("some-handle", 0)
+			_, err = portAllocator.AllocatePort("some-handle", []string{"tcp"}, 0)
 			Expect(err).NotTo(HaveOccurred())
 
 			By("checking that the write to the closed file should fail")
@@ -125,7 +127,7 @@ var _ = Describe("PortAllocator", func() {
 				locker.OpenReturns(nil, errors.New("potato"))
 			})
 			It("wraps and returns the error", func() {
-				_, err := portAllocator.AllocatePort("some-handle", 0)
+				_, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 0)
 				Expect(err).To(MatchError("open lock: potato"))
 			})
 		})
@@ -135,7 +137,7 @@ var _ = Describe("PortAllocator", func() {
 				serializer.DecodeAllReturns(errors.New("potato"))
 			})
 			It("wraps and returns the error", func() {
-				_, err := portAllocator.AllocatePort("some-handle", 0)
+				_, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 0)
 				Expect(err).To(MatchError("decoding state file: potato"))
 			})
 		})
@@ -145,7 +147,7 @@ var _ = Describe("PortAllocator", func() {
 				tracker.AcquireOneReturns(0, errors.New("turnip"))
 			})
 			It("wraps and returns the error", func() {
-				_, err := portAllocator.AllocatePort("some-handle", 0)
+				_, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 0)
 				Expect(err).To(MatchError("acquire port: turnip"))
 			})
 		})
@@ -155,12 +157,108 @@ var _ = Describe("PortAllocator", func() {
 				serializer.EncodeAndOverwriteReturns(errors.New("turnip"))
 			})
 			It("wraps and returns the error", func() {
-				_, err := portAllocator.AllocatePort("some-handle", 0)
+				_, err := portAllocator.AllocatePort("some-handle", []string{"tcp"}, 0)
 				Expect(err).To(MatchError("encode and overwrite: turnip"))
 			})
 		})
 	})
 
+	Describe("AllocatedPorts", func() {
+		BeforeEach(func() {
+			serializer.DecodeAllStub = func(_ io.ReadSeeker, outData interface{}) error {
+				pool := outData.(*port_allocator.Pool)
+				pool.AcquiredPorts = map[uint16]string{40002: "some-handle", 40000: "some-handle", 40001: "other-handle"}
+				pool.AcquiredUDPPorts = map[uint16]string{40000: "some-handle"}
+				return nil
+			}
+		})
+
+		It("returns the ports acquired by the handle for each protocol", func() {
+			ports, err := portAllocator.AllocatedPorts("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ports).To(Equal(map[string][]uint16{
+				"tcp": {40000, 40002},
+				"udp": {40000},
+			}))
+
+			file, _ := serializer.DecodeAllArgsForCall(0)
+			Expect(file).To(Equal(lockedFile))
+		})
+
+		It("does not modify the pool", func() {
+			_, err := portAllocator.AllocatedPorts("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(0))
+		})
+
+		Context("the handle has no ports", func() {
+			It("returns empty lists", func() {
+				ports, err := portAllocator.AllocatedPorts("unknown-handle")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ports).To(Equal(map[string][]uint16{"tcp": {}, "udp": {}}))
+			})
+		})
+
+		Context("when the locker fails to open the file", func() {
+			BeforeEach(func() {
+				locker.OpenReturns(nil, errors.New("potato"))
+			})
+			It("wraps and returns the error", func() {
+				_, err := portAllocator.AllocatedPorts("some-handle")
+				Expect(err).To(MatchError("open lock: potato"))
+			})
+		})
+
+		Context("when the serializer fails to decode", func() {
+			BeforeEach(func() {
+				serializer.DecodeAllReturns(errors.New("potato"))
+			})
+			It("wraps and returns the error", func() {
+				_, err := portAllocator.AllocatedPorts("some-handle")
+				Expect(err).To(MatchError("decoding state file: potato"))
+			})
+		})
+	})
+
+	Describe("ReleasePort", func() {
+		It("releases the port for each protocol and re-serializes the pool to the locked file", func() {
+			err := portAllocator.ReleasePort("some-handle", []string{"tcp", "udp"}, 1234)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tracker.ReleaseCallCount()).To(Equal(1))
+			_, handle, protocols, port := tracker.ReleaseArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(protocols).To(Equal([]string{"tcp", "udp"}))
+			Expect(port).To(Equal(uint16(1234)))
+
+			_, poolForDecode := serializer.DecodeAllArgsForCall(0)
+			file, poolForEncode := serializer.EncodeAndOverwriteArgsForCall(0)
+			Expect(file).To(Equal(lockedFile))
+			Expect(poolForEncode).To(Equal(poolForDecode))
+		})
+
+		Context("when the serializer fails to decode", func() {
+			BeforeEach(func() {
+				serializer.DecodeAllReturns(errors.New("potato"))
+			})
+			It("wraps and returns the error", func() {
+				err := portAllocator.ReleasePort("some-handle", []string{"tcp"}, 1234)
+				Expect(err).To(MatchError("decoding state file: potato"))
+			})
+		})
+
+		Context("when the tracker fails to release the port", func() {
+			BeforeEach(func() {
+				tracker.ReleaseReturns(errors.New("turnip"))
+			})
+			It("wraps and returns the error", func() {
+				err := portAllocator.ReleasePort("some-handle", []string{"tcp"}, 1234)
+				Expect(err).To(MatchError("release port: turnip"))
+				Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Describe("ReleaseAllPorts", func() {
 		It("deserializes the pool from the locked file", func() {
 			err := portAllocator.ReleaseAllPorts("some-handle")
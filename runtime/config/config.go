@@ -3,8 +3,10 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"unicode/utf8"
 
@@ -76,6 +78,14 @@ func checkAll(spec specs.Spec, v validate.Validator) []string {
 		}
 	}
 	msgs = append(msgs, checkSemVer(spec.Version)...)
+	msgs = append(msgs, checkHooks(spec.Hooks)...)
+	if spec.Process != nil {
+		msgs = append(msgs, checkProcessFields(*spec.Process)...)
+	}
+	if spec.Windows != nil {
+		msgs = append(msgs, checkWindowsResources(spec.Windows.Resources)...)
+		msgs = append(msgs, checkHyperV(spec.Windows)...)
+	}
 	if spec.Root == nil {
 		msgs = append(msgs, "'root' MUST be set when platform is `windows`")
 	} else {
@@ -113,8 +123,11 @@ func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *spec
 			spec.Cwd = overrides.Cwd
 		}
 
-		if len(overrides.Args) > 0 {
+		// a command line is run in preference to args, so one from the
+		// process config mustn't outlive the args it's meant to go with
+		if len(overrides.Args) > 0 || overrides.CommandLine != "" {
 			spec.Args = overrides.Args
+			spec.CommandLine = overrides.CommandLine
 		}
 
 		if len(overrides.Env) > 0 {
@@ -124,6 +137,10 @@ func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *spec
 		if overrides.User.Username != "" {
 			spec.User.Username = overrides.User.Username
 		}
+
+		if overrides.Terminal {
+			spec.Terminal = true
+		}
 	}
 
 	spec.Cwd = toWindowsPath(spec.Cwd)
@@ -132,10 +149,12 @@ func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *spec
 		msgs = append(msgs, fmt.Sprintf("cwd %q is not an absolute path", spec.Cwd))
 	}
 
-	if len(spec.Args) == 0 {
+	if len(spec.Args) == 0 && spec.CommandLine == "" {
 		msgs = append(msgs, "args must not be empty")
 	}
 
+	msgs = append(msgs, checkProcessFields(spec)...)
+
 	for _, env := range spec.Env {
 		if !envValid(env) {
 			msgs = append(msgs, fmt.Sprintf("env %q should be in the form of 'key=value'.", env))
@@ -152,6 +171,214 @@ func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *spec
 	return &spec, nil
 }
 
+// ValidateResources checks that a live resource update is consistent with the
+// limits the container was created with. Limits that cannot be updated are
+// accepted as long as they are unchanged, so the update can be checked again
+// once it has been merged with the container's current limits.
+func ValidateResources(logger *logrus.Entry, spec *specs.Spec, update *specs.WindowsResources) error {
+	logger.Debug("validating resources")
+
+	msgs := []string{}
+
+	created := &specs.WindowsResources{}
+	if spec.Windows != nil && spec.Windows.Resources != nil {
+		created = spec.Windows.Resources
+	}
+
+	if update == nil || (update.Memory == nil && update.CPU == nil) {
+		msgs = append(msgs, "no resources to update")
+	}
+
+	if update != nil && update.Memory != nil && update.Memory.Limit != nil {
+		if *update.Memory.Limit < 1024*1024 {
+			msgs = append(msgs, fmt.Sprintf("memory limit %d must be at least 1MB", *update.Memory.Limit))
+		}
+	}
+
+	if update != nil && update.CPU != nil {
+		cpu := update.CPU
+		msgs = append(msgs, checkCPUWeightAndMaximum(cpu)...)
+
+		var createdCount *uint64
+		if created.CPU != nil {
+			createdCount = created.CPU.Count
+		}
+
+		if cpu.Count != nil && !reflect.DeepEqual(cpu.Count, createdCount) {
+			msgs = append(msgs, "cpu count cannot be updated on a running container")
+		}
+
+		if cpu.Maximum != nil && createdCount != nil {
+			msgs = append(msgs, "cpu maximum cannot be set on a container created with a cpu count")
+		}
+	}
+
+	if update != nil && update.Storage != nil && !reflect.DeepEqual(update.Storage, created.Storage) {
+		msgs = append(msgs, "storage limits cannot be updated on a running container")
+	}
+
+	if len(msgs) > 0 {
+		for _, m := range msgs {
+			logger.WithField("resourcesError", m).Error("error in resources")
+		}
+		return &ResourcesValidationError{ErrorMessages: msgs}
+	}
+
+	return nil
+}
+
+// checkHooks validates the hooks that winc runs on the host. createContainer
+// and startContainer hooks must run inside the container, which HCS provides
+// no way to do, so they are rejected rather than silently skipped.
+func checkHooks(hooks *specs.Hooks) []string {
+	msgs := []string{}
+	if hooks == nil {
+		return msgs
+	}
+
+	if len(hooks.CreateContainer) > 0 {
+		msgs = append(msgs, "createContainer hooks are not supported")
+	}
+	if len(hooks.StartContainer) > 0 {
+		msgs = append(msgs, "startContainer hooks are not supported")
+	}
+
+	all := [][]specs.Hook{hooks.Prestart, hooks.CreateRuntime, hooks.Poststart, hooks.Poststop}
+	for _, list := range all {
+		for _, hook := range list {
+			if !filepath.IsAbs(hook.Path) {
+				msgs = append(msgs, fmt.Sprintf("hook path %q is not an absolute path", hook.Path))
+			}
+			if hook.Timeout != nil && *hook.Timeout <= 0 {
+				msgs = append(msgs, fmt.Sprintf("hook %q timeout must be greater than zero", hook.Path))
+			}
+		}
+	}
+
+	return msgs
+}
+
+func checkHyperV(windows *specs.Windows) []string {
+	msgs := []string{}
+	if windows.HyperV == nil {
+		return msgs
+	}
+
+	if windows.HyperV.UtilityVMPath == "" && len(windows.LayerFolders) == 0 {
+		msgs = append(msgs, "hyperv containers need a utilityVMPath or a base layer with a UtilityVM")
+	}
+
+	if windows.HyperV.UtilityVMPath != "" && !filepath.IsAbs(windows.HyperV.UtilityVMPath) {
+		msgs = append(msgs, fmt.Sprintf("utilityVMPath %q is not an absolute path", windows.HyperV.UtilityVMPath))
+	}
+
+	return msgs
+}
+
+// checkWindowsResources rejects limits HCS cannot apply and combinations of
+// limits that conflict with each other
+func checkWindowsResources(resources *specs.WindowsResources) []string {
+	msgs := []string{}
+	if resources == nil {
+		return msgs
+	}
+
+	if resources.Memory != nil && resources.Memory.Limit != nil && *resources.Memory.Limit < 1024*1024 {
+		msgs = append(msgs, fmt.Sprintf("memory limit %d must be at least 1MB", *resources.Memory.Limit))
+	}
+
+	if cpu := resources.CPU; cpu != nil {
+		if cpu.Count != nil && (*cpu.Count < 1 || *cpu.Count > math.MaxUint32) {
+			msgs = append(msgs, fmt.Sprintf("cpu count %d is out of range", *cpu.Count))
+		}
+
+		msgs = append(msgs, checkCPUWeightAndMaximum(cpu)...)
+
+		// both limit the portion of processor cycles the container can use
+		if cpu.Count != nil && cpu.Maximum != nil {
+			msgs = append(msgs, "cpu count and cpu maximum cannot both be set")
+		}
+
+		if len(cpu.Affinity) > 0 {
+			msgs = append(msgs, "cpu affinity is not supported")
+		}
+	}
+
+	if storage := resources.Storage; storage != nil {
+		if storage.Iops != nil && *storage.Iops == 0 {
+			msgs = append(msgs, "storage iops must be greater than zero")
+		}
+
+		if storage.Bps != nil && *storage.Bps == 0 {
+			msgs = append(msgs, "storage bps must be greater than zero")
+		}
+	}
+
+	return msgs
+}
+
+// checkCPUWeightAndMaximum applies the same limits to cpu shares and cpu
+// maximum whether they are set on create or on update
+func checkCPUWeightAndMaximum(cpu *specs.WindowsCPUResources) []string {
+	msgs := []string{}
+
+	if cpu.Shares != nil && (*cpu.Shares < 1 || *cpu.Shares > 10000) {
+		msgs = append(msgs, fmt.Sprintf("cpu shares %d must be between 1 and 10000", *cpu.Shares))
+	}
+
+	if cpu.Maximum != nil && (*cpu.Maximum < 1 || *cpu.Maximum > 10000) {
+		msgs = append(msgs, fmt.Sprintf("cpu maximum %d must be between 1 and 10000", *cpu.Maximum))
+	}
+
+	if cpu.Shares != nil && cpu.Maximum != nil {
+		msgs = append(msgs, "cpu shares and cpu maximum cannot both be set")
+	}
+
+	return msgs
+}
+
+// checkProcessFields rejects process settings that HCS has no equivalent
+// for, which would otherwise be silently dropped when the process is created
+func checkProcessFields(process specs.Process) []string {
+	msgs := []string{}
+
+	if process.ConsoleSize != nil && !process.Terminal {
+		msgs = append(msgs, "consoleSize requires terminal to be set")
+	}
+
+	if process.User.UID != 0 || process.User.GID != 0 {
+		msgs = append(msgs, "user.uid and user.gid are not supported, use user.username")
+	}
+	if process.User.Umask != nil {
+		msgs = append(msgs, "user.umask is not supported")
+	}
+	if len(process.User.AdditionalGids) > 0 {
+		msgs = append(msgs, "user.additionalGids is not supported")
+	}
+
+	unsupported := []struct {
+		name string
+		set  bool
+	}{
+		{"capabilities", process.Capabilities != nil},
+		{"rlimits", len(process.Rlimits) > 0},
+		{"noNewPrivileges", process.NoNewPrivileges},
+		{"apparmorProfile", process.ApparmorProfile != ""},
+		{"oomScoreAdj", process.OOMScoreAdj != nil},
+		{"scheduler", process.Scheduler != nil},
+		{"selinuxLabel", process.SelinuxLabel != ""},
+		{"ioPriority", process.IOPriority != nil},
+		{"execCPUAffinity", process.ExecCPUAffinity != nil},
+	}
+	for _, field := range unsupported {
+		if field.set {
+			msgs = append(msgs, fmt.Sprintf("%s is not supported on Windows", field.name))
+		}
+	}
+
+	return msgs
+}
+
 func envValid(env string) bool {
 	items := strings.Split(env, "=")
 	return len(items) >= 2
@@ -67,6 +67,20 @@ var _ = Describe("Config", func() {
 				Expect(spec).To(Equal(&expectedSpec))
 			})
 
+			Context("when the process has a command line instead of args", func() {
+				BeforeEach(func() {
+					expectedSpec.Process.Args = nil
+					expectedSpec.Process.CommandLine = `powershell.exe "Write-Host 'hi'"`
+				})
+
+				It("keeps the command line as is", func() {
+					spec, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec.Process.CommandLine).To(Equal(`powershell.exe "Write-Host 'hi'"`))
+					Expect(spec.Process.Args).To(BeEmpty())
+				})
+			})
+
 			Context("when the config.json spec version is not exact but the major version matches the expected version", func() {
 				BeforeEach(func() {
 					expectedSpec.Version = fmt.Sprintf("%d.%d.%d%s", specs.VersionMajor, specs.VersionMinor+1, specs.VersionPatch, specs.VersionDev)
@@ -252,6 +266,128 @@ var _ = Describe("Config", func() {
 				})
 			})
 
+			Context("when the hooks are invalid", func() {
+				BeforeEach(func() {
+					timeout := 0
+					invalidSpec = specs.Spec{
+						Version: specs.Version,
+						Process: &specs.Process{
+							Args: []string{"cmd"},
+							Cwd:  "C:\\",
+						},
+						Windows: &specs.Windows{LayerFolders: []string{"hi"}},
+						Root:    &specs.Root{Path: "some-volume-guid"},
+						Hooks: &specs.Hooks{
+							CreateRuntime:   []specs.Hook{{Path: "relative\\hook.exe"}},
+							Poststop:        []specs.Hook{{Path: "C:\\hook.exe", Timeout: &timeout}},
+							CreateContainer: []specs.Hook{{Path: "C:\\hook.exe"}},
+							StartContainer:  []specs.Hook{{Path: "C:\\hook.exe"}},
+						},
+					}
+					config, err := json.Marshal(&invalidSpec)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(os.WriteFile(filepath.Join(bundlePath, "config.json"), config, 0666)).To(Succeed())
+				})
+
+				It("returns an error describing what is invalid", func() {
+					_, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+					Expect(err.Error()).To(ContainSubstring(`hook path "relative\\hook.exe" is not an absolute path`))
+					Expect(err.Error()).To(ContainSubstring(`hook "C:\\hook.exe" timeout must be greater than zero`))
+					Expect(err.Error()).To(ContainSubstring("createContainer hooks are not supported"))
+					Expect(err.Error()).To(ContainSubstring("startContainer hooks are not supported"))
+				})
+			})
+
+			Context("when the process has settings that are not supported on Windows", func() {
+				BeforeEach(func() {
+					invalidSpec = specs.Spec{
+						Version: specs.Version,
+						Process: &specs.Process{
+							Args:            []string{"cmd"},
+							Cwd:             "C:\\",
+							Rlimits:         []specs.POSIXRlimit{{Type: "RLIMIT_NOFILE", Hard: 1024, Soft: 1024}},
+							NoNewPrivileges: true,
+						},
+						Windows: &specs.Windows{LayerFolders: []string{"hi"}},
+						Root:    &specs.Root{Path: "some-volume-guid"},
+					}
+					config, err := json.Marshal(&invalidSpec)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(os.WriteFile(filepath.Join(bundlePath, "config.json"), config, 0666)).To(Succeed())
+				})
+
+				It("returns an error describing what is invalid", func() {
+					_, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+					Expect(err.Error()).To(ContainSubstring("rlimits is not supported on Windows"))
+					Expect(err.Error()).To(ContainSubstring("noNewPrivileges is not supported on Windows"))
+				})
+			})
+
+			Context("when the resources conflict or are not supported", func() {
+				BeforeEach(func() {
+					count := uint64(2)
+					maximum := uint16(5000)
+					iops := uint64(0)
+					invalidSpec = specs.Spec{
+						Version: specs.Version,
+						Process: &specs.Process{
+							Args: []string{"cmd"},
+							Cwd:  "C:\\",
+						},
+						Windows: &specs.Windows{
+							LayerFolders: []string{"hi"},
+							Resources: &specs.WindowsResources{
+								CPU: &specs.WindowsCPUResources{
+									Count:    &count,
+									Maximum:  &maximum,
+									Affinity: []specs.WindowsCPUGroupAffinity{{Mask: 1}},
+								},
+								Storage: &specs.WindowsStorageResources{Iops: &iops},
+							},
+						},
+						Root: &specs.Root{Path: "some-volume-guid"},
+					}
+					config, err := json.Marshal(&invalidSpec)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(os.WriteFile(filepath.Join(bundlePath, "config.json"), config, 0666)).To(Succeed())
+				})
+
+				It("returns an error describing what is invalid", func() {
+					_, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+					Expect(err.Error()).To(ContainSubstring("cpu count and cpu maximum cannot both be set"))
+					Expect(err.Error()).To(ContainSubstring("cpu affinity is not supported"))
+					Expect(err.Error()).To(ContainSubstring("storage iops must be greater than zero"))
+				})
+			})
+
+			Context("when the hyper-v utility VM path is not absolute", func() {
+				BeforeEach(func() {
+					invalidSpec = specs.Spec{
+						Version: specs.Version,
+						Process: &specs.Process{
+							Args: []string{"cmd"},
+							Cwd:  "C:\\",
+						},
+						Windows: &specs.Windows{
+							HyperV: &specs.WindowsHyperV{UtilityVMPath: "relative\\uvm"},
+						},
+						Root: &specs.Root{Path: "some-volume-guid"},
+					}
+					config, err := json.Marshal(&invalidSpec)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(os.WriteFile(filepath.Join(bundlePath, "config.json"), config, 0666)).To(Succeed())
+				})
+
+				It("returns an error describing what is invalid", func() {
+					_, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+					Expect(err.Error()).To(ContainSubstring(`utilityVMPath "relative\\uvm" is not an absolute path`))
+				})
+			})
+
 			Context("when the config.json spec version has a different major version than the expected version", func() {
 				BeforeEach(func() {
 					invalidSpec.Version = fmt.Sprintf("%d.%d.%d%s", specs.VersionMajor+1, specs.VersionMinor, specs.VersionPatch, specs.VersionDev)
@@ -369,6 +505,69 @@ var _ = Describe("Config", func() {
 				})
 			})
 
+			Context("when the process config file has a command line", func() {
+				BeforeEach(func() {
+					expectedSpec.Args = nil
+					expectedSpec.CommandLine = `cmd.exe /C "echo hi"`
+					config, err := json.Marshal(&expectedSpec)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(os.WriteFile(processConfig, config, 0666)).To(Succeed())
+				})
+
+				It("keeps it", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec.CommandLine).To(Equal(`cmd.exe /C "echo hi"`))
+					Expect(spec.Args).To(BeEmpty())
+				})
+
+				Context("when the overrides specify args", func() {
+					BeforeEach(func() {
+						processConfigOverrides.Args = []string{"foo.exe", "arg"}
+					})
+
+					It("replaces the command line with them", func() {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(spec.Args).To(Equal([]string{"foo.exe", "arg"}))
+						Expect(spec.CommandLine).To(BeEmpty())
+					})
+				})
+
+				Context("when the overrides specify a command line", func() {
+					BeforeEach(func() {
+						processConfigOverrides.CommandLine = `powershell.exe "Write-Host 'hi'"`
+					})
+
+					It("replaces the command line", func() {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(spec.CommandLine).To(Equal(`powershell.exe "Write-Host 'hi'"`))
+						Expect(spec.Args).To(BeEmpty())
+					})
+				})
+			})
+
+			Context("when the overrides specify a command line", func() {
+				BeforeEach(func() {
+					processConfigOverrides.CommandLine = `powershell.exe "Write-Host 'hi'"`
+				})
+
+				It("replaces the process config file's args", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec.CommandLine).To(Equal(`powershell.exe "Write-Host 'hi'"`))
+					Expect(spec.Args).To(BeEmpty())
+				})
+			})
+
+			Context("when a terminal is requested", func() {
+				BeforeEach(func() {
+					processConfigOverrides.Terminal = true
+				})
+
+				It("the process gets a terminal", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec.Terminal).To(BeTrue())
+				})
+			})
+
 			Context("when the process config cwd is a unix style path", func() {
 				BeforeEach(func() {
 					processConfigOverrides.Cwd = "/"
@@ -443,6 +642,80 @@ var _ = Describe("Config", func() {
 			})
 		})
 
+		Context("when the process has a command line instead of args", func() {
+			BeforeEach(func() {
+				processConfigOverrides = &specs.Process{
+					CommandLine: `cmd.exe /C "echo hi"`,
+				}
+				processConfig = ""
+			})
+
+			It("is valid", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(spec.CommandLine).To(Equal(`cmd.exe /C "echo hi"`))
+			})
+		})
+
+		Context("when the process has settings that are not supported on Windows", func() {
+			BeforeEach(func() {
+				umask := uint32(0022)
+				oomScoreAdj := 100
+				processSpec := specs.Process{
+					Args:        []string{"cmd.exe"},
+					Cwd:         "C:\\",
+					ConsoleSize: &specs.Box{Width: 80, Height: 24},
+					User: specs.User{
+						UID:            1000,
+						Umask:          &umask,
+						AdditionalGids: []uint32{5},
+					},
+					Capabilities:    &specs.LinuxCapabilities{Bounding: []string{"CAP_KILL"}},
+					Rlimits:         []specs.POSIXRlimit{{Type: "RLIMIT_NOFILE", Hard: 1024, Soft: 1024}},
+					NoNewPrivileges: true,
+					ApparmorProfile: "some-profile",
+					OOMScoreAdj:     &oomScoreAdj,
+					SelinuxLabel:    "some-label",
+				}
+				c, err := json.Marshal(processSpec)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(processConfig, c, 0644)).To(Succeed())
+			})
+
+			It("returns an error listing each of them", func() {
+				Expect(err).To(BeAssignableToTypeOf(&config.ProcessConfigValidationError{}))
+				Expect(err.Error()).To(ContainSubstring("consoleSize requires terminal to be set"))
+				Expect(err.Error()).To(ContainSubstring("user.uid and user.gid are not supported, use user.username"))
+				Expect(err.Error()).To(ContainSubstring("user.umask is not supported"))
+				Expect(err.Error()).To(ContainSubstring("user.additionalGids is not supported"))
+				Expect(err.Error()).To(ContainSubstring("capabilities is not supported on Windows"))
+				Expect(err.Error()).To(ContainSubstring("rlimits is not supported on Windows"))
+				Expect(err.Error()).To(ContainSubstring("noNewPrivileges is not supported on Windows"))
+				Expect(err.Error()).To(ContainSubstring("apparmorProfile is not supported on Windows"))
+				Expect(err.Error()).To(ContainSubstring("oomScoreAdj is not supported on Windows"))
+				Expect(err.Error()).To(ContainSubstring("selinuxLabel is not supported on Windows"))
+				Expect(spec).To(BeNil())
+			})
+
+			Context("when the console size is for a terminal", func() {
+				BeforeEach(func() {
+					processSpec := specs.Process{
+						Args:        []string{"cmd.exe"},
+						Cwd:         "C:\\",
+						Terminal:    true,
+						ConsoleSize: &specs.Box{Width: 80, Height: 24},
+					}
+					c, err := json.Marshal(processSpec)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(os.WriteFile(processConfig, c, 0644)).To(Succeed())
+				})
+
+				It("is valid", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec.ConsoleSize).To(Equal(&specs.Box{Width: 80, Height: 24}))
+				})
+			})
+		})
+
 		Context("when the process config file does not exist", func() {
 			BeforeEach(func() {
 				Expect(os.RemoveAll(processConfig)).To(Succeed())
@@ -522,4 +795,149 @@ var _ = Describe("Config", func() {
 			})
 		})
 	})
+
+	Context("CPU limits", func() {
+		u16 := func(v uint16) *uint16 { return &v }
+
+		validateOnCreate := func(cpu *specs.WindowsCPUResources) error {
+			spec := specs.Spec{
+				Version: specs.Version,
+				Process: &specs.Process{Args: []string{"cmd"}, Cwd: "C:\\"},
+				Root:    &specs.Root{Path: "some-volume-guid"},
+				Windows: &specs.Windows{
+					LayerFolders: []string{"a layer"},
+					Resources:    &specs.WindowsResources{CPU: cpu},
+				},
+			}
+			data, err := json.Marshal(&spec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(bundlePath, "config.json"), data, 0666)).To(Succeed())
+
+			_, err = config.ValidateBundle(logger, bundlePath)
+			return err
+		}
+
+		validateOnUpdate := func(cpu *specs.WindowsCPUResources) error {
+			return config.ValidateResources(logger, &specs.Spec{Windows: &specs.Windows{}}, &specs.WindowsResources{CPU: cpu})
+		}
+
+		DescribeTable("accepts the same limits on create and update",
+			func(cpu *specs.WindowsCPUResources) {
+				Expect(validateOnCreate(cpu)).To(Succeed())
+				Expect(validateOnUpdate(cpu)).To(Succeed())
+			},
+			Entry("lowest cpu shares", &specs.WindowsCPUResources{Shares: u16(1)}),
+			Entry("highest cpu shares", &specs.WindowsCPUResources{Shares: u16(10000)}),
+			Entry("lowest cpu maximum", &specs.WindowsCPUResources{Maximum: u16(1)}),
+			Entry("highest cpu maximum", &specs.WindowsCPUResources{Maximum: u16(10000)}),
+		)
+
+		DescribeTable("rejects the same limits on create and update",
+			func(cpu *specs.WindowsCPUResources, message string) {
+				Expect(validateOnCreate(cpu)).To(MatchError(ContainSubstring(message)))
+				Expect(validateOnUpdate(cpu)).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("cpu shares below the range", &specs.WindowsCPUResources{Shares: u16(0)}, "cpu shares 0 must be between 1 and 10000"),
+			Entry("cpu shares above the range", &specs.WindowsCPUResources{Shares: u16(10001)}, "cpu shares 10001 must be between 1 and 10000"),
+			Entry("cpu maximum below the range", &specs.WindowsCPUResources{Maximum: u16(0)}, "cpu maximum 0 must be between 1 and 10000"),
+			Entry("cpu maximum above the range", &specs.WindowsCPUResources{Maximum: u16(10001)}, "cpu maximum 10001 must be between 1 and 10000"),
+			Entry("cpu shares with a cpu maximum", &specs.WindowsCPUResources{Shares: u16(5000), Maximum: u16(5000)}, "cpu shares and cpu maximum cannot both be set"),
+		)
+	})
+
+	Context("Resources", func() {
+		var (
+			spec   *specs.Spec
+			update *specs.WindowsResources
+		)
+
+		BeforeEach(func() {
+			spec = &specs.Spec{Windows: &specs.Windows{}}
+			limit := uint64(512 * 1024 * 1024)
+			shares := uint16(5000)
+			update = &specs.WindowsResources{
+				Memory: &specs.WindowsMemoryResources{Limit: &limit},
+				CPU:    &specs.WindowsCPUResources{Shares: &shares},
+			}
+		})
+
+		It("accepts valid memory and cpu limits", func() {
+			Expect(config.ValidateResources(logger, spec, update)).To(Succeed())
+		})
+
+		Context("when no resources are provided", func() {
+			It("returns an error", func() {
+				err := config.ValidateResources(logger, spec, &specs.WindowsResources{})
+				Expect(err).To(BeAssignableToTypeOf(&config.ResourcesValidationError{}))
+				Expect(err.Error()).To(ContainSubstring("no resources to update"))
+			})
+		})
+
+		Context("when the resources are out of range or conflict", func() {
+			BeforeEach(func() {
+				limit := uint64(1024)
+				shares := uint16(0)
+				maximum := uint16(10001)
+				count := uint64(2)
+				update = &specs.WindowsResources{
+					Memory:  &specs.WindowsMemoryResources{Limit: &limit},
+					CPU:     &specs.WindowsCPUResources{Shares: &shares, Maximum: &maximum, Count: &count},
+					Storage: &specs.WindowsStorageResources{},
+				}
+			})
+
+			It("returns an error describing what is invalid", func() {
+				err := config.ValidateResources(logger, spec, update)
+				Expect(err).To(BeAssignableToTypeOf(&config.ResourcesValidationError{}))
+				Expect(err.Error()).To(ContainSubstring("resources are invalid"))
+				Expect(err.Error()).To(ContainSubstring("memory limit 1024 must be at least 1MB"))
+				Expect(err.Error()).To(ContainSubstring("cpu shares 0 must be between 1 and 10000"))
+				Expect(err.Error()).To(ContainSubstring("cpu maximum 10001 must be between 1 and 10000"))
+				Expect(err.Error()).To(ContainSubstring("cpu shares and cpu maximum cannot both be set"))
+				Expect(err.Error()).To(ContainSubstring("cpu count cannot be updated on a running container"))
+				Expect(err.Error()).To(ContainSubstring("storage limits cannot be updated on a running container"))
+			})
+		})
+
+		Context("when the container was created with a cpu count", func() {
+			BeforeEach(func() {
+				count := uint64(2)
+				spec.Windows.Resources = &specs.WindowsResources{
+					CPU: &specs.WindowsCPUResources{Count: &count},
+				}
+				maximum := uint16(5000)
+				update = &specs.WindowsResources{
+					CPU: &specs.WindowsCPUResources{Maximum: &maximum},
+				}
+			})
+
+			It("rejects a cpu maximum", func() {
+				err := config.ValidateResources(logger, spec, update)
+				Expect(err).To(MatchError(ContainSubstring("cpu maximum cannot be set on a container created with a cpu count")))
+			})
+		})
+
+		Context("when the limits that cannot be updated are unchanged", func() {
+			BeforeEach(func() {
+				count := uint64(2)
+				iops := uint64(100)
+				spec.Windows.Resources = &specs.WindowsResources{
+					CPU:     &specs.WindowsCPUResources{Count: &count},
+					Storage: &specs.WindowsStorageResources{Iops: &iops},
+				}
+				sameCount := uint64(2)
+				sameIops := uint64(100)
+				limit := uint64(512 * 1024 * 1024)
+				update = &specs.WindowsResources{
+					Memory:  &specs.WindowsMemoryResources{Limit: &limit},
+					CPU:     &specs.WindowsCPUResources{Count: &sameCount},
+					Storage: &specs.WindowsStorageResources{Iops: &sameIops},
+				}
+			})
+
+			It("accepts them", func() {
+				Expect(config.ValidateResources(logger, spec, update)).To(Succeed())
+			})
+		})
+	})
 })
@@ -88,3 +88,16 @@ func (e *ProcessConfigValidationError) Error() string {
 
 	return errorStr
 }
+
+type ResourcesValidationError struct {
+	ErrorMessages []string
+}
+
+func (e *ResourcesValidationError) Error() string {
+	errorStr := "resources are invalid:"
+	for _, m := range e.ErrorMessages {
+		errorStr += "\n\t" + m
+	}
+
+	return errorStr
+}
@@ -2,9 +2,11 @@ package container
 
 import (
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -17,15 +19,40 @@ import (
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
 )
 
+// destroyTimeout is how long kill, and cleaning up after a container fails to
+// start, wait for a container to shut down or terminate
 const destroyTimeout = time.Minute
+
+// pipePrefix is how named pipes are addressed both on the host and in the
+// container
+const pipePrefix = `\\.\pipe\`
 const gmsaCredentialsRef = "WINDOWS_GMSA_CREDENTIAL_REF"
 
+// hcsshim only defines the Network resource type for Modify, these are the
+// additional types HCS accepts for live resource updates
+const (
+	MemoryResource    hcsshim.ResourceType = "Memory"
+	ProcessorResource hcsshim.ResourceType = "Processor"
+	UpdateRequest     hcsshim.RequestType  = "Update"
+)
+
+type MemorySettings struct {
+	SizeInMB int64 `json:"SizeInMB"`
+}
+
+type ProcessorSettings struct {
+	Weight  uint64 `json:"Weight,omitempty"`
+	Maximum int64  `json:"Maximum,omitempty"`
+}
+
 type Manager struct {
-	logger    *logrus.Entry
-	hcsClient HCSClient
-	id        string
+	logger         *logrus.Entry
+	hcsClient      HCSClient
+	id             string
+	fileMountsRoot string
 }
 
 type Statistics struct {
@@ -39,16 +66,57 @@ type Statistics struct {
 		} `json:"cpu"`
 		Memory struct {
 			Raw struct {
-				TotalRss uint64 `json:"total_rss,omitempty"`
+				TotalRss          uint64 `json:"total_rss,omitempty"`
+				PrivateWorkingSet uint64 `json:"private_working_set,omitempty"`
 			} `json:"raw,omitempty"`
+			Usage    uint64 `json:"usage,omitempty"`
+			MaxUsage uint64 `json:"max_usage,omitempty"`
+			Limit    uint64 `json:"limit,omitempty"`
 		} `json:"memory,omitempty"`
 		Pids struct {
 			Current uint64 `json:"current,omitempty"`
 			Limit   uint64 `json:"limit,omitempty"`
 		} `json:"pids"`
+		Blkio struct {
+			IoServiceBytesRecursive []BlkioEntry `json:"ioServiceBytesRecursive,omitempty"`
+			IoServicedRecursive     []BlkioEntry `json:"ioServicedRecursive,omitempty"`
+		} `json:"blkio"`
+		NetworkInterfaces []*NetworkInterface `json:"network_interfaces,omitempty"`
 	} `json:"data,omitempty"`
 }
 
+// BlkioEntry and NetworkInterface match the runc types.Stats equivalents.
+// Windows containers have a single sandbox volume, so there is no device
+// number and entries are only distinguished by Op.
+type BlkioEntry struct {
+	Op    string `json:"op,omitempty"`
+	Value uint64 `json:"value,omitempty"`
+}
+
+type NetworkInterface struct {
+	Name      string `json:"name"`
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+// ProcessInfo describes a process running inside a container. CPUTime is the
+// total kernel and user time in nanoseconds and WorkingSet is the private plus
+// shared working set in bytes.
+type ProcessInfo struct {
+	Pid        uint32    `json:"pid"`
+	Image      string    `json:"image"`
+	User       string    `json:"user,omitempty"`
+	StartTime  time.Time `json:"start_time"`
+	CPUTime    uint64    `json:"cpu_time"`
+	WorkingSet uint64    `json:"working_set"`
+}
+
 //go:generate counterfeiter -o fakes/hcsclient.go --fake-name HCSClient . HCSClient
 type HCSClient interface {
 	GetContainers(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
@@ -57,14 +125,18 @@ type HCSClient interface {
 	CreateContainer(string, *hcsshim.ContainerConfig) (hcs.Container, error)
 	OpenContainer(string) (hcs.Container, error)
 	IsPending(error) bool
+	IsNotExist(error) bool
+	IsAlreadyStopped(error) bool
 	GetHNSEndpointByName(string) (*hcsshim.HNSEndpoint, error)
+	SignalProcess(string, int, string) error
 }
 
-func New(logger *logrus.Entry, hcsClient HCSClient, id string) *Manager {
+func New(logger *logrus.Entry, hcsClient HCSClient, id, fileMountsRoot string) *Manager {
 	return &Manager{
-		logger:    logger,
-		hcsClient: hcsClient,
-		id:        id,
+		logger:         logger,
+		hcsClient:      hcsClient,
+		id:             id,
+		fileMountsRoot: fileMountsRoot,
 	}
 }
 
@@ -135,7 +207,10 @@ func (m *Manager) CredentialSpecFromEnv(envs []string, credhubEndpoint string, u
 	return "", nil
 }
 
-func (m *Manager) Create(spec *specs.Spec, credentialSpec string) error {
+// Create creates and starts the compute system. Its owner is set to owner,
+// unless it shares the network of another container, in which case that
+// container is its owner.
+func (m *Manager) Create(spec *specs.Spec, credentialSpec, owner string) error {
 	_, err := m.hcsClient.GetContainerProperties(m.id)
 	if err == nil {
 		return &AlreadyExistsError{Id: m.id}
@@ -158,27 +233,10 @@ func (m *Manager) Create(spec *specs.Spec, credentialSpec string) error {
 		})
 	}
 
-	mappedDirs := []hcsshim.MappedDir{}
-	for _, d := range spec.Mounts {
-		fileInfo, err := os.Stat(d.Source)
-		if err != nil {
-			return err
-		}
-		if !fileInfo.IsDir() {
-			logrus.WithField("mount", d.Source).Error("mount is not a directory, ignoring")
-			continue
-		}
-
-		readOnly, err := m.parseMountOptions(d.Options)
-		if err != nil {
-			return err
-		}
-
-		mappedDirs = append(mappedDirs, hcsshim.MappedDir{
-			HostPath:      d.Source,
-			ContainerPath: destToWindowsPath(d.Destination),
-			ReadOnly:      readOnly,
-		})
+	mappedDirs, mappedPipes, err := m.mappedMounts(spec.Mounts)
+	if err != nil {
+		m.removeFileMounts()
+		return err
 	}
 
 	containerConfig := hcsshim.ContainerConfig{
@@ -188,6 +246,8 @@ func (m *Manager) Create(spec *specs.Spec, credentialSpec string) error {
 		LayerFolderPath:   "ignored",
 		Layers:            layerInfos,
 		MappedDirectories: mappedDirs,
+		MappedPipes:       mappedPipes,
+		Owner:             owner,
 	}
 
 	if credentialSpec != "" {
@@ -196,25 +256,52 @@ func (m *Manager) Create(spec *specs.Spec, credentialSpec string) error {
 
 	if spec.Windows != nil {
 		if spec.Windows.Resources != nil {
+			// the spec has no memory reservation for Windows and HCS has no
+			// equivalent, so the limit is the only memory setting applied
 			if spec.Windows.Resources.Memory != nil {
 				if spec.Windows.Resources.Memory.Limit != nil {
 					memBytes := *spec.Windows.Resources.Memory.Limit
 					containerConfig.MemoryMaximumInMB = int64(memBytes / 1024 / 1024)
 				}
 			}
-			if spec.Windows.Resources.CPU != nil {
-				if spec.Windows.Resources.CPU.Shares != nil {
-					containerConfig.ProcessorWeight = uint64(*spec.Windows.Resources.CPU.Shares)
+			if cpu := spec.Windows.Resources.CPU; cpu != nil {
+				if cpu.Count != nil {
+					containerConfig.ProcessorCount = uint32(*cpu.Count)
+				}
+				if cpu.Shares != nil {
+					containerConfig.ProcessorWeight = uint64(*cpu.Shares)
+				}
+				if cpu.Maximum != nil {
+					containerConfig.ProcessorMaximum = int64(*cpu.Maximum)
+				}
+			}
+			if storage := spec.Windows.Resources.Storage; storage != nil {
+				if storage.Iops != nil {
+					containerConfig.StorageIOPSMaximum = *storage.Iops
+				}
+				if storage.Bps != nil {
+					containerConfig.StorageBandwidthMaximum = *storage.Bps
+				}
+				if storage.SandboxSize != nil {
+					containerConfig.StorageSandboxSize = *storage.SandboxSize
 				}
 			}
 		}
 
+		if spec.Windows.HyperV != nil {
+			containerConfig.HvPartition = true
+			containerConfig.HvRuntime = &hcsshim.HvRuntime{
+				ImagePath: utilityVMPath(spec.Windows),
+			}
+		}
+
 		if spec.Windows.Network != nil {
 			if spec.Windows.Network.NetworkSharedContainerName != "" {
 				containerConfig.NetworkSharedContainerName = spec.Windows.Network.NetworkSharedContainerName
 				containerConfig.Owner = spec.Windows.Network.NetworkSharedContainerName
 				endpoint, err := m.hcsClient.GetHNSEndpointByName(spec.Windows.Network.NetworkSharedContainerName)
 				if err != nil {
+					m.removeFileMounts()
 					return err
 				}
 				containerConfig.EndpointList = []string{endpoint.Id}
@@ -224,40 +311,305 @@ func (m *Manager) Create(spec *specs.Spec, credentialSpec string) error {
 
 	container, err := m.hcsClient.CreateContainer(m.id, &containerConfig)
 	if err != nil {
+		m.removeFileMounts()
 		return err
 	}
 
 	if err := container.Start(); err != nil {
-		if deleteErr := m.deleteContainer(container); deleteErr != nil {
+		if deleteErr := m.deleteContainer(container, destroyTimeout); deleteErr != nil {
 			logrus.Error(deleteErr.Error())
 		}
+		m.removeFileMounts()
 		return err
 	}
 
 	return nil
 }
 
-func (m *Manager) parseMountOptions(options []string) (bool, error) {
+// mappedMounts turns the spec's mounts into the directories and named pipes
+// HCS maps into the container. HCS can only map directories, so files are
+// hard linked into a directory per destination directory, and that directory
+// is mapped in their place. Read-only files on another volume are copied
+// instead, as they can't be linked.
+func (m *Manager) mappedMounts(mounts []specs.Mount) ([]hcsshim.MappedDir, []hcsshim.MappedPipe, error) {
+	mappedDirs := []hcsshim.MappedDir{}
+	mappedPipes := []hcsshim.MappedPipe{}
+
+	destinations := map[string]bool{}
+	dirDestinations := map[string]bool{}
+	fileDirs := []*hcsshim.MappedDir{}
+	fileDirsByDestination := map[string]*hcsshim.MappedDir{}
+
+	for _, d := range mounts {
+		opts, err := m.parseMountOptions(d.Options)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		destination := destToWindowsPath(d.Destination)
+		if isPipe(d.Destination) {
+			destination = d.Destination
+		}
+
+		if destinations[strings.ToLower(destination)] {
+			return nil, nil, &InvalidMountError{Id: m.id, Source: d.Source, Destination: d.Destination, Reason: "another mount has the same destination"}
+		}
+		destinations[strings.ToLower(destination)] = true
+
+		if isPipe(d.Source) {
+			if !isPipe(d.Destination) {
+				return nil, nil, &InvalidMountError{Id: m.id, Source: d.Source, Destination: d.Destination, Reason: "a named pipe can only be mounted at a named pipe"}
+			}
+
+			mappedPipes = append(mappedPipes, hcsshim.MappedPipe{
+				HostPath:          d.Source,
+				ContainerPipeName: d.Destination[len(pipePrefix):],
+			})
+			continue
+		}
+
+		if opts.createSource {
+			if err := os.MkdirAll(d.Source, 0755); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		fileInfo, err := os.Stat(d.Source)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if fileInfo.IsDir() {
+			mappedDirs = append(mappedDirs, hcsshim.MappedDir{
+				HostPath:         d.Source,
+				ContainerPath:    destination,
+				ReadOnly:         opts.readOnly,
+				IOPSMaximum:      opts.iopsMaximum,
+				BandwidthMaximum: opts.bandwidthMaximum,
+			})
+			dirDestinations[strings.ToLower(destination)] = true
+			continue
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			return nil, nil, &InvalidMountError{Id: m.id, Source: d.Source, Destination: d.Destination, Reason: "source is not a directory, file or named pipe"}
+		}
+
+		containerDir := filepath.Dir(destination)
+		if containerDir == destination || filepath.Dir(containerDir) == containerDir {
+			return nil, nil, &InvalidMountError{Id: m.id, Source: d.Source, Destination: d.Destination, Reason: "a file cannot be mounted at the root of a drive"}
+		}
+
+		fileDir, ok := fileDirsByDestination[strings.ToLower(containerDir)]
+		if !ok {
+			fileDir = &hcsshim.MappedDir{
+				HostPath:         filepath.Join(m.fileMountsDir(), strconv.Itoa(len(fileDirs))),
+				ContainerPath:    containerDir,
+				ReadOnly:         opts.readOnly,
+				IOPSMaximum:      opts.iopsMaximum,
+				BandwidthMaximum: opts.bandwidthMaximum,
+			}
+			if err := os.MkdirAll(fileDir.HostPath, 0755); err != nil {
+				return nil, nil, err
+			}
+			fileDirs = append(fileDirs, fileDir)
+			fileDirsByDestination[strings.ToLower(containerDir)] = fileDir
+		}
+
+		if fileDir.ReadOnly != opts.readOnly || fileDir.IOPSMaximum != opts.iopsMaximum || fileDir.BandwidthMaximum != opts.bandwidthMaximum {
+			return nil, nil, &InvalidMountError{Id: m.id, Source: d.Source, Destination: d.Destination, Reason: "files mounted into the same directory must have the same mount options"}
+		}
+
+		if err := linkOrCopy(d.Source, filepath.Join(fileDir.HostPath, filepath.Base(destination)), opts.readOnly); err != nil {
+			if err == errCrossVolumeLink {
+				return nil, nil, &InvalidMountError{Id: m.id, Source: d.Source, Destination: d.Destination, Reason: "a read-write file must be on the same volume as " + m.fileMountsRoot}
+			}
+			return nil, nil, err
+		}
+	}
+
+	for _, fileDir := range fileDirs {
+		if dirDestinations[strings.ToLower(fileDir.ContainerPath)] {
+			return nil, nil, &InvalidMountError{Id: m.id, Destination: fileDir.ContainerPath, Reason: "a directory and files are both mounted at this destination"}
+		}
+		mappedDirs = append(mappedDirs, *fileDir)
+	}
+
+	return mappedDirs, mappedPipes, nil
+}
+
+// FileMountsRoot is the directory under the mount root that holds a directory
+// per container for the directories that are mapped in place of files
+func FileMountsRoot(mountRoot string) string {
+	return filepath.Join(mountRoot, "file-mounts")
+}
+
+// FileMounts returns the ids of the containers that have a file mounts
+// directory under root, including those left behind by a winc that crashed
+func FileMounts(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ids = append(ids, entry.Name())
+		}
+	}
+
+	return ids, nil
+}
+
+func (m *Manager) fileMountsDir() string {
+	return filepath.Join(m.fileMountsRoot, m.id)
+}
+
+// RemoveFileMounts removes the directories that are mapped in place of files
+func (m *Manager) RemoveFileMounts() error {
+	return os.RemoveAll(m.fileMountsDir())
+}
+
+func (m *Manager) removeFileMounts() {
+	if err := m.RemoveFileMounts(); err != nil {
+		m.logger.WithError(err).Warn("could not remove file mounts")
+	}
+}
+
+func isPipe(path string) bool {
+	return len(path) > len(pipePrefix) && strings.EqualFold(path[:len(pipePrefix)], pipePrefix)
+}
+
+var errCrossVolumeLink = errors.New("cannot link across volumes")
+
+// linkOrCopy hard links source to destination. A link can't be made across
+// volumes, so a read-only source on another volume is copied instead; a copy
+// of a read-write source wouldn't share its writes.
+func linkOrCopy(source, destination string, readOnly bool) error {
+	err := os.Link(source, destination)
+	if err == nil {
+		return nil
+	}
+
+	if linkErr, ok := err.(*os.LinkError); !ok || linkErr.Err != windows.ERROR_NOT_SAME_DEVICE {
+		return err
+	}
+
+	if !readOnly {
+		return errCrossVolumeLink
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	// never truncate an existing file, it may be a link to another source
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		// #nosec G104 - the copy error is the one we want to report
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// utilityVMPath defaults to the UtilityVM directory of the base layer, which
+// is the last of the layer folders
+func utilityVMPath(windows *specs.Windows) string {
+	if windows.HyperV.UtilityVMPath != "" {
+		return windows.HyperV.UtilityVMPath
+	}
+
+	if len(windows.LayerFolders) == 0 {
+		return ""
+	}
+
+	return filepath.Join(windows.LayerFolders[len(windows.LayerFolders)-1], "UtilityVM")
+}
+
+type mountOptions struct {
+	readOnly         bool
+	iopsMaximum      uint64
+	bandwidthMaximum uint64
+	createSource     bool
+}
+
+// parseMountOptions understands:
+//
+//	ro, rw           mount read-only (the default) or read-write
+//	bind, rbind      accepted for compatibility, all mounts are bind mounts
+//	nocache          accepted as a hint, HCS has no caching setting for
+//	                 process isolated mounts
+//	iops=<n>         cap the mount at n IO operations per second
+//	bps=<n>          cap the mount at n bytes per second
+//	create=dir       create the source directory if it does not exist
+func (m *Manager) parseMountOptions(options []string) (mountOptions, error) {
+	opts := mountOptions{readOnly: true}
+
 	hasReadOnly := false
 	hasReadWrite := false
+	invalid := []string{}
+	unknown := []string{}
+
 	for _, option := range options {
-		if option == "rw" {
-			hasReadWrite = true
-		} else if option == "ro" {
+		name, value, hasValue := strings.Cut(option, "=")
+
+		switch {
+		case option == "ro":
 			hasReadOnly = true
+		case option == "rw":
+			hasReadWrite = true
+		case option == "bind", option == "rbind", option == "nocache":
+		case name == "iops" && hasValue:
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil || n == 0 {
+				invalid = append(invalid, option)
+			}
+			opts.iopsMaximum = n
+		case name == "bps" && hasValue:
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil || n == 0 {
+				invalid = append(invalid, option)
+			}
+			opts.bandwidthMaximum = n
+		case name == "create" && hasValue:
+			if value != "dir" {
+				invalid = append(invalid, option)
+			}
+			opts.createSource = true
+		default:
+			unknown = append(unknown, option)
 		}
 	}
 
+	if len(unknown) > 0 {
+		return mountOptions{}, &UnknownMountOptionsError{Id: m.id, Options: unknown}
+	}
+
 	if hasReadOnly && hasReadWrite {
-		return false, &InvalidMountOptionsError{Id: m.id, Options: options}
+		return mountOptions{}, &InvalidMountOptionsError{Id: m.id, Options: options}
+	}
+
+	if len(invalid) > 0 {
+		return mountOptions{}, &InvalidMountOptionsError{Id: m.id, Options: invalid}
 	}
 
-	readOnly := true
 	if hasReadWrite {
-		readOnly = false
+		opts.readOnly = false
 	}
 
-	return readOnly, nil
+	return opts, nil
 }
 
 func (m *Manager) Exec(processSpec *specs.Process, createIOPipes bool) (hcs.Process, error) {
@@ -272,8 +624,17 @@ func (m *Manager) Exec(processSpec *specs.Process, createIOPipes bool) (hcs.Proc
 		env[v[0]] = strings.Join(v[1:], "=")
 	}
 
+	// a command line from the spec is already escaped for Windows, so it is
+	// passed through as is rather than rebuilt from args
+	commandLine := processSpec.CommandLine
+	if commandLine == "" {
+		commandLine = makeCmdLine(processSpec.Args)
+	}
+
+	// an empty user runs the process as the image's default user, which is
+	// ContainerAdministrator for the windows server core based rootfs
 	pc := &hcsshim.ProcessConfig{
-		CommandLine:      makeCmdLine(processSpec.Args),
+		CommandLine:      commandLine,
 		CreateStdInPipe:  createIOPipes,
 		CreateStdOutPipe: createIOPipes,
 		CreateStdErrPipe: createIOPipes,
@@ -281,9 +642,20 @@ func (m *Manager) Exec(processSpec *specs.Process, createIOPipes bool) (hcs.Proc
 		User:             processSpec.User.Username,
 		Environment:      env,
 	}
+
+	if processSpec.Terminal {
+		// with an emulated console stderr is written to the console along
+		// with stdout, and HCS rejects a separate stderr pipe
+		pc.EmulateConsole = true
+		pc.CreateStdErrPipe = false
+		if processSpec.ConsoleSize != nil {
+			pc.ConsoleSize = [2]uint{processSpec.ConsoleSize.Height, processSpec.ConsoleSize.Width}
+		}
+	}
+
 	p, err := container.CreateProcess(pc)
 	if err != nil {
-		command := ""
+		command := processSpec.CommandLine
 		if len(processSpec.Args) != 0 {
 			command = processSpec.Args[0]
 		}
@@ -296,6 +668,21 @@ func (m *Manager) Exec(processSpec *specs.Process, createIOPipes bool) (hcs.Proc
 	return p, nil
 }
 
+func (m *Manager) ResizeConsole(pid int, width, height uint16) error {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	process, err := container.OpenProcess(pid)
+	if err != nil {
+		return hcs.CleanError(err)
+	}
+	defer process.Close()
+
+	return hcs.CleanError(process.ResizeConsole(width, height))
+}
+
 func (m *Manager) Stats() (Statistics, error) {
 	var stats Statistics
 
@@ -306,7 +693,7 @@ func (m *Manager) Stats() (Statistics, error) {
 
 	containerStats, err := container.Statistics()
 	if err != nil {
-		return stats, err
+		return stats, hcs.CleanError(err)
 	}
 
 	processListItems, err := container.ProcessList()
@@ -315,15 +702,237 @@ func (m *Manager) Stats() (Statistics, error) {
 	}
 
 	stats.Data.Memory.Raw.TotalRss = containerStats.Memory.UsageCommitBytes
+	stats.Data.Memory.Raw.PrivateWorkingSet = containerStats.Memory.UsagePrivateWorkingSetBytes
+	stats.Data.Memory.Usage = containerStats.Memory.UsageCommitBytes
+	stats.Data.Memory.MaxUsage = containerStats.Memory.UsageCommitPeakBytes
 	stats.Data.CPUStats.CPUUsage.Usage = containerStats.Processor.TotalRuntime100ns * 100
 	stats.Data.CPUStats.CPUUsage.User = containerStats.Processor.RuntimeUser100ns * 100
 	stats.Data.CPUStats.CPUUsage.System = containerStats.Processor.RuntimeKernel100ns * 100
 	stats.Data.Pids.Current = uint64(len(processListItems))
 
+	storage := containerStats.Storage
+	if storage.ReadSizeBytes != 0 || storage.WriteSizeBytes != 0 {
+		stats.Data.Blkio.IoServiceBytesRecursive = []BlkioEntry{
+			{Op: "Read", Value: storage.ReadSizeBytes},
+			{Op: "Write", Value: storage.WriteSizeBytes},
+			{Op: "Total", Value: storage.ReadSizeBytes + storage.WriteSizeBytes},
+		}
+	}
+	if storage.ReadCountNormalized != 0 || storage.WriteCountNormalized != 0 {
+		stats.Data.Blkio.IoServicedRecursive = []BlkioEntry{
+			{Op: "Read", Value: storage.ReadCountNormalized},
+			{Op: "Write", Value: storage.WriteCountNormalized},
+			{Op: "Total", Value: storage.ReadCountNormalized + storage.WriteCountNormalized},
+		}
+	}
+
+	for _, n := range containerStats.Network {
+		stats.Data.NetworkInterfaces = append(stats.Data.NetworkInterfaces, &NetworkInterface{
+			Name:      n.EndpointId,
+			RxBytes:   n.BytesReceived,
+			RxPackets: n.PacketsReceived,
+			RxDropped: n.DroppedPacketsIncoming,
+			TxBytes:   n.BytesSent,
+			TxPackets: n.PacketsSent,
+			TxDropped: n.DroppedPacketsOutgoing,
+		})
+	}
+
 	return stats, nil
 }
 
-func (m *Manager) Delete(force bool) error {
+// Processes lists the processes running in the container. The processes of a
+// hyper-v isolated container run in its utility VM, so their users can't be
+// looked up from the host.
+func (m *Manager) Processes(hyperV bool) ([]ProcessInfo, error) {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return nil, err
+	}
+
+	processListItems, err := container.ProcessList()
+	if err != nil {
+		return nil, err
+	}
+
+	processes := []ProcessInfo{}
+	for _, p := range processListItems {
+		info := ProcessInfo{
+			Pid:        p.ProcessId,
+			Image:      p.ImageName,
+			StartTime:  p.CreateTimestamp,
+			CPUTime:    (p.KernelTime100ns + p.UserTime100ns) * 100,
+			WorkingSet: p.MemoryWorkingSetPrivateBytes + p.MemoryWorkingSetSharedBytes,
+		}
+		if !hyperV {
+			info.User = processUser(p.ProcessId)
+		}
+		processes = append(processes, info)
+	}
+
+	return processes, nil
+}
+
+func (m *Manager) Update(resources *specs.WindowsResources) error {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	requests := []*hcsshim.ResourceModificationRequestResponse{}
+
+	if resources.Memory != nil && resources.Memory.Limit != nil {
+		requests = append(requests, &hcsshim.ResourceModificationRequestResponse{
+			Resource: MemoryResource,
+			Request:  UpdateRequest,
+			Data:     MemorySettings{SizeInMB: int64(*resources.Memory.Limit / 1024 / 1024)},
+		})
+	}
+
+	if resources.CPU != nil && (resources.CPU.Shares != nil || resources.CPU.Maximum != nil) {
+		settings := ProcessorSettings{}
+		if resources.CPU.Shares != nil {
+			settings.Weight = uint64(*resources.CPU.Shares)
+		}
+		if resources.CPU.Maximum != nil {
+			settings.Maximum = int64(*resources.CPU.Maximum)
+		}
+
+		requests = append(requests, &hcsshim.ResourceModificationRequestResponse{
+			Resource: ProcessorResource,
+			Request:  UpdateRequest,
+			Data:     settings,
+		})
+	}
+
+	for _, request := range requests {
+		if err := container.Modify(request); err != nil {
+			return errors.Wrapf(hcs.CleanError(err), "updating %s", request.Resource)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) Pause() error {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	return container.Pause()
+}
+
+func (m *Manager) Resume() error {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	return container.Resume()
+}
+
+func (m *Manager) Kill(signal syscall.Signal, all bool) error {
+	if signal != syscall.SIGTERM && signal != syscall.SIGKILL {
+		return &UnsupportedSignalError{Signal: signal.String()}
+	}
+
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	// HCS has no way of delivering a graceful signal to an individual
+	// process, so every process is terminated regardless of the signal
+	if all {
+		return m.killProcesses(container)
+	}
+
+	if signal == syscall.SIGKILL {
+		return m.terminateContainer(container, destroyTimeout)
+	}
+
+	if err := m.shutdownContainer(container, destroyTimeout); err != nil {
+		return m.terminateContainer(container, destroyTimeout)
+	}
+
+	return nil
+}
+
+func (m *Manager) killProcesses(container hcs.Container) error {
+	processListItems, err := container.ProcessList()
+	if err != nil {
+		return err
+	}
+
+	// killing a process often makes its children exit before they're reached,
+	// so a process that has gone is skipped and the rest are still killed
+	var firstErr error
+	for _, item := range processListItems {
+		if err := m.killProcess(container, int(item.ProcessId)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (m *Manager) killProcess(container hcs.Container, pid int) error {
+	p, err := container.OpenProcess(pid)
+	if err != nil {
+		if m.processGone(err) {
+			return nil
+		}
+		return err
+	}
+
+	killErr := p.Kill()
+	// #nosec G104 - we don't need to capture errors from closing the process handle
+	p.Close()
+	if killErr != nil && !m.processGone(killErr) {
+		return killErr
+	}
+
+	return nil
+}
+
+func (m *Manager) processGone(err error) bool {
+	return m.hcsClient.IsNotExist(err) || m.hcsClient.IsAlreadyStopped(err)
+}
+
+// PreStop sends CTRL_SHUTDOWN to a process in the container and waits up to
+// timeout for it to exit, so that it can drain before the container is shut
+// down
+func (m *Manager) PreStop(pid int, timeout time.Duration) error {
+	start := time.Now()
+	err := m.preStop(pid, timeout)
+	m.logPhase("pre-stop", start, err)
+	return err
+}
+
+func (m *Manager) preStop(pid int, timeout time.Duration) error {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	process, err := container.OpenProcess(pid)
+	if err != nil {
+		return err
+	}
+	// #nosec G104 - we don't need to capture errors from closing the process handle
+	defer process.Close()
+
+	if err := m.hcsClient.SignalProcess(m.id, pid, hcs.CtrlShutdown); err != nil {
+		return err
+	}
+
+	return process.WaitTimeout(timeout)
+}
+
+// Delete stops the container if it is running, waiting up to timeout each for
+// it to shut down and, failing that, to terminate
+func (m *Manager) Delete(force bool, timeout time.Duration) error {
 	container, err := m.hcsClient.OpenContainer(m.id)
 	if err != nil {
 		if force {
@@ -336,10 +945,15 @@ func (m *Manager) Delete(force bool) error {
 		return err
 	}
 
-	return m.deleteContainer(container)
+	if err := m.deleteContainer(container, timeout); err != nil {
+		return err
+	}
+
+	m.removeFileMounts()
+	return nil
 }
 
-func (m *Manager) deleteContainer(container hcs.Container) error {
+func (m *Manager) deleteContainer(container hcs.Container, timeout time.Duration) error {
 	props, err := m.hcsClient.GetContainerProperties(m.id)
 	if err != nil {
 		return err
@@ -349,21 +963,38 @@ func (m *Manager) deleteContainer(container hcs.Container) error {
 		if err := container.Close(); err != nil {
 			return err
 		}
-	} else {
-		if err := m.shutdownContainer(container); err != nil {
-			if err := m.terminateContainer(container); err != nil {
-				return err
-			}
-		}
+		return nil
 	}
 
-	return nil
+	start := time.Now()
+	err = m.shutdownContainer(container, timeout)
+	m.logPhase("shutdown", start, err)
+	if err == nil {
+		return nil
+	}
+
+	start = time.Now()
+	err = m.terminateContainer(container, timeout)
+	m.logPhase("terminate", start, err)
+	return err
 }
 
-func (m *Manager) shutdownContainer(container hcs.Container) error {
+func (m *Manager) logPhase(phase string, start time.Time, err error) {
+	logger := m.logger.WithFields(logrus.Fields{
+		"phase":    phase,
+		"duration": time.Since(start).String(),
+	})
+
+	if err != nil {
+		logger = logger.WithError(err)
+	}
+	logger.Info("stop phase finished")
+}
+
+func (m *Manager) shutdownContainer(container hcs.Container, timeout time.Duration) error {
 	if err := container.Shutdown(); err != nil {
 		if m.hcsClient.IsPending(err) {
-			if err := container.WaitTimeout(destroyTimeout); err != nil {
+			if err := container.WaitTimeout(timeout); err != nil {
 				logrus.Error("hcsContainer.WaitTimeout error after Shutdown", err)
 				return err
 			}
@@ -376,10 +1007,10 @@ func (m *Manager) shutdownContainer(container hcs.Container) error {
 	return nil
 }
 
-func (m *Manager) terminateContainer(container hcs.Container) error {
+func (m *Manager) terminateContainer(container hcs.Container, timeout time.Duration) error {
 	if err := container.Terminate(); err != nil {
 		if m.hcsClient.IsPending(err) {
-			if err := container.WaitTimeout(destroyTimeout); err != nil {
+			if err := container.WaitTimeout(timeout); err != nil {
 				logrus.Error("hcsContainer.WaitTimeout error after Terminate", err)
 				return err
 			}
@@ -392,6 +1023,39 @@ func (m *Manager) terminateContainer(container hcs.Container) error {
 	return nil
 }
 
+// processUser looks up the account a process is running as. HCS does not
+// report this, but process isolated containers share the host kernel so the
+// process can be opened from the host. Container-local accounts often cannot
+// be resolved by the host, in which case the SID is returned instead.
+func processUser(pid uint32) string {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(h)
+
+	var token windows.Token
+	if err := windows.OpenProcessToken(h, windows.TOKEN_QUERY, &token); err != nil {
+		return ""
+	}
+	defer token.Close()
+
+	tokenUser, err := token.GetTokenUser()
+	if err != nil {
+		return ""
+	}
+
+	account, domain, _, err := tokenUser.User.Sid.LookupAccount("")
+	if err != nil {
+		return tokenUser.User.Sid.String()
+	}
+
+	if domain == "" {
+		return account
+	}
+	return domain + `\` + account
+}
+
 func destToWindowsPath(input string) string {
 	vol := filepath.VolumeName(input)
 	if vol == "" {
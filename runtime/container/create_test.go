@@ -23,6 +23,7 @@ var _ = Describe("Create", func() {
 		containerVolume = "containervolume"
 		hostName        = "some-hostname"
 		containerId     = "my-container"
+		owner           = "winc:C:\\ProgramData\\winc"
 	)
 
 	var (
@@ -31,6 +32,7 @@ var _ = Describe("Create", func() {
 		containerManager *container.Manager
 		spec             *specs.Spec
 		credentialSpec   string
+		fileMountsRoot   string
 	)
 
 	BeforeEach(func() {
@@ -60,7 +62,15 @@ var _ = Describe("Create", func() {
 			Out: io.Discard,
 		}).WithField("test", "create")
 
-		containerManager = container.New(logger, hcsClient, containerId)
+		var err error
+		fileMountsRoot, err = os.MkdirTemp("", "file-mounts")
+		Expect(err).NotTo(HaveOccurred())
+
+		containerManager = container.New(logger, hcsClient, containerId, fileMountsRoot)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(fileMountsRoot)).To(Succeed())
 	})
 
 	Context("when the specified container does not already exist", func() {
@@ -88,7 +98,7 @@ var _ = Describe("Create", func() {
 		})
 
 		It("creates and starts it", func() {
-			Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+			Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 			Expect(hcsClient.GetContainerPropertiesCallCount()).To(Equal(1))
 			Expect(hcsClient.GetContainerPropertiesArgsForCall(0)).To(Equal(containerId))
@@ -108,6 +118,8 @@ var _ = Describe("Create", func() {
 				LayerFolderPath:   "ignored",
 				Layers:            expectedHcsshimLayers,
 				MappedDirectories: []hcsshim.MappedDir{},
+				MappedPipes:       []hcsshim.MappedPipe{},
+				Owner:             owner,
 			}))
 
 			Expect(fakeContainer.StartCallCount()).To(Equal(1))
@@ -119,7 +131,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("creates the container with the specified credential spec", func() {
-				Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+				Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -159,7 +171,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates the container with the specified mounts", func() {
-					Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -176,7 +188,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates the container with the specified mounts", func() {
-					Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -193,7 +205,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates the container with the specified mounts", func() {
-					Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -208,49 +220,264 @@ var _ = Describe("Create", func() {
 				})
 
 				It("errors", func() {
-					err := containerManager.Create(spec, credentialSpec)
+					err := containerManager.Create(spec, credentialSpec, owner)
 					Expect(err).To(HaveOccurred())
 					Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountOptionsError{}))
 				})
 			})
 
+			Context("mount options specify rbind and nocache", func() {
+				BeforeEach(func() {
+					spec.Mounts[0].Options = []string{"rbind", "nocache", "rw"}
+
+					expectedMappedDirs[0].ReadOnly = false
+				})
+
+				It("ignores them", func() {
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+					Expect(containerConfig.MappedDirectories).To(ConsistOf(expectedMappedDirs))
+				})
+			})
+
+			Context("mount options specify iops and bps", func() {
+				BeforeEach(func() {
+					spec.Mounts[0].Options = []string{"bind", "iops=500", "bps=1048576"}
+
+					expectedMappedDirs[0].IOPSMaximum = 500
+					expectedMappedDirs[0].BandwidthMaximum = 1048576
+				})
+
+				It("caps the mapped directory", func() {
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+					Expect(containerConfig.MappedDirectories).To(ConsistOf(expectedMappedDirs))
+				})
+
+				Context("a cap is not a positive integer", func() {
+					BeforeEach(func() {
+						spec.Mounts[0].Options = []string{"bind", "iops=lots", "bps=0"}
+					})
+
+					It("errors listing the invalid options", func() {
+						err := containerManager.Create(spec, credentialSpec, owner)
+						Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountOptionsError{}))
+						Expect(err.(*container.InvalidMountOptionsError).Options).To(Equal([]string{"iops=lots", "bps=0"}))
+						Expect(hcsClient.CreateContainerCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("mount options specify unknown options", func() {
+				BeforeEach(func() {
+					spec.Mounts[0].Options = []string{"bind", "noexec", "ro", "size=1g"}
+				})
+
+				It("errors listing the unknown options", func() {
+					err := containerManager.Create(spec, credentialSpec, owner)
+					Expect(err).To(BeAssignableToTypeOf(&container.UnknownMountOptionsError{}))
+					Expect(err).To(MatchError(fmt.Sprintf("unknown mount options for container %s: noexec, size=1g", containerId)))
+					Expect(hcsClient.CreateContainerCallCount()).To(Equal(0))
+				})
+			})
+
 			Context("when the mount does not exist", func() {
 				BeforeEach(func() {
 					Expect(os.RemoveAll(mount)).To(Succeed())
 				})
 
 				It("errors", func() {
-					err := containerManager.Create(spec, credentialSpec)
+					err := containerManager.Create(spec, credentialSpec, owner)
 					Expect(os.IsNotExist(err)).To(BeTrue())
 				})
+
+				Context("mount options specify create=dir", func() {
+					BeforeEach(func() {
+						mount = filepath.Join(mount, "nested")
+						spec.Mounts[0].Source = mount
+						spec.Mounts[0].Options = []string{"create=dir", "rw"}
+
+						expectedMappedDirs[0].HostPath = mount
+						expectedMappedDirs[0].ReadOnly = false
+					})
+
+					AfterEach(func() {
+						Expect(os.RemoveAll(filepath.Dir(mount))).To(Succeed())
+					})
+
+					It("creates the source directory", func() {
+						Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+						Expect(mount).To(BeADirectory())
+						_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+						Expect(containerConfig.MappedDirectories).To(ConsistOf(expectedMappedDirs))
+					})
+				})
+
+				Context("mount options specify create with something other than dir", func() {
+					BeforeEach(func() {
+						spec.Mounts[0].Options = []string{"create=file"}
+					})
+
+					It("errors", func() {
+						err := containerManager.Create(spec, credentialSpec, owner)
+						Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountOptionsError{}))
+						Expect(mount).NotTo(BeADirectory())
+					})
+				})
 			})
 
-			Context("when a file is specified as a mount", func() {
-				var mountFile string
+			Context("when files are specified as mounts", func() {
+				var (
+					mountFile      string
+					otherMountFile string
+					fileMountsDir  string
+				)
 
 				BeforeEach(func() {
 					m, err := os.CreateTemp("", "mountfile")
 					Expect(err).ToNot(HaveOccurred())
+					_, err = m.WriteString("some-cert")
+					Expect(err).ToNot(HaveOccurred())
 					Expect(m.Close()).To(Succeed())
 					mountFile = m.Name()
 
-					spec.Mounts = append(spec.Mounts, specs.Mount{
-						Source:      mountFile,
-						Destination: "foo",
-					})
+					m, err = os.CreateTemp("", "mountfile")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(m.Close()).To(Succeed())
+					otherMountFile = m.Name()
+
+					spec.Mounts = append(spec.Mounts,
+						specs.Mount{Source: mountFile, Destination: "/certs/ca.pem"},
+						specs.Mount{Source: otherMountFile, Destination: "C:\\certs\\other.pem"},
+					)
+
+					fileMountsDir = filepath.Join(fileMountsRoot, containerId)
 				})
 
 				AfterEach(func() {
 					Expect(os.RemoveAll(mountFile)).To(Succeed())
+					Expect(os.RemoveAll(otherMountFile)).To(Succeed())
+					Expect(os.RemoveAll(fileMountsDir)).To(Succeed())
 				})
 
-				It("ignores it", func() {
-					Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+				It("maps a directory containing only those files to their destination directory", func() {
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
-					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
-					actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
-					Expect(actualContainerId).To(Equal(containerId))
+					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+					Expect(containerConfig.MappedDirectories).To(ConsistOf(append(expectedMappedDirs, hcsshim.MappedDir{
+						HostPath:      filepath.Join(fileMountsDir, "0"),
+						ContainerPath: "C:\\certs",
+						ReadOnly:      true,
+					})))
+
+					entries, err := os.ReadDir(filepath.Join(fileMountsDir, "0"))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(entries).To(HaveLen(2))
+
+					contents, err := os.ReadFile(filepath.Join(fileMountsDir, "0", "ca.pem"))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(string(contents)).To(Equal("some-cert"))
+				})
+
+				Context("when the files have different mount options", func() {
+					BeforeEach(func() {
+						spec.Mounts[2].Options = []string{"rw"}
+					})
+
+					It("errors and cleans up", func() {
+						err := containerManager.Create(spec, credentialSpec, owner)
+						Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountError{}))
+						Expect(err.Error()).To(ContainSubstring("files mounted into the same directory must have the same mount options"))
+						Expect(fileMountsDir).NotTo(BeADirectory())
+						Expect(hcsClient.CreateContainerCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when a directory is mounted at the files' destination directory", func() {
+					BeforeEach(func() {
+						spec.Mounts[0].Destination = "C:\\certs"
+					})
+
+					It("errors", func() {
+						err := containerManager.Create(spec, credentialSpec, owner)
+						Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountError{}))
+						Expect(err.Error()).To(ContainSubstring("a directory and files are both mounted at this destination"))
+					})
+				})
+
+				Context("when two files are mounted at the same destination", func() {
+					BeforeEach(func() {
+						spec.Mounts[2].Destination = "c:\\certs\\CA.pem"
+					})
+
+					It("errors without touching either source", func() {
+						err := containerManager.Create(spec, credentialSpec, owner)
+						Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountError{}))
+						Expect(err.Error()).To(ContainSubstring("another mount has the same destination"))
+						Expect(fileMountsDir).NotTo(BeADirectory())
+
+						contents, err := os.ReadFile(mountFile)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(string(contents)).To(Equal("some-cert"))
+					})
+				})
+
+				Context("when a file is mounted at the root of a drive", func() {
+					BeforeEach(func() {
+						spec.Mounts[1].Destination = "C:\\ca.pem"
+					})
+
+					It("errors", func() {
+						err := containerManager.Create(spec, credentialSpec, owner)
+						Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountError{}))
+						Expect(err.Error()).To(ContainSubstring("a file cannot be mounted at the root of a drive"))
+					})
+				})
+
+				Context("when creating the container fails", func() {
+					BeforeEach(func() {
+						hcsClient.CreateContainerReturns(nil, errors.New("couldn't create"))
+					})
+
+					It("removes the directory the files were linked into", func() {
+						Expect(containerManager.Create(spec, credentialSpec, owner)).To(MatchError("couldn't create"))
+						Expect(fileMountsDir).NotTo(BeADirectory())
+					})
+				})
+			})
+
+			Context("when a named pipe is specified as a mount", func() {
+				BeforeEach(func() {
+					spec.Mounts = append(spec.Mounts, specs.Mount{
+						Source:      `\\.\pipe\docker_engine`,
+						Destination: `\\.\pipe\docker_engine_in_container`,
+					})
+				})
+
+				It("maps the pipe into the container", func() {
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
 					Expect(containerConfig.MappedDirectories).To(ConsistOf(expectedMappedDirs))
+					Expect(containerConfig.MappedPipes).To(ConsistOf(hcsshim.MappedPipe{
+						HostPath:          `\\.\pipe\docker_engine`,
+						ContainerPipeName: "docker_engine_in_container",
+					}))
+				})
+
+				Context("when the destination is not a named pipe", func() {
+					BeforeEach(func() {
+						spec.Mounts[1].Destination = "C:\\pipe"
+					})
+
+					It("errors", func() {
+						err := containerManager.Create(spec, credentialSpec, owner)
+						Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountError{}))
+						Expect(err.Error()).To(ContainSubstring("a named pipe can only be mounted at a named pipe"))
+					})
 				})
 			})
 		})
@@ -269,7 +496,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("creates the container with the specified memory limits", func() {
-				Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+				Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -290,7 +517,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("creates the container with the specified cpu limits", func() {
-				Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+				Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -298,6 +525,91 @@ var _ = Describe("Create", func() {
 			})
 		})
 
+		Context("when a cpu count and maximum are specified in the spec", func() {
+			BeforeEach(func() {
+				count := uint64(2)
+				maximum := uint16(5000)
+				spec.Windows.Resources = &specs.WindowsResources{
+					CPU: &specs.WindowsCPUResources{
+						Count:   &count,
+						Maximum: &maximum,
+					},
+				}
+			})
+
+			It("creates the container with the specified processor count and maximum", func() {
+				Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig.ProcessorCount).To(Equal(uint32(2)))
+				Expect(containerConfig.ProcessorMaximum).To(Equal(int64(5000)))
+			})
+		})
+
+		Context("when storage limits are specified in the spec", func() {
+			BeforeEach(func() {
+				iops := uint64(500)
+				bps := uint64(10 * 1024 * 1024)
+				sandboxSize := uint64(30 * 1024 * 1024 * 1024)
+				spec.Windows.Resources = &specs.WindowsResources{
+					Storage: &specs.WindowsStorageResources{
+						Iops:        &iops,
+						Bps:         &bps,
+						SandboxSize: &sandboxSize,
+					},
+				}
+			})
+
+			It("creates the container with the specified storage limits", func() {
+				Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig.StorageIOPSMaximum).To(Equal(uint64(500)))
+				Expect(containerConfig.StorageBandwidthMaximum).To(Equal(uint64(10 * 1024 * 1024)))
+				Expect(containerConfig.StorageSandboxSize).To(Equal(uint64(30 * 1024 * 1024 * 1024)))
+			})
+		})
+
+		Context("when hyper-v isolation is specified in the spec", func() {
+			BeforeEach(func() {
+				spec.Windows.HyperV = &specs.WindowsHyperV{}
+			})
+
+			It("creates a hyper-v partition using the base layer's utility VM", func() {
+				Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig.SystemType).To(Equal("Container"))
+				Expect(containerConfig.HvPartition).To(BeTrue())
+				Expect(containerConfig.HvRuntime).To(Equal(&hcsshim.HvRuntime{
+					ImagePath: filepath.Join("some-rootfs", "UtilityVM"),
+				}))
+			})
+
+			Context("when a utility VM path is specified", func() {
+				BeforeEach(func() {
+					spec.Windows.HyperV.UtilityVMPath = "C:\\uvm"
+				})
+
+				It("uses it", func() {
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+					Expect(containerConfig.HvRuntime.ImagePath).To(Equal("C:\\uvm"))
+				})
+			})
+		})
+
+		Context("when hyper-v isolation is not specified in the spec", func() {
+			It("creates a process isolated container", func() {
+				Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
+
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig.HvPartition).To(BeFalse())
+				Expect(containerConfig.HvRuntime).To(BeNil())
+			})
+		})
+
 		Context("when network settings are specified in the spec", func() {
 			Context("when NetworkSharedContainerName is specified", func() {
 				var (
@@ -314,7 +626,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates the container with a NetworkSharedContainerName and EndpointList", func() {
-					Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -331,9 +643,31 @@ var _ = Describe("Create", func() {
 					})
 
 					It("returns an error", func() {
-						err := containerManager.Create(spec, credentialSpec)
+						err := containerManager.Create(spec, credentialSpec, owner)
 						Expect(err).To(MatchError("couldn't get endpoint"))
 					})
+
+					Context("when a file is mounted", func() {
+						var mountFile string
+
+						BeforeEach(func() {
+							m, err := os.CreateTemp("", "mountfile")
+							Expect(err).ToNot(HaveOccurred())
+							Expect(m.Close()).To(Succeed())
+							mountFile = m.Name()
+
+							spec.Mounts = []specs.Mount{{Source: mountFile, Destination: "C:\\certs\\ca.pem"}}
+						})
+
+						AfterEach(func() {
+							Expect(os.RemoveAll(mountFile)).To(Succeed())
+						})
+
+						It("removes the directory the file was linked into", func() {
+							Expect(containerManager.Create(spec, credentialSpec, owner)).To(MatchError("couldn't get endpoint"))
+							Expect(filepath.Join(fileMountsRoot, containerId)).NotTo(BeADirectory())
+						})
+					})
 				})
 			})
 
@@ -343,7 +677,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates a container without a NetworkSharedContainerName or EndpointList", func() {
-					Expect(containerManager.Create(spec, credentialSpec)).To(Succeed())
+					Expect(containerManager.Create(spec, credentialSpec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -359,7 +693,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("returns an error", func() {
-				err := containerManager.Create(spec, credentialSpec)
+				err := containerManager.Create(spec, credentialSpec, owner)
 				Expect(err).To(MatchError("couldn't create"))
 			})
 		})
@@ -371,7 +705,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("closes but doesn't shutdown or terminate the container", func() {
-				err := containerManager.Create(spec, credentialSpec)
+				err := containerManager.Create(spec, credentialSpec, owner)
 				Expect(err).To(MatchError("couldn't start"))
 
 				Expect(fakeContainer.CloseCallCount()).To(Equal(1))
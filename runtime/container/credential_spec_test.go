@@ -37,7 +37,7 @@ var _ = Describe("CredentialSpec", func() {
 			Out: io.Discard,
 		}).WithField("test", "create")
 
-		containerManager = container.New(logger, hcsClient, containerId)
+		containerManager = container.New(logger, hcsClient, containerId, "")
 	})
 
 	It("loads the credential spec from the path", func() {
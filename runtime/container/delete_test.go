@@ -3,6 +3,9 @@ package container_test
 import (
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
+	"time"
 
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime/container"
@@ -20,17 +23,26 @@ var _ = Describe("Delete", func() {
 		hcsClient        *fakes.HCSClient
 		fakeContainer    *hcsfakes.Container
 		containerManager *container.Manager
+		fileMountsRoot   string
 	)
 
 	BeforeEach(func() {
 		hcsClient = &fakes.HCSClient{}
 		fakeContainer = &hcsfakes.Container{}
 
+		var err error
+		fileMountsRoot, err = os.MkdirTemp("", "file-mounts")
+		Expect(err).NotTo(HaveOccurred())
+
 		logger := (&logrus.Logger{
 			Out: io.Discard,
 		}).WithField("test", "delete")
 
-		containerManager = container.New(logger, hcsClient, containerId)
+		containerManager = container.New(logger, hcsClient, containerId, fileMountsRoot)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(fileMountsRoot)).To(Succeed())
 	})
 
 	Context("when the specified container is running", func() {
@@ -39,7 +51,7 @@ var _ = Describe("Delete", func() {
 		})
 
 		It("deletes it", func() {
-			Expect(containerManager.Delete(false)).To(Succeed())
+			Expect(containerManager.Delete(false, time.Minute)).To(Succeed())
 
 			Expect(hcsClient.OpenContainerCallCount()).To(Equal(1))
 			Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
@@ -50,13 +62,31 @@ var _ = Describe("Delete", func() {
 			Expect(fakeContainer.ShutdownCallCount()).To(Equal(1))
 		})
 
+		Context("when files were mounted into the container", func() {
+			var fileMountsDir string
+
+			BeforeEach(func() {
+				fileMountsDir = filepath.Join(fileMountsRoot, containerId)
+				Expect(os.MkdirAll(filepath.Join(fileMountsDir, "0"), 0755)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(fileMountsDir)).To(Succeed())
+			})
+
+			It("removes the directory they were linked into", func() {
+				Expect(containerManager.Delete(false, time.Minute)).To(Succeed())
+				Expect(fileMountsDir).NotTo(BeADirectory())
+			})
+		})
+
 		Context("when the container was never started", func() {
 			BeforeEach(func() {
 				hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{Stopped: true}, nil)
 			})
 
 			It("closes the container but skips shutting down and terminating it", func() {
-				Expect(containerManager.Delete(false)).To(Succeed())
+				Expect(containerManager.Delete(false, time.Minute)).To(Succeed())
 
 				Expect(fakeContainer.CloseCallCount()).To(Equal(1))
 				Expect(fakeContainer.ShutdownCallCount()).To(Equal(0))
@@ -71,7 +101,7 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("errors", func() {
-					Expect(containerManager.Delete(false)).To(Equal(closeError))
+					Expect(containerManager.Delete(false, time.Minute)).To(Equal(closeError))
 				})
 			})
 		})
@@ -86,7 +116,7 @@ var _ = Describe("Delete", func() {
 			})
 
 			It("calls terminate", func() {
-				Expect(containerManager.Delete(false)).To(Succeed())
+				Expect(containerManager.Delete(false, time.Minute)).To(Succeed())
 				Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
 			})
 
@@ -96,10 +126,15 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("waits for shutdown to finish", func() {
-					Expect(containerManager.Delete(false)).To(Succeed())
+					Expect(containerManager.Delete(false, time.Minute)).To(Succeed())
 					Expect(fakeContainer.TerminateCallCount()).To(Equal(0))
 				})
 
+				It("waits up to the given timeout", func() {
+					Expect(containerManager.Delete(false, 5*time.Second)).To(Succeed())
+					Expect(fakeContainer.WaitTimeoutArgsForCall(0)).To(Equal(5 * time.Second))
+				})
+
 				Context("when shutdown does not finish before the timeout", func() {
 					var shutdownWaitError = errors.New("waiting for shutdown failed")
 
@@ -108,7 +143,7 @@ var _ = Describe("Delete", func() {
 					})
 
 					It("it calls terminate", func() {
-						Expect(containerManager.Delete(false)).To(Succeed())
+						Expect(containerManager.Delete(false, time.Minute)).To(Succeed())
 						Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
 					})
 
@@ -120,7 +155,7 @@ var _ = Describe("Delete", func() {
 						})
 
 						It("errors", func() {
-							Expect(containerManager.Delete(false)).To(Equal(terminateContainerError))
+							Expect(containerManager.Delete(false, time.Minute)).To(Equal(terminateContainerError))
 						})
 
 						Context("when terminate is pending", func() {
@@ -129,7 +164,7 @@ var _ = Describe("Delete", func() {
 							})
 
 							It("waits for terminate to finish", func() {
-								Expect(containerManager.Delete(false)).To(Succeed())
+								Expect(containerManager.Delete(false, time.Minute)).To(Succeed())
 							})
 
 							Context("when terminate does not finish before the timeout", func() {
@@ -140,7 +175,7 @@ var _ = Describe("Delete", func() {
 								})
 
 								It("errors", func() {
-									Expect(containerManager.Delete(false)).To(Equal(terminateWaitError))
+									Expect(containerManager.Delete(false, time.Minute)).To(Equal(terminateWaitError))
 								})
 							})
 						})
@@ -158,7 +193,69 @@ var _ = Describe("Delete", func() {
 		})
 
 		It("errors", func() {
-			Expect(containerManager.Delete(false)).To(Equal(openContainerError))
+			Expect(containerManager.Delete(false, time.Minute)).To(Equal(openContainerError))
+		})
+	})
+})
+
+var _ = Describe("PreStop", func() {
+	const containerId = "container-to-pre-stop"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		fakeProcess      *hcsfakes.Process
+		containerManager *container.Manager
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		fakeProcess = &hcsfakes.Process{}
+
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+		fakeContainer.OpenProcessReturns(fakeProcess, nil)
+
+		logger := (&logrus.Logger{
+			Out: io.Discard,
+		}).WithField("test", "pre-stop")
+
+		containerManager = container.New(logger, hcsClient, containerId, "")
+	})
+
+	It("sends CTRL_SHUTDOWN to the process and waits for it to exit", func() {
+		Expect(containerManager.PreStop(99, 5*time.Second)).To(Succeed())
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(fakeContainer.OpenProcessArgsForCall(0)).To(Equal(99))
+
+		id, pid, signal := hcsClient.SignalProcessArgsForCall(0)
+		Expect(id).To(Equal(containerId))
+		Expect(pid).To(Equal(99))
+		Expect(signal).To(Equal("CtrlShutdown"))
+
+		Expect(fakeProcess.WaitTimeoutArgsForCall(0)).To(Equal(5 * time.Second))
+		Expect(fakeProcess.CloseCallCount()).To(Equal(1))
+	})
+
+	Context("when signalling the process fails", func() {
+		BeforeEach(func() {
+			hcsClient.SignalProcessReturns(errors.New("signal failed"))
+		})
+
+		It("errors without waiting", func() {
+			Expect(containerManager.PreStop(99, 5*time.Second)).To(MatchError("signal failed"))
+			Expect(fakeProcess.WaitTimeoutCallCount()).To(Equal(0))
+			Expect(fakeProcess.CloseCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the process does not exit before the timeout", func() {
+		BeforeEach(func() {
+			fakeProcess.WaitTimeoutReturns(errors.New("timed out"))
+		})
+
+		It("errors", func() {
+			Expect(containerManager.PreStop(99, 5*time.Second)).To(MatchError("timed out"))
 		})
 	})
 })
@@ -1,6 +1,9 @@
 package container
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type AlreadyExistsError struct {
 	Id string
@@ -43,3 +46,34 @@ type InvalidMountOptionsError struct {
 func (e *InvalidMountOptionsError) Error() string {
 	return fmt.Sprintf("invalid mount options for container %s: %+v", e.Id, e.Options)
 }
+
+type UnknownMountOptionsError struct {
+	Id      string
+	Options []string
+}
+
+func (e *UnknownMountOptionsError) Error() string {
+	return fmt.Sprintf("unknown mount options for container %s: %s", e.Id, strings.Join(e.Options, ", "))
+}
+
+type InvalidMountError struct {
+	Id          string
+	Source      string
+	Destination string
+	Reason      string
+}
+
+func (e *InvalidMountError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("invalid mount at %s for container %s: %s", e.Destination, e.Id, e.Reason)
+	}
+	return fmt.Sprintf("invalid mount of %s at %s for container %s: %s", e.Source, e.Destination, e.Id, e.Reason)
+}
+
+type UnsupportedSignalError struct {
+	Signal string
+}
+
+func (e *UnsupportedSignalError) Error() string {
+	return fmt.Sprintf("unsupported signal: %s", e.Signal)
+}
@@ -34,7 +34,7 @@ var _ = Describe("Exec", func() {
 			Out: io.Discard,
 		}).WithField("test", "exec")
 
-		containerManager = container.New(logger, hcsClient, containerId)
+		containerManager = container.New(logger, hcsClient, containerId, "")
 	})
 
 	Context("when the specified container exists", func() {
@@ -85,6 +85,48 @@ var _ = Describe("Exec", func() {
 			})
 		})
 
+		Context("when the process has a terminal", func() {
+			BeforeEach(func() {
+				processSpec.Terminal = true
+				processSpec.ConsoleSize = &specs.Box{Width: 120, Height: 40}
+				expectedProcessConfig.EmulateConsole = true
+				expectedProcessConfig.CreateStdErrPipe = false
+				expectedProcessConfig.ConsoleSize = [2]uint{40, 120}
+			})
+
+			It("emulates a console of the requested size with no stderr pipe", func() {
+				_, err := containerManager.Exec(&processSpec, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeContainer.CreateProcessArgsForCall(0)).To(Equal(expectedProcessConfig))
+			})
+
+			Context("when no console size is given", func() {
+				BeforeEach(func() {
+					processSpec.ConsoleSize = nil
+					expectedProcessConfig.ConsoleSize = [2]uint{}
+				})
+
+				It("leaves the size to HCS", func() {
+					_, err := containerManager.Exec(&processSpec, true)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(fakeContainer.CreateProcessArgsForCall(0)).To(Equal(expectedProcessConfig))
+				})
+			})
+		})
+
+		Context("when the process has a command line", func() {
+			BeforeEach(func() {
+				processSpec.CommandLine = `cmd.exe /C "echo ""hi"""`
+				expectedProcessConfig.CommandLine = processSpec.CommandLine
+			})
+
+			It("uses it as is instead of building one from args", func() {
+				_, err := containerManager.Exec(&processSpec, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeContainer.CreateProcessArgsForCall(0)).To(Equal(expectedProcessConfig))
+			})
+		})
+
 		Context("when a command and arguments contain spaces", func() {
 			It("quotes the argument", func() {
 				commandArgs := []string{"command with spaces.exe", "arg with spaces", "other arg"}
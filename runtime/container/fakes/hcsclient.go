@@ -63,6 +63,28 @@ type HCSClient struct {
 		result1 *hcsshim.HNSEndpoint
 		result2 error
 	}
+	IsAlreadyStoppedStub        func(error) bool
+	isAlreadyStoppedMutex       sync.RWMutex
+	isAlreadyStoppedArgsForCall []struct {
+		arg1 error
+	}
+	isAlreadyStoppedReturns struct {
+		result1 bool
+	}
+	isAlreadyStoppedReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	IsNotExistStub        func(error) bool
+	isNotExistMutex       sync.RWMutex
+	isNotExistArgsForCall []struct {
+		arg1 error
+	}
+	isNotExistReturns struct {
+		result1 bool
+	}
+	isNotExistReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsPendingStub        func(error) bool
 	isPendingMutex       sync.RWMutex
 	isPendingArgsForCall []struct {
@@ -100,6 +122,19 @@ type HCSClient struct {
 		result1 hcs.Container
 		result2 error
 	}
+	SignalProcessStub        func(string, int, string) error
+	signalProcessMutex       sync.RWMutex
+	signalProcessArgsForCall []struct {
+		arg1 string
+		arg2 int
+		arg3 string
+	}
+	signalProcessReturns struct {
+		result1 error
+	}
+	signalProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -361,6 +396,128 @@ func (fake *HCSClient) GetHNSEndpointByNameReturnsOnCall(i int, result1 *hcsshim
 	}{result1, result2}
 }
 
+func (fake *HCSClient) IsAlreadyStopped(arg1 error) bool {
+	fake.isAlreadyStoppedMutex.Lock()
+	ret, specificReturn := fake.isAlreadyStoppedReturnsOnCall[len(fake.isAlreadyStoppedArgsForCall)]
+	fake.isAlreadyStoppedArgsForCall = append(fake.isAlreadyStoppedArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.IsAlreadyStoppedStub
+	fakeReturns := fake.isAlreadyStoppedReturns
+	fake.recordInvocation("IsAlreadyStopped", []interface{}{arg1})
+	fake.isAlreadyStoppedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *HCSClient) IsAlreadyStoppedCallCount() int {
+	fake.isAlreadyStoppedMutex.RLock()
+	defer fake.isAlreadyStoppedMutex.RUnlock()
+	return len(fake.isAlreadyStoppedArgsForCall)
+}
+
+func (fake *HCSClient) IsAlreadyStoppedCalls(stub func(error) bool) {
+	fake.isAlreadyStoppedMutex.Lock()
+	defer fake.isAlreadyStoppedMutex.Unlock()
+	fake.IsAlreadyStoppedStub = stub
+}
+
+func (fake *HCSClient) IsAlreadyStoppedArgsForCall(i int) error {
+	fake.isAlreadyStoppedMutex.RLock()
+	defer fake.isAlreadyStoppedMutex.RUnlock()
+	argsForCall := fake.isAlreadyStoppedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HCSClient) IsAlreadyStoppedReturns(result1 bool) {
+	fake.isAlreadyStoppedMutex.Lock()
+	defer fake.isAlreadyStoppedMutex.Unlock()
+	fake.IsAlreadyStoppedStub = nil
+	fake.isAlreadyStoppedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *HCSClient) IsAlreadyStoppedReturnsOnCall(i int, result1 bool) {
+	fake.isAlreadyStoppedMutex.Lock()
+	defer fake.isAlreadyStoppedMutex.Unlock()
+	fake.IsAlreadyStoppedStub = nil
+	if fake.isAlreadyStoppedReturnsOnCall == nil {
+		fake.isAlreadyStoppedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isAlreadyStoppedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *HCSClient) IsNotExist(arg1 error) bool {
+	fake.isNotExistMutex.Lock()
+	ret, specificReturn := fake.isNotExistReturnsOnCall[len(fake.isNotExistArgsForCall)]
+	fake.isNotExistArgsForCall = append(fake.isNotExistArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.IsNotExistStub
+	fakeReturns := fake.isNotExistReturns
+	fake.recordInvocation("IsNotExist", []interface{}{arg1})
+	fake.isNotExistMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *HCSClient) IsNotExistCallCount() int {
+	fake.isNotExistMutex.RLock()
+	defer fake.isNotExistMutex.RUnlock()
+	return len(fake.isNotExistArgsForCall)
+}
+
+func (fake *HCSClient) IsNotExistCalls(stub func(error) bool) {
+	fake.isNotExistMutex.Lock()
+	defer fake.isNotExistMutex.Unlock()
+	fake.IsNotExistStub = stub
+}
+
+func (fake *HCSClient) IsNotExistArgsForCall(i int) error {
+	fake.isNotExistMutex.RLock()
+	defer fake.isNotExistMutex.RUnlock()
+	argsForCall := fake.isNotExistArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HCSClient) IsNotExistReturns(result1 bool) {
+	fake.isNotExistMutex.Lock()
+	defer fake.isNotExistMutex.Unlock()
+	fake.IsNotExistStub = nil
+	fake.isNotExistReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *HCSClient) IsNotExistReturnsOnCall(i int, result1 bool) {
+	fake.isNotExistMutex.Lock()
+	defer fake.isNotExistMutex.Unlock()
+	fake.IsNotExistStub = nil
+	if fake.isNotExistReturnsOnCall == nil {
+		fake.isNotExistReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isNotExistReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *HCSClient) IsPending(arg1 error) bool {
 	fake.isPendingMutex.Lock()
 	ret, specificReturn := fake.isPendingReturnsOnCall[len(fake.isPendingArgsForCall)]
@@ -550,6 +707,69 @@ func (fake *HCSClient) OpenContainerReturnsOnCall(i int, result1 hcs.Container,
 	}{result1, result2}
 }
 
+func (fake *HCSClient) SignalProcess(arg1 string, arg2 int, arg3 string) error {
+	fake.signalProcessMutex.Lock()
+	ret, specificReturn := fake.signalProcessReturnsOnCall[len(fake.signalProcessArgsForCall)]
+	fake.signalProcessArgsForCall = append(fake.signalProcessArgsForCall, struct {
+		arg1 string
+		arg2 int
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.SignalProcessStub
+	fakeReturns := fake.signalProcessReturns
+	fake.recordInvocation("SignalProcess", []interface{}{arg1, arg2, arg3})
+	fake.signalProcessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *HCSClient) SignalProcessCallCount() int {
+	fake.signalProcessMutex.RLock()
+	defer fake.signalProcessMutex.RUnlock()
+	return len(fake.signalProcessArgsForCall)
+}
+
+func (fake *HCSClient) SignalProcessCalls(stub func(string, int, string) error) {
+	fake.signalProcessMutex.Lock()
+	defer fake.signalProcessMutex.Unlock()
+	fake.SignalProcessStub = stub
+}
+
+func (fake *HCSClient) SignalProcessArgsForCall(i int) (string, int, string) {
+	fake.signalProcessMutex.RLock()
+	defer fake.signalProcessMutex.RUnlock()
+	argsForCall := fake.signalProcessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HCSClient) SignalProcessReturns(result1 error) {
+	fake.signalProcessMutex.Lock()
+	defer fake.signalProcessMutex.Unlock()
+	fake.SignalProcessStub = nil
+	fake.signalProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) SignalProcessReturnsOnCall(i int, result1 error) {
+	fake.signalProcessMutex.Lock()
+	defer fake.signalProcessMutex.Unlock()
+	fake.SignalProcessStub = nil
+	if fake.signalProcessReturnsOnCall == nil {
+		fake.signalProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.signalProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *HCSClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -561,12 +781,18 @@ func (fake *HCSClient) Invocations() map[string][][]interface{} {
 	defer fake.getContainersMutex.RUnlock()
 	fake.getHNSEndpointByNameMutex.RLock()
 	defer fake.getHNSEndpointByNameMutex.RUnlock()
+	fake.isAlreadyStoppedMutex.RLock()
+	defer fake.isAlreadyStoppedMutex.RUnlock()
+	fake.isNotExistMutex.RLock()
+	defer fake.isNotExistMutex.RUnlock()
 	fake.isPendingMutex.RLock()
 	defer fake.isPendingMutex.RUnlock()
 	fake.nameToGuidMutex.RLock()
 	defer fake.nameToGuidMutex.RUnlock()
 	fake.openContainerMutex.RLock()
 	defer fake.openContainerMutex.RUnlock()
+	fake.signalProcessMutex.RLock()
+	defer fake.signalProcessMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,218 @@
+package container_test
+
+import (
+	"errors"
+	"io"
+	"syscall"
+
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/Microsoft/hcsshim"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Kill", func() {
+	const containerId = "container-to-kill"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		containerManager *container.Manager
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+
+		logger := (&logrus.Logger{
+			Out: io.Discard,
+		}).WithField("test", "kill")
+
+		containerManager = container.New(logger, hcsClient, containerId, "")
+	})
+
+	Context("when sent SIGTERM", func() {
+		It("shuts the container down", func() {
+			Expect(containerManager.Kill(syscall.SIGTERM, false)).To(Succeed())
+
+			Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(1))
+			Expect(fakeContainer.TerminateCallCount()).To(Equal(0))
+		})
+
+		Context("when shutting down the container fails", func() {
+			BeforeEach(func() {
+				fakeContainer.ShutdownReturns(errors.New("shutdown failed"))
+			})
+
+			It("terminates the container", func() {
+				Expect(containerManager.Kill(syscall.SIGTERM, false)).To(Succeed())
+
+				Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the shutdown is pending and does not finish in time", func() {
+			BeforeEach(func() {
+				fakeContainer.ShutdownReturns(errors.New("pending"))
+				hcsClient.IsPendingReturns(true)
+				fakeContainer.WaitTimeoutReturnsOnCall(0, errors.New("timed out"))
+			})
+
+			It("terminates the container", func() {
+				Expect(containerManager.Kill(syscall.SIGTERM, false)).To(Succeed())
+
+				Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("when sent SIGKILL", func() {
+		It("terminates the container", func() {
+			Expect(containerManager.Kill(syscall.SIGKILL, false)).To(Succeed())
+
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(0))
+			Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
+		})
+
+		Context("when terminating the container fails", func() {
+			BeforeEach(func() {
+				fakeContainer.TerminateReturns(errors.New("terminate failed"))
+			})
+
+			It("errors", func() {
+				Expect(containerManager.Kill(syscall.SIGKILL, false)).To(MatchError("terminate failed"))
+			})
+		})
+	})
+
+	Context("when sent an unsupported signal", func() {
+		It("errors without opening the container", func() {
+			err := containerManager.Kill(syscall.SIGHUP, false)
+			Expect(err).To(BeAssignableToTypeOf(&container.UnsupportedSignalError{}))
+
+			Expect(hcsClient.OpenContainerCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when all is true", func() {
+		var (
+			process1 *hcsfakes.Process
+			process2 *hcsfakes.Process
+		)
+
+		BeforeEach(func() {
+			process1 = &hcsfakes.Process{}
+			process2 = &hcsfakes.Process{}
+
+			fakeContainer.ProcessListReturns([]hcsshim.ProcessListItem{
+				{ProcessId: 11},
+				{ProcessId: 22},
+			}, nil)
+			fakeContainer.OpenProcessReturnsOnCall(0, process1, nil)
+			fakeContainer.OpenProcessReturnsOnCall(1, process2, nil)
+		})
+
+		It("kills every process in the container, leaving the container running", func() {
+			Expect(containerManager.Kill(syscall.SIGTERM, true)).To(Succeed())
+
+			Expect(fakeContainer.OpenProcessCallCount()).To(Equal(2))
+			Expect(fakeContainer.OpenProcessArgsForCall(0)).To(Equal(11))
+			Expect(fakeContainer.OpenProcessArgsForCall(1)).To(Equal(22))
+
+			Expect(process1.KillCallCount()).To(Equal(1))
+			Expect(process1.CloseCallCount()).To(Equal(1))
+			Expect(process2.KillCallCount()).To(Equal(1))
+			Expect(process2.CloseCallCount()).To(Equal(1))
+
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(0))
+			Expect(fakeContainer.TerminateCallCount()).To(Equal(0))
+		})
+
+		Context("when listing the processes fails", func() {
+			BeforeEach(func() {
+				fakeContainer.ProcessListReturns(nil, errors.New("process list failed"))
+			})
+
+			It("errors", func() {
+				Expect(containerManager.Kill(syscall.SIGKILL, true)).To(MatchError("process list failed"))
+			})
+		})
+
+		Context("when killing a process fails", func() {
+			BeforeEach(func() {
+				process1.KillReturns(errors.New("kill failed"))
+			})
+
+			It("closes the process, kills the rest and errors", func() {
+				Expect(containerManager.Kill(syscall.SIGKILL, true)).To(MatchError("kill failed"))
+
+				Expect(process1.CloseCallCount()).To(Equal(1))
+				Expect(process2.KillCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when a listed process has already exited", func() {
+			var exitedErr error
+
+			BeforeEach(func() {
+				exitedErr = errors.New("process has exited")
+				hcsClient.IsAlreadyStoppedStub = func(err error) bool {
+					return err == exitedErr
+				}
+			})
+
+			Context("before it is opened", func() {
+				BeforeEach(func() {
+					fakeContainer.OpenProcessReturnsOnCall(0, nil, exitedErr)
+				})
+
+				It("kills the rest", func() {
+					Expect(containerManager.Kill(syscall.SIGKILL, true)).To(Succeed())
+					Expect(process2.KillCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("before it is killed", func() {
+				BeforeEach(func() {
+					process1.KillReturns(exitedErr)
+				})
+
+				It("kills the rest", func() {
+					Expect(containerManager.Kill(syscall.SIGKILL, true)).To(Succeed())
+					Expect(process1.CloseCallCount()).To(Equal(1))
+					Expect(process2.KillCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when a listed process can no longer be found", func() {
+			BeforeEach(func() {
+				notFoundErr := errors.New("process not found")
+				hcsClient.IsNotExistStub = func(err error) bool {
+					return err == notFoundErr
+				}
+				fakeContainer.OpenProcessReturnsOnCall(0, nil, notFoundErr)
+			})
+
+			It("kills the rest", func() {
+				Expect(containerManager.Kill(syscall.SIGKILL, true)).To(Succeed())
+				Expect(process2.KillCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("when opening the container fails", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("open failed"))
+		})
+
+		It("errors", func() {
+			Expect(containerManager.Kill(syscall.SIGTERM, false)).To(MatchError("open failed"))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package container_test
+
+import (
+	"errors"
+	"io"
+
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pause and Resume", func() {
+	const containerId = "container-to-pause"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		containerManager *container.Manager
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+
+		logger := (&logrus.Logger{
+			Out: io.Discard,
+		}).WithField("test", "pause")
+
+		containerManager = container.New(logger, hcsClient, containerId, "")
+	})
+
+	It("pauses the container", func() {
+		Expect(containerManager.Pause()).To(Succeed())
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(fakeContainer.PauseCallCount()).To(Equal(1))
+	})
+
+	It("resumes the container", func() {
+		Expect(containerManager.Resume()).To(Succeed())
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(fakeContainer.ResumeCallCount()).To(Equal(1))
+	})
+
+	Context("when pausing fails", func() {
+		BeforeEach(func() {
+			fakeContainer.PauseReturns(errors.New("pause failed"))
+		})
+
+		It("errors", func() {
+			Expect(containerManager.Pause()).To(MatchError("pause failed"))
+		})
+	})
+
+	Context("when opening the container fails", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("open failed"))
+		})
+
+		It("errors", func() {
+			Expect(containerManager.Pause()).To(MatchError("open failed"))
+			Expect(containerManager.Resume()).To(MatchError("open failed"))
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package container_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/Microsoft/hcsshim"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Processes", func() {
+	const containerId = "container-for-ps"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		containerManager *container.Manager
+		startTime        time.Time
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+
+		logger := (&logrus.Logger{
+			Out: io.Discard,
+		}).WithField("test", "ps")
+
+		containerManager = container.New(logger, hcsClient, containerId, "")
+
+		startTime = time.Now().UTC()
+		fakeContainer.ProcessListReturns([]hcsshim.ProcessListItem{
+			{
+				ProcessId:                    0xffffff00,
+				ImageName:                    "cmd.exe",
+				CreateTimestamp:              startTime,
+				KernelTime100ns:              10,
+				UserTime100ns:                5,
+				MemoryWorkingSetPrivateBytes: 1000,
+				MemoryWorkingSetSharedBytes:  24,
+			},
+		}, nil)
+	})
+
+	It("returns the processes in the container", func() {
+		processes, err := containerManager.Processes(false)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(processes).To(Equal([]container.ProcessInfo{
+			{
+				Pid:        0xffffff00,
+				Image:      "cmd.exe",
+				StartTime:  startTime,
+				CPUTime:    1500,
+				WorkingSet: 1024,
+			},
+		}))
+	})
+
+	Context("the container is hyper-v isolated", func() {
+		BeforeEach(func() {
+			// a pid the host can open, which in a utility VM would be a
+			// different process
+			fakeContainer.ProcessListReturns([]hcsshim.ProcessListItem{
+				{ProcessId: uint32(os.Getpid()), ImageName: "cmd.exe", CreateTimestamp: startTime},
+			}, nil)
+		})
+
+		It("doesn't look up the users of the processes on the host", func() {
+			processes, err := containerManager.Processes(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processes).To(HaveLen(1))
+			Expect(processes[0].User).To(BeEmpty())
+		})
+	})
+
+	Context("opening the container fails", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("couldn't open"))
+		})
+
+		It("returns the error", func() {
+			_, err := containerManager.Processes(false)
+			Expect(err).To(MatchError("couldn't open"))
+		})
+	})
+
+	Context("listing the processes fails", func() {
+		BeforeEach(func() {
+			fakeContainer.ProcessListReturns(nil, errors.New("couldn't list"))
+		})
+
+		It("returns the error", func() {
+			_, err := containerManager.Processes(false)
+			Expect(err).To(MatchError("couldn't list"))
+		})
+	})
+})
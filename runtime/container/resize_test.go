@@ -0,0 +1,81 @@
+package container_test
+
+import (
+	"errors"
+	"io"
+
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("ResizeConsole", func() {
+	const containerId = "some-container"
+	var (
+		hcsClient        *fakes.HCSClient
+		containerManager *container.Manager
+		fakeContainer    *hcsfakes.Container
+		fakeProcess      *hcsfakes.Process
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		fakeProcess = &hcsfakes.Process{}
+
+		logger := (&logrus.Logger{
+			Out: io.Discard,
+		}).WithField("test", "resize")
+
+		containerManager = container.New(logger, hcsClient, containerId, "")
+
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+		fakeContainer.OpenProcessReturns(fakeProcess, nil)
+	})
+
+	It("resizes the console of the process", func() {
+		Expect(containerManager.ResizeConsole(42, 120, 40)).To(Succeed())
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(fakeContainer.OpenProcessArgsForCall(0)).To(Equal(42))
+
+		width, height := fakeProcess.ResizeConsoleArgsForCall(0)
+		Expect(width).To(Equal(uint16(120)))
+		Expect(height).To(Equal(uint16(40)))
+		Expect(fakeProcess.CloseCallCount()).To(Equal(1))
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("container does not exist"))
+		})
+
+		It("errors", func() {
+			Expect(containerManager.ResizeConsole(42, 120, 40)).To(MatchError("container does not exist"))
+		})
+	})
+
+	Context("when the process does not exist", func() {
+		BeforeEach(func() {
+			fakeContainer.OpenProcessReturns(nil, errors.New("process does not exist"))
+		})
+
+		It("errors", func() {
+			Expect(containerManager.ResizeConsole(42, 120, 40)).To(MatchError("process does not exist"))
+		})
+	})
+
+	Context("when resizing fails", func() {
+		BeforeEach(func() {
+			fakeProcess.ResizeConsoleReturns(errors.New("not a console process"))
+		})
+
+		It("errors and closes the process", func() {
+			Expect(containerManager.ResizeConsole(42, 120, 40)).To(MatchError("not a console process"))
+			Expect(fakeProcess.CloseCallCount()).To(Equal(1))
+		})
+	})
+})
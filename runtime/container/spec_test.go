@@ -64,7 +64,7 @@ var _ = Describe("Spec", func() {
 			Out: io.Discard,
 		}).WithField("test", "create")
 
-		containerManager = container.New(logger, hcsClient, containerId)
+		containerManager = container.New(logger, hcsClient, containerId, "")
 	})
 
 	It("loads and validates the spec from the bundle path", func() {
@@ -101,7 +101,7 @@ var _ = Describe("Spec", func() {
 
 	Context("the container id doesn't match the bundle path", func() {
 		BeforeEach(func() {
-			containerManager = container.New(logger, hcsClient, "a-different-id")
+			containerManager = container.New(logger, hcsClient, "a-different-id", "")
 		})
 
 		It("returns an error", func() {
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"io"
 	"os"
+	"syscall"
 
+	"code.cloudfoundry.org/winc/hcs"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/container/fakes"
@@ -30,7 +32,7 @@ var _ = Describe("Stats", func() {
 			Out: io.Discard,
 		}).WithField("test", "stats")
 
-		containerManager = container.New(logger, hcsClient, containerId)
+		containerManager = container.New(logger, hcsClient, containerId, "")
 
 		fakeContainer = &hcsfakes.Container{}
 		hcsClient.OpenContainerReturns(fakeContainer, nil)
@@ -44,13 +46,32 @@ var _ = Describe("Stats", func() {
 		BeforeEach(func() {
 			fakeContainer.StatisticsReturns(hcsshim.Statistics{
 				Memory: hcsshim.MemoryStats{
-					UsageCommitBytes: 666,
+					UsageCommitBytes:            666,
+					UsageCommitPeakBytes:        777,
+					UsagePrivateWorkingSetBytes: 555,
 				},
 				Processor: hcsshim.ProcessorStats{
 					TotalRuntime100ns:  123,
 					RuntimeKernel100ns: 101,
 					RuntimeUser100ns:   22,
 				},
+				Storage: hcsshim.StorageStats{
+					ReadCountNormalized:  3,
+					ReadSizeBytes:        300,
+					WriteCountNormalized: 4,
+					WriteSizeBytes:       400,
+				},
+				Network: []hcsshim.NetworkStats{
+					{
+						EndpointId:             "some-endpoint-id",
+						BytesReceived:          10,
+						PacketsReceived:        1,
+						DroppedPacketsIncoming: 2,
+						BytesSent:              20,
+						PacketsSent:            3,
+						DroppedPacketsOutgoing: 4,
+					},
+				},
 			}, nil)
 			fakeContainer.ProcessListReturns([]hcsshim.ProcessListItem{hcsshim.ProcessListItem{}}, nil)
 		})
@@ -64,11 +85,35 @@ var _ = Describe("Stats", func() {
 
 			expectedStats := container.Statistics{}
 			expectedStats.Data.Memory.Raw.TotalRss = 666
+			expectedStats.Data.Memory.Raw.PrivateWorkingSet = 555
+			expectedStats.Data.Memory.Usage = 666
+			expectedStats.Data.Memory.MaxUsage = 777
 			expectedStats.Data.CPUStats.CPUUsage.Usage = 12300
 			expectedStats.Data.CPUStats.CPUUsage.System = 10100
 			expectedStats.Data.CPUStats.CPUUsage.User = 2200
 			expectedStats.Data.Pids.Current = 1
 			expectedStats.Data.Pids.Limit = 0
+			expectedStats.Data.Blkio.IoServiceBytesRecursive = []container.BlkioEntry{
+				{Op: "Read", Value: 300},
+				{Op: "Write", Value: 400},
+				{Op: "Total", Value: 700},
+			}
+			expectedStats.Data.Blkio.IoServicedRecursive = []container.BlkioEntry{
+				{Op: "Read", Value: 3},
+				{Op: "Write", Value: 4},
+				{Op: "Total", Value: 7},
+			}
+			expectedStats.Data.NetworkInterfaces = []*container.NetworkInterface{
+				{
+					Name:      "some-endpoint-id",
+					RxBytes:   10,
+					RxPackets: 1,
+					RxDropped: 2,
+					TxBytes:   20,
+					TxPackets: 3,
+					TxDropped: 4,
+				},
+			}
 			Expect(stats).To(Equal(expectedStats))
 		})
 	})
@@ -86,6 +131,17 @@ var _ = Describe("Stats", func() {
 		})
 	})
 
+	Context("when the container is out of memory", func() {
+		BeforeEach(func() {
+			fakeContainer.StatisticsReturns(hcsshim.Statistics{}, &hcsshim.ContainerError{Err: syscall.Errno(0x5af)})
+		})
+
+		It("returns a LowMemoryError", func() {
+			_, err := containerManager.Stats()
+			Expect(err).To(BeAssignableToTypeOf(&hcs.LowMemoryError{}))
+		})
+	})
+
 	Context("when getting the stats fails", func() {
 		var statsError = errors.New("stats failed")
 
@@ -0,0 +1,106 @@
+package container_test
+
+import (
+	"errors"
+	"io"
+
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/Microsoft/hcsshim"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Update", func() {
+	const containerId = "container-to-update"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		containerManager *container.Manager
+		memoryLimit      uint64
+		cpuShares        uint16
+		resources        *specs.WindowsResources
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+
+		logger := (&logrus.Logger{
+			Out: io.Discard,
+		}).WithField("test", "update")
+
+		containerManager = container.New(logger, hcsClient, containerId, "")
+
+		memoryLimit = 512 * 1024 * 1024
+		cpuShares = 5000
+		resources = &specs.WindowsResources{
+			Memory: &specs.WindowsMemoryResources{Limit: &memoryLimit},
+			CPU:    &specs.WindowsCPUResources{Shares: &cpuShares},
+		}
+	})
+
+	It("modifies the memory and processor limits of the container", func() {
+		Expect(containerManager.Update(resources)).To(Succeed())
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(fakeContainer.ModifyCallCount()).To(Equal(2))
+
+		Expect(fakeContainer.ModifyArgsForCall(0)).To(Equal(&hcsshim.ResourceModificationRequestResponse{
+			Resource: container.MemoryResource,
+			Request:  container.UpdateRequest,
+			Data:     container.MemorySettings{SizeInMB: 512},
+		}))
+		Expect(fakeContainer.ModifyArgsForCall(1)).To(Equal(&hcsshim.ResourceModificationRequestResponse{
+			Resource: container.ProcessorResource,
+			Request:  container.UpdateRequest,
+			Data:     container.ProcessorSettings{Weight: 5000},
+		}))
+	})
+
+	Context("only a cpu maximum is provided", func() {
+		BeforeEach(func() {
+			cpuMaximum := uint16(2500)
+			resources = &specs.WindowsResources{
+				CPU: &specs.WindowsCPUResources{Maximum: &cpuMaximum},
+			}
+		})
+
+		It("only modifies the processor limit", func() {
+			Expect(containerManager.Update(resources)).To(Succeed())
+
+			Expect(fakeContainer.ModifyCallCount()).To(Equal(1))
+			Expect(fakeContainer.ModifyArgsForCall(0)).To(Equal(&hcsshim.ResourceModificationRequestResponse{
+				Resource: container.ProcessorResource,
+				Request:  container.UpdateRequest,
+				Data:     container.ProcessorSettings{Maximum: 2500},
+			}))
+		})
+	})
+
+	Context("opening the container fails", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("couldn't open"))
+		})
+
+		It("returns the error", func() {
+			Expect(containerManager.Update(resources)).To(MatchError("couldn't open"))
+		})
+	})
+
+	Context("modifying the container fails", func() {
+		BeforeEach(func() {
+			fakeContainer.ModifyReturnsOnCall(0, errors.New("couldn't modify"))
+		})
+
+		It("returns the error and does not apply the remaining limits", func() {
+			Expect(containerManager.Update(resources)).To(MatchError("updating Memory: couldn't modify"))
+			Expect(fakeContainer.ModifyCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -2,10 +2,12 @@ package runtime_test
 
 import (
 	"errors"
+	"path/filepath"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -25,6 +27,7 @@ var _ = Describe("Create", func() {
 		containerFactory *fakes.ContainerFactory
 		cm               *fakes.ContainerManager
 		processWrapper   *fakes.ProcessWrapper
+		hookRunner       *fakes.HookRunner
 		hcsQuery         *fakes.HCSQuery
 		r                *runtime.Runtime
 		spec             *specs.Spec
@@ -40,6 +43,7 @@ var _ = Describe("Create", func() {
 		containerFactory = &fakes.ContainerFactory{}
 		cm = &fakes.ContainerManager{}
 		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
 		process := specs.Process{
 			Env: []string{},
 		}
@@ -52,7 +56,7 @@ var _ = Describe("Create", func() {
 		cm.CredentialSpecFromFileReturns("", nil)
 
 		config := runtime.Config{}
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 	})
 
 	It("loads the spec, creates the container, and intializes the state", func() {
@@ -70,11 +74,14 @@ var _ = Describe("Create", func() {
 
 		Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
 
-		s, cs := cm.CreateArgsForCall(0)
+		s, cs, owner := cm.CreateArgsForCall(0)
 		Expect(s).To(Equal(spec))
 		Expect(cs).To(Equal(""))
+		Expect(owner).To(Equal("winc:" + filepath.Clean(rootDir)))
 
-		Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+		bp, initSpec := sm.InitializeArgsForCall(0)
+		Expect(bp).To(Equal(bundlePath))
+		Expect(initSpec).To(Equal(spec))
 	})
 
 	Context("when a non-empty credential spec env and filepath is provided", func() {
@@ -86,7 +93,7 @@ var _ = Describe("Create", func() {
 				CredhubEndpoint:        "http://somewhere",
 				CredhubCaCertificate:   "cert-value",
 			}
-			r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+			r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 
 			cm.CredentialSpecFromEnvStub = func(envs []string, endpoint string, clientId string, clientSecret string, caCert string) (string, error) {
 				Expect(clientId).To(Equal("hello"))
@@ -112,11 +119,13 @@ var _ = Describe("Create", func() {
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
 
-			s, cs := cm.CreateArgsForCall(0)
+			s, cs, _ := cm.CreateArgsForCall(0)
 			Expect(s).To(Equal(spec))
 			Expect(cs).To(Equal("credential-spec-contents"))
 
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			bp, initSpec := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(initSpec).To(Equal(spec))
 
 			Expect(cm.CredentialSpecFromFileCallCount()).To(Equal(0))
 			Expect(cm.CredentialSpecFromEnvCallCount()).To(Equal(1))
@@ -132,7 +141,7 @@ var _ = Describe("Create", func() {
 				CredhubEndpoint:        "http://somewhere",
 				CredhubCaCertificate:   "cert-value",
 			}
-			r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+			r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 
 			cm.CredentialSpecFromEnvStub = func(envs []string, endpoint string, clientId string, clientSecret string, caCert string) (string, error) {
 				Expect(clientId).To(Equal("hello"))
@@ -158,11 +167,13 @@ var _ = Describe("Create", func() {
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
 
-			s, cs := cm.CreateArgsForCall(0)
+			s, cs, _ := cm.CreateArgsForCall(0)
 			Expect(s).To(Equal(spec))
 			Expect(cs).To(Equal("credential-spec-contents"))
 
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			bp, initSpec := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(initSpec).To(Equal(spec))
 			Expect(cm.CredentialSpecFromEnvCallCount()).To(Equal(1))
 		})
 
@@ -182,7 +193,7 @@ var _ = Describe("Create", func() {
 		BeforeEach(func() {
 			credentialSpecPath = "/path/to/credential/spec"
 			config := runtime.Config{}
-			r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+			r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 
 			cm.CredentialSpecFromFileStub = func(path string) (string, error) {
 				Expect(path).To(Equal(credentialSpecPath))
@@ -206,11 +217,13 @@ var _ = Describe("Create", func() {
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
 
-			s, cs := cm.CreateArgsForCall(0)
+			s, cs, _ := cm.CreateArgsForCall(0)
 			Expect(s).To(Equal(spec))
 			Expect(cs).To(Equal("credential-spec-contents"))
 
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			bp, initSpec := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(initSpec).To(Equal(spec))
 		})
 
 		Context("loading the credential spec fails", func() {
@@ -225,6 +238,29 @@ var _ = Describe("Create", func() {
 		})
 	})
 
+	It("holds the container's lock while creating it", func() {
+		sm.InitializeStub = func(string, *specs.Spec) error {
+			Expect(sm.LockCallCount()).To(Equal(1))
+			Expect(sm.UnlockCallCount()).To(Equal(0))
+			return nil
+		}
+
+		Expect(r.Create(containerId, bundlePath)).To(Succeed())
+		Expect(sm.UnlockCallCount()).To(Equal(1))
+	})
+
+	Context("another command holds the container's lock", func() {
+		BeforeEach(func() {
+			sm.LockReturns(&state.BusyError{Id: containerId})
+		})
+
+		It("returns the error without creating the container", func() {
+			err := r.Create(containerId, bundlePath)
+			Expect(err).To(BeAssignableToTypeOf(&state.BusyError{}))
+			Expect(cm.CreateCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("loading the spec fails", func() {
 		BeforeEach(func() {
 			cm.SpecReturns(nil, errors.New("bad spec"))
@@ -236,6 +272,19 @@ var _ = Describe("Create", func() {
 		})
 	})
 
+	Context("the process has a tty", func() {
+		BeforeEach(func() {
+			spec.Process.Terminal = true
+		})
+
+		It("returns an error without creating the container", func() {
+			err := r.Create(containerId, bundlePath)
+			Expect(err).To(MatchError("cannot allocate a tty for a container that is created without a console socket, use run instead"))
+			Expect(cm.CreateCallCount()).To(Equal(0))
+			Expect(sm.InitializeCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("creating the container fails", func() {
 		BeforeEach(func() {
 			cm.CreateReturns(errors.New("hcsshim fell over"))
@@ -257,8 +306,56 @@ var _ = Describe("Create", func() {
 			Expect(err).To(MatchError("state init failed"))
 
 			Expect(cm.DeleteCallCount()).To(Equal(1))
-			force := cm.DeleteArgsForCall(0)
+			force, _ := cm.DeleteArgsForCall(0)
 			Expect(force).To(Equal(false))
 		})
 	})
+
+	Context("the spec has prestart and createRuntime hooks", func() {
+		BeforeEach(func() {
+			spec.Annotations = map[string]string{"some-key": "some-value"}
+			spec.Hooks = &specs.Hooks{
+				Prestart:      []specs.Hook{{Path: "C:\\prestart.exe"}},
+				CreateRuntime: []specs.Hook{{Path: "C:\\create-runtime.exe"}},
+				Poststart:     []specs.Hook{{Path: "C:\\poststart.exe"}},
+			}
+		})
+
+		It("runs them after the container is created", func() {
+			Expect(r.Create(containerId, bundlePath)).To(Succeed())
+
+			Expect(hookRunner.RunCallCount()).To(Equal(1))
+			hooks, state, _ := hookRunner.RunArgsForCall(0)
+			Expect(hooks).To(Equal([]specs.Hook{{Path: "C:\\prestart.exe"}, {Path: "C:\\create-runtime.exe"}}))
+			Expect(state).To(Equal(&specs.State{
+				Version:     specs.Version,
+				ID:          containerId,
+				Status:      specs.StateCreating,
+				Bundle:      bundlePath,
+				Annotations: map[string]string{"some-key": "some-value"},
+			}))
+		})
+
+		Context("a hook fails", func() {
+			BeforeEach(func() {
+				hookRunner.RunReturns(errors.New("hook failed"))
+			})
+
+			It("deletes the state and the container and returns the error", func() {
+				err := r.Create(containerId, bundlePath)
+				Expect(err).To(MatchError("running createRuntime hooks: hook failed"))
+
+				Expect(sm.DeleteCallCount()).To(Equal(1))
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeFalse())
+			})
+		})
+	})
+
+	Context("the spec has no hooks", func() {
+		It("does not run any hooks", func() {
+			Expect(r.Create(containerId, bundlePath)).To(Succeed())
+			Expect(hookRunner.RunCallCount()).To(Equal(0))
+		})
+	})
 })
@@ -2,6 +2,7 @@ package runtime_test
 
 import (
 	"strings"
+	"time"
 
 	"github.com/Microsoft/hcsshim"
 	"github.com/pkg/errors"
@@ -9,6 +10,7 @@ import (
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -29,6 +31,7 @@ var _ = Describe("Delete", func() {
 		containerFactory   *fakes.ContainerFactory
 		cm                 *fakes.ContainerManager
 		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
 		hcsQuery           *fakes.HCSQuery
 		credentialSpecPath string
 		r                  *runtime.Runtime
@@ -42,12 +45,13 @@ var _ = Describe("Delete", func() {
 		containerFactory = &fakes.ContainerFactory{}
 		cm = &fakes.ContainerManager{}
 		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
 
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
 		config := runtime.Config{}
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 	})
 
 	BeforeEach(func() {
@@ -56,7 +60,7 @@ var _ = Describe("Delete", func() {
 	})
 
 	It("unmounts the volume, deletes the state and deletes the container", func() {
-		Expect(r.Delete(containerId, true)).To(Succeed())
+		Expect(r.Delete(containerId, true, 0, false)).To(Succeed())
 
 		_, c, id := containerFactory.NewManagerArgsForCall(0)
 		Expect(*c).To(Equal(hcs.Client{}))
@@ -68,9 +72,157 @@ var _ = Describe("Delete", func() {
 		Expect(id).To(Equal(containerId))
 		Expect(rd).To(Equal(rootDir))
 
-		Expect(mounter.UnmountArgsForCall(0)).To(Equal(99))
+		id, pid := mounter.UnmountArgsForCall(0)
+		Expect(id).To(Equal(containerId))
+		Expect(pid).To(Equal(99))
 		Expect(sm.DeleteCallCount()).To(Equal(1))
-		Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+		force, _ := cm.DeleteArgsForCall(0)
+		Expect(force).To(BeTrue())
+	})
+
+	Context("the spec has poststop hooks", func() {
+		var annotations map[string]string
+
+		BeforeEach(func() {
+			cm.SpecReturns(&specs.Spec{
+				Annotations: map[string]string{"some-key": "some-value"},
+				Hooks: &specs.Hooks{
+					Poststop: []specs.Hook{{Path: "C:\\poststop.exe"}},
+				},
+			}, nil)
+
+			annotations = map[string]string{
+				"some-key":                  "some-value",
+				state.IsolationAnnotation:   state.IsolationProcess,
+				state.EndpointIdAnnotation:  "some-endpoint-id",
+				state.HCSSystemIdAnnotation: containerId,
+			}
+			sm.StateReturns(&specs.State{Status: specs.StateRunning, Bundle: bundlePath, Pid: 99, Annotations: annotations}, nil)
+		})
+
+		It("runs them with the stopped state, including the winc annotations, after deleting the container", func() {
+			Expect(r.Delete(containerId, true, 0, false)).To(Succeed())
+
+			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
+			hooks, stoppedState, _ := hookRunner.RunAllArgsForCall(0)
+			Expect(hooks).To(Equal([]specs.Hook{{Path: "C:\\poststop.exe"}}))
+			Expect(stoppedState).To(Equal(&specs.State{Status: specs.StateStopped, Bundle: bundlePath, Pid: 99, Annotations: annotations}))
+			Expect(sm.OwnEndpointIdCallCount()).To(Equal(0))
+		})
+
+		Context("the bundle can no longer be loaded", func() {
+			BeforeEach(func() {
+				cm.SpecReturns(nil, errors.New("bundle does not exist"))
+			})
+
+			It("skips the hooks", func() {
+				Expect(r.Delete(containerId, true, 0, false)).To(Succeed())
+				Expect(hookRunner.RunAllCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("stopping the container", func() {
+		It("waits a minute for each phase by default", func() {
+			Expect(r.Delete(containerId, false, 0, false)).To(Succeed())
+
+			_, timeout := cm.DeleteArgsForCall(0)
+			Expect(timeout).To(Equal(time.Minute))
+		})
+
+		It("waits for the given timeout", func() {
+			Expect(r.Delete(containerId, false, 5*time.Second, false)).To(Succeed())
+
+			_, timeout := cm.DeleteArgsForCall(0)
+			Expect(timeout).To(Equal(5 * time.Second))
+		})
+
+		Context("the config file sets a stop timeout", func() {
+			BeforeEach(func() {
+				config := runtime.Config{StopTimeoutSeconds: 90}
+				r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+			})
+
+			It("waits for the configured timeout", func() {
+				Expect(r.Delete(containerId, false, 0, false)).To(Succeed())
+
+				_, timeout := cm.DeleteArgsForCall(0)
+				Expect(timeout).To(Equal(90 * time.Second))
+			})
+
+			It("prefers the given timeout", func() {
+				Expect(r.Delete(containerId, false, 5*time.Second, false)).To(Succeed())
+
+				_, timeout := cm.DeleteArgsForCall(0)
+				Expect(timeout).To(Equal(5 * time.Second))
+			})
+		})
+	})
+
+	Context("pre-stop is requested", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: specs.StateRunning, Bundle: bundlePath, Pid: 99}, nil)
+		})
+
+		It("signals the init process before unmounting the volume and stopping the container", func() {
+			cm.PreStopStub = func(int, time.Duration) error {
+				Expect(mounter.UnmountCallCount()).To(Equal(0))
+				Expect(cm.DeleteCallCount()).To(Equal(0))
+				return nil
+			}
+
+			Expect(r.Delete(containerId, false, 5*time.Second, true)).To(Succeed())
+
+			pid, timeout := cm.PreStopArgsForCall(0)
+			Expect(pid).To(Equal(99))
+			Expect(timeout).To(Equal(5 * time.Second))
+			Expect(mounter.UnmountCallCount()).To(Equal(1))
+			Expect(cm.DeleteCallCount()).To(Equal(1))
+		})
+
+		Context("the init process doesn't exit in time", func() {
+			BeforeEach(func() {
+				cm.PreStopReturns(errors.New("timed out"))
+			})
+
+			It("still deletes the container", func() {
+				Expect(r.Delete(containerId, false, 0, true)).To(Succeed())
+
+				Expect(mounter.UnmountCallCount()).To(Equal(1))
+				Expect(sm.DeleteCallCount()).To(Equal(1))
+				Expect(cm.DeleteCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("the container is not running", func() {
+			BeforeEach(func() {
+				sm.StateReturns(&specs.State{Status: specs.StateStopped, Bundle: bundlePath, Pid: 99}, nil)
+			})
+
+			It("skips pre-stop", func() {
+				Expect(r.Delete(containerId, false, 0, true)).To(Succeed())
+				Expect(cm.PreStopCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("by the config file", func() {
+			BeforeEach(func() {
+				config := runtime.Config{PreStop: true}
+				r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+			})
+
+			It("signals the init process", func() {
+				Expect(r.Delete(containerId, false, 0, false)).To(Succeed())
+				Expect(cm.PreStopCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	It("doesn't pre-stop unless requested", func() {
+		sm.StateReturns(&specs.State{Status: specs.StateRunning, Bundle: bundlePath, Pid: 99}, nil)
+
+		Expect(r.Delete(containerId, false, 0, false)).To(Succeed())
+		Expect(cm.PreStopCallCount()).To(Equal(0))
 	})
 
 	Context("getting state fails", func() {
@@ -81,7 +233,7 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("returns success", func() {
-					Expect(r.Delete(containerId, true)).To(Succeed())
+					Expect(r.Delete(containerId, true, 0, false)).To(Succeed())
 
 					Expect(mounter.UnmountCallCount()).To(Equal(0))
 					Expect(sm.DeleteCallCount()).To(Equal(0))
@@ -95,12 +247,15 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("returns the error", func() {
-					err := r.Delete(containerId, true)
+					err := r.Delete(containerId, true, 0, false)
 					Expect(err).To(MatchError("couldn't get state"))
 
-					Expect(mounter.UnmountCallCount()).To(Equal(0))
+					id, pid := mounter.UnmountArgsForCall(0)
+					Expect(id).To(Equal(containerId))
+					Expect(pid).To(Equal(0))
 					Expect(sm.DeleteCallCount()).To(Equal(1))
-					Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+					force, _ := cm.DeleteArgsForCall(0)
+					Expect(force).To(BeTrue())
 				})
 			})
 		})
@@ -112,7 +267,7 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("returns the error", func() {
-					err := r.Delete(containerId, false)
+					err := r.Delete(containerId, false, 0, false)
 					Expect(err).To(HaveOccurred())
 					errs := strings.Split(err.Error(), "\n")
 
@@ -130,28 +285,87 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("returns the error", func() {
-					err := r.Delete(containerId, false)
+					err := r.Delete(containerId, false, 0, false)
 					Expect(err).To(MatchError("couldn't get state"))
 
-					Expect(mounter.UnmountCallCount()).To(Equal(0))
+					id, pid := mounter.UnmountArgsForCall(0)
+					Expect(id).To(Equal(containerId))
+					Expect(pid).To(Equal(0))
 					Expect(sm.DeleteCallCount()).To(Equal(1))
-					Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+					force, _ := cm.DeleteArgsForCall(0)
+					Expect(force).To(BeFalse())
 				})
 			})
 		})
 	})
 
+	Context("another command holds the container's lock", func() {
+		BeforeEach(func() {
+			sm.LockReturns(&state.BusyError{Id: containerId})
+		})
+
+		It("returns the error without deleting anything", func() {
+			err := r.Delete(containerId, false, 0, false)
+			Expect(err).To(MatchError((&state.BusyError{Id: containerId}).Error()))
+
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+			Expect(sm.DeleteCallCount()).To(Equal(0))
+			Expect(cm.DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the state is corrupt", func() {
+		BeforeEach(func() {
+			sm.StateReturns(nil, &state.CorruptStateError{Id: containerId, Reason: "unexpected end of JSON input"})
+		})
+
+		It("still unmounts the volume, deletes the state and deletes the container", func() {
+			Expect(r.Delete(containerId, false, 0, false)).To(Succeed())
+
+			id, pid := mounter.UnmountArgsForCall(0)
+			Expect(id).To(Equal(containerId))
+			Expect(pid).To(Equal(0))
+			Expect(sm.DeleteCallCount()).To(Equal(1))
+			force, _ := cm.DeleteArgsForCall(0)
+			Expect(force).To(BeFalse())
+			Expect(cm.SpecCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("the state doesn't have a pid", func() {
 		BeforeEach(func() {
 			sm.StateReturns(&specs.State{}, nil)
 		})
 
-		It("deletes the state and deletes the container", func() {
-			Expect(r.Delete(containerId, true)).To(Succeed())
+		It("unmounts the volume by container id, deletes the state and deletes the container", func() {
+			Expect(r.Delete(containerId, true, 0, false)).To(Succeed())
+
+			id, pid := mounter.UnmountArgsForCall(0)
+			Expect(id).To(Equal(containerId))
+			Expect(pid).To(Equal(0))
+			Expect(sm.DeleteCallCount()).To(Equal(1))
+			force, _ := cm.DeleteArgsForCall(0)
+			Expect(force).To(BeTrue())
+		})
+	})
+
+	Context("the container is hyper-v isolated", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{
+				Status:      "stopped",
+				Bundle:      bundlePath,
+				Pid:         99,
+				Annotations: map[string]string{state.IsolationAnnotation: state.IsolationHyperV},
+			}, nil)
+		})
+
+		It("does not unmount the volume, as it was never mounted", func() {
+			Expect(r.Delete(containerId, true, 0, false)).To(Succeed())
 
 			Expect(mounter.UnmountCallCount()).To(Equal(0))
 			Expect(sm.DeleteCallCount()).To(Equal(1))
-			Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+			force, _ := cm.DeleteArgsForCall(0)
+			Expect(force).To(BeTrue())
 		})
 	})
 
@@ -161,12 +375,13 @@ var _ = Describe("Delete", func() {
 		})
 
 		It("deletes the state and deletes the container", func() {
-			err := r.Delete(containerId, true)
+			err := r.Delete(containerId, true, 0, false)
 			Expect(err).To(MatchError("couldn't unmount"))
 
 			Expect(mounter.UnmountCallCount()).To(Equal(1))
 			Expect(sm.DeleteCallCount()).To(Equal(1))
-			Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+			force, _ := cm.DeleteArgsForCall(0)
+			Expect(force).To(BeTrue())
 		})
 	})
 
@@ -176,12 +391,13 @@ var _ = Describe("Delete", func() {
 		})
 
 		It("deletes the container", func() {
-			err := r.Delete(containerId, true)
+			err := r.Delete(containerId, true, 0, false)
 			Expect(err).To(MatchError("couldn't delete state"))
 
 			Expect(mounter.UnmountCallCount()).To(Equal(1))
 			Expect(sm.DeleteCallCount()).To(Equal(1))
-			Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+			force, _ := cm.DeleteArgsForCall(0)
+			Expect(force).To(BeTrue())
 		})
 	})
 
@@ -191,12 +407,13 @@ var _ = Describe("Delete", func() {
 		})
 
 		It("returns an error", func() {
-			err := r.Delete(containerId, true)
+			err := r.Delete(containerId, true, 0, false)
 			Expect(err).To(MatchError("couldn't delete container"))
 
 			Expect(mounter.UnmountCallCount()).To(Equal(1))
 			Expect(sm.DeleteCallCount()).To(Equal(1))
-			Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+			force, _ := cm.DeleteArgsForCall(0)
+			Expect(force).To(BeTrue())
 		})
 	})
 
@@ -226,7 +443,7 @@ var _ = Describe("Delete", func() {
 			sidecarSm.StateReturns(sidecarState, nil)
 		})
 		It("deletes the sidecar container", func() {
-			Expect(r.Delete(containerId, true)).To(Succeed())
+			Expect(r.Delete(containerId, true, 0, false)).To(Succeed())
 
 			Expect(hcsQuery.GetContainersCallCount()).To(Equal(1))
 			query := hcsshim.ComputeSystemQuery{Owners: []string{containerId}}
@@ -241,23 +458,33 @@ var _ = Describe("Delete", func() {
 			_, _, cId = containerFactory.NewManagerArgsForCall(1)
 			Expect(cId).To(Equal(containerId))
 
-			Expect(mounter.UnmountArgsForCall(0)).To(Equal(sidecarPid))
+			id, pid := mounter.UnmountArgsForCall(0)
+			Expect(id).To(Equal(sidecarId))
+			Expect(pid).To(Equal(sidecarPid))
 			Expect(sidecarSm.DeleteCallCount()).To(Equal(1))
-			Expect(sidecarCm.DeleteArgsForCall(0)).To(BeTrue())
+			force, timeout := sidecarCm.DeleteArgsForCall(0)
+			Expect(force).To(BeTrue())
+			Expect(timeout).To(Equal(time.Minute))
 
-			Expect(mounter.UnmountArgsForCall(1)).To(Equal(99))
+			id, pid = mounter.UnmountArgsForCall(1)
+			Expect(id).To(Equal(containerId))
+			Expect(pid).To(Equal(99))
 			Expect(sm.DeleteCallCount()).To(Equal(1))
-			Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+			force, timeout = cm.DeleteArgsForCall(0)
+			Expect(force).To(BeTrue())
+			Expect(timeout).To(Equal(time.Minute))
 		})
 		Context("when we fail to delete the sidecar container", func() {
 			BeforeEach(func() {
 				sidecarCm.DeleteReturnsOnCall(0, errors.New("some-sidecar-delete-error"))
 			})
 			It("continues to delete the main container", func() {
-				Expect(r.Delete(containerId, true)).NotTo(Succeed())
-				Expect(mounter.UnmountArgsForCall(1)).To(Equal(99))
+				Expect(r.Delete(containerId, true, 0, false)).NotTo(Succeed())
+				id, _ := mounter.UnmountArgsForCall(1)
+				Expect(id).To(Equal(containerId))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
-				Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeTrue())
 			})
 		})
 		Context("when we fail to unmount the sidecar container", func() {
@@ -265,10 +492,12 @@ var _ = Describe("Delete", func() {
 				mounter.UnmountReturnsOnCall(0, errors.New("some-sidecar-mount-error"))
 			})
 			It("continues to delete the main container", func() {
-				Expect(r.Delete(containerId, true)).NotTo(Succeed())
-				Expect(mounter.UnmountArgsForCall(1)).To(Equal(99))
+				Expect(r.Delete(containerId, true, 0, false)).NotTo(Succeed())
+				id, _ := mounter.UnmountArgsForCall(1)
+				Expect(id).To(Equal(containerId))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
-				Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeTrue())
 			})
 		})
 	})
@@ -1,15 +1,20 @@
 package runtime_test
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 var _ = Describe("Events", func() {
@@ -25,6 +30,7 @@ var _ = Describe("Events", func() {
 		containerFactory   *fakes.ContainerFactory
 		cm                 *fakes.ContainerManager
 		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
 		hcsQuery           *fakes.HCSQuery
 		credentialSpecPath string
 		r                  *runtime.Runtime
@@ -39,6 +45,7 @@ var _ = Describe("Events", func() {
 		containerFactory = &fakes.ContainerFactory{}
 		cm = &fakes.ContainerManager{}
 		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
 
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
@@ -46,7 +53,7 @@ var _ = Describe("Events", func() {
 		output = gbytes.NewBuffer()
 		config := runtime.Config{}
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 	})
 
 	Context("show stats is true", func() {
@@ -64,13 +71,14 @@ var _ = Describe("Events", func() {
     "memory": {
       "raw": {}
     },
-    "pids": {}
+    "pids": {},
+    "blkio": {}
   }
 }`
 		})
 
 		It("writes the stats to the output", func() {
-			Expect(r.Events(containerId, output, true)).To(Succeed())
+			Expect(r.Events(containerId, output, true, 0)).To(Succeed())
 			Expect(string(output.Contents())).To(Equal(expectedJSON))
 
 			_, c, id := containerFactory.NewManagerArgsForCall(0)
@@ -78,19 +86,224 @@ var _ = Describe("Events", func() {
 			Expect(id).To(Equal(containerId))
 		})
 
+		Context("the memory limit has been updated", func() {
+			BeforeEach(func() {
+				limit := uint64(1024 * 1024 * 1024)
+				sm.LoadReturns(state.State{
+					Resources: &specs.WindowsResources{
+						Memory: &specs.WindowsMemoryResources{Limit: &limit},
+					},
+				}, nil)
+			})
+
+			It("reports the limit with the memory stats", func() {
+				Expect(r.Events(containerId, output, true, 0)).To(Succeed())
+				Expect(string(output.Contents())).To(ContainSubstring(`"limit": 1073741824`))
+			})
+		})
+
+		Context("the spec configures memory and pid limits", func() {
+			BeforeEach(func() {
+				limit := uint64(512 * 1024 * 1024)
+				sm.LoadReturns(state.State{Bundle: bundlePath}, nil)
+				cm.SpecReturns(&specs.Spec{
+					Windows: &specs.Windows{
+						Resources: &specs.WindowsResources{
+							Memory: &specs.WindowsMemoryResources{Limit: &limit},
+						},
+					},
+					Linux: &specs.Linux{
+						Resources: &specs.LinuxResources{
+							Pids: &specs.LinuxPids{Limit: 1024},
+						},
+					},
+				}, nil)
+			})
+
+			It("reports the configured limits", func() {
+				Expect(r.Events(containerId, output, true, 0)).To(Succeed())
+				Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
+
+				var stats container.Statistics
+				Expect(json.Unmarshal(output.Contents(), &stats)).To(Succeed())
+				Expect(stats.Data.Memory.Limit).To(Equal(uint64(512 * 1024 * 1024)))
+				Expect(stats.Data.Pids.Limit).To(Equal(uint64(1024)))
+			})
+		})
+
 		Context("events is passed a nil io.Writer", func() {
 			It("returns an error", func() {
-				err := r.Events(containerId, nil, true)
+				err := r.Events(containerId, nil, true, 0)
 				Expect(err).To(MatchError("provided output is nil"))
 			})
 		})
 	})
 
 	Context("show stats is false", func() {
-		It("calls cm.Stats but doesn't write anything", func() {
-			Expect(r.Events(containerId, output, false)).To(Succeed())
-			Expect(string(output.Contents())).To(Equal(""))
-			Expect(cm.StatsCallCount()).To(Equal(1))
+		var memoryLimit uint64
+
+		BeforeEach(func() {
+			memoryLimit = 100 * 1024 * 1024
+			cm.SpecReturns(&specs.Spec{
+				Windows: &specs.Windows{
+					Resources: &specs.WindowsResources{
+						Memory: &specs.WindowsMemoryResources{Limit: &memoryLimit},
+					},
+				},
+			}, nil)
+
+			sm.StateReturnsOnCall(0, &specs.State{Status: specs.StateRunning, Bundle: bundlePath}, nil)
+			sm.StateReturnsOnCall(1, &specs.State{Status: specs.StateRunning, Bundle: bundlePath}, nil)
+			sm.StateReturnsOnCall(2, &specs.State{Status: specs.StateStopped, Bundle: bundlePath}, nil)
+			sm.LoadReturns(state.State{Bundle: bundlePath}, nil)
+			sm.ExitCodeReturns(3, nil)
+
+			stats := container.Statistics{}
+			stats.Data.Memory.Raw.TotalRss = 1024
+			stats.Data.Memory.Usage = 1024
+			cm.StatsReturns(stats, nil)
+		})
+
+		It("streams the stats every interval until the container exits", func() {
+			Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+
+			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
+			Expect(cm.StatsCallCount()).To(Equal(2))
+
+			lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(Equal(`{"type":"stats","id":"container-for-stats","data":{"cpu":{"usage":{"total":0,"kernel":0,"user":0}},"memory":{"raw":{"total_rss":1024},"usage":1024,"limit":104857600},"pids":{},"blkio":{}}}`))
+			Expect(lines[1]).To(Equal(lines[0]))
+			Expect(lines[2]).To(Equal(`{"type":"exit","id":"container-for-stats","data":{"status":3}}`))
+		})
+
+		Context("the container is already stopped", func() {
+			BeforeEach(func() {
+				sm.StateReturnsOnCall(0, &specs.State{Status: specs.StateStopped}, nil)
+			})
+
+			It("only writes the exit event", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+				Expect(cm.StatsCallCount()).To(Equal(0))
+				Expect(string(output.Contents())).To(Equal(`{"type":"exit","id":"container-for-stats","data":{"status":3}}` + "\n"))
+			})
+		})
+
+		Context("the exit code is unavailable", func() {
+			BeforeEach(func() {
+				sm.ExitCodeReturns(0, errors.New("init process is no longer available"))
+			})
+
+			It("writes the exit event without a status", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+				Expect(string(output.Contents())).To(HaveSuffix(`{"type":"exit","id":"container-for-stats"}` + "\n"))
+			})
+		})
+
+		Context("the container is deleted", func() {
+			BeforeEach(func() {
+				sm.StateReturnsOnCall(1, nil, &hcs.NotFoundError{Id: containerId})
+			})
+
+			It("writes the exit event and stops streaming", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+				Expect(cm.StatsCallCount()).To(Equal(1))
+				Expect(string(output.Contents())).To(HaveSuffix(`{"type":"exit","id":"container-for-stats","data":{"status":3}}` + "\n"))
+			})
+		})
+
+		Context("the memory commit reaches the limit", func() {
+			BeforeEach(func() {
+				stats := container.Statistics{}
+				stats.Data.Memory.Raw.TotalRss = memoryLimit
+				stats.Data.Memory.Usage = memoryLimit
+				cm.StatsReturns(stats, nil)
+			})
+
+			It("writes a single oom event while the container stays at the limit", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+
+				lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+				Expect(lines).To(HaveLen(4))
+				Expect(lines[0]).To(HavePrefix(`{"type":"stats"`))
+				Expect(lines[1]).To(Equal(`{"type":"oom","id":"container-for-stats"}`))
+				Expect(lines[2]).To(HavePrefix(`{"type":"stats"`))
+				Expect(lines[3]).To(HavePrefix(`{"type":"exit"`))
+			})
+		})
+
+		Context("the limit has been updated", func() {
+			BeforeEach(func() {
+				updatedLimit := uint64(200 * 1024 * 1024)
+				sm.LoadReturns(state.State{
+					Bundle: bundlePath,
+					Resources: &specs.WindowsResources{
+						Memory: &specs.WindowsMemoryResources{Limit: &updatedLimit},
+					},
+				}, nil)
+			})
+
+			It("reports the updated limit", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+				Expect(string(output.Contents())).To(ContainSubstring(`"limit":209715200`))
+			})
+		})
+
+		Context("hcs reports the container is low on memory", func() {
+			BeforeEach(func() {
+				cm.StatsReturnsOnCall(0, container.Statistics{}, &hcs.LowMemoryError{})
+			})
+
+			It("writes an oom event and keeps streaming", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+
+				lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+				Expect(lines).To(HaveLen(3))
+				Expect(lines[0]).To(Equal(`{"type":"oom","id":"container-for-stats"}`))
+				Expect(lines[1]).To(HavePrefix(`{"type":"stats"`))
+			})
+
+			Context("it stays low on memory", func() {
+				BeforeEach(func() {
+					sm.StateReturnsOnCall(2, &specs.State{Status: specs.StateRunning, Bundle: bundlePath}, nil)
+					sm.StateReturnsOnCall(3, &specs.State{Status: specs.StateStopped, Bundle: bundlePath}, nil)
+					cm.StatsReturnsOnCall(1, container.Statistics{}, &hcs.LowMemoryError{})
+				})
+
+				It("writes a single oom event", func() {
+					Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+					Expect(cm.StatsCallCount()).To(Equal(3))
+
+					lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+					Expect(lines).To(HaveLen(3))
+					Expect(lines[0]).To(Equal(`{"type":"oom","id":"container-for-stats"}`))
+					Expect(lines[1]).To(HavePrefix(`{"type":"stats"`))
+					Expect(lines[2]).To(HavePrefix(`{"type":"exit"`))
+				})
+			})
+		})
+
+		Context("getting stats fails", func() {
+			BeforeEach(func() {
+				cm.StatsReturns(container.Statistics{}, errors.New("stats failed"))
+			})
+
+			It("returns the error", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(MatchError("stats failed"))
+			})
+		})
+
+		Context("the interval is not positive", func() {
+			It("returns an error", func() {
+				Expect(r.Events(containerId, output, false, 0)).To(MatchError("invalid interval: 0s"))
+				Expect(cm.StatsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("events is passed a nil io.Writer", func() {
+			It("returns an error", func() {
+				Expect(r.Events(containerId, nil, false, time.Millisecond)).To(MatchError("provided output is nil"))
+			})
 		})
 	})
 
@@ -100,7 +313,7 @@ var _ = Describe("Events", func() {
 		})
 
 		It("returns an error", func() {
-			err := r.Events(containerId, nil, true)
+			err := r.Events(containerId, nil, true, 0)
 			Expect(err).To(MatchError("stats failed"))
 		})
 	})
@@ -3,6 +3,7 @@ package runtime_test
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -30,6 +31,7 @@ var _ = Describe("Exec", func() {
 		containerFactory   *fakes.ContainerFactory
 		cm                 *fakes.ContainerManager
 		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
 		wrappedProcess     *fakes.WrappedProcess
 		unwrappedProcess   *hcsfakes.Process
 		hcsQuery           *fakes.HCSQuery
@@ -51,6 +53,7 @@ var _ = Describe("Exec", func() {
 		containerFactory = &fakes.ContainerFactory{}
 		cm = &fakes.ContainerManager{}
 		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
 		wrappedProcess = &fakes.WrappedProcess{}
 
 		stateFactory.NewManagerReturns(sm)
@@ -62,7 +65,7 @@ var _ = Describe("Exec", func() {
 		processSpecFile = filepath.Join(processSpecDir, "process.json")
 
 		config := runtime.Config{}
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 
 		processSpec := specs.Process{
 			User: specs.User{Username: "some-user"},
@@ -228,6 +231,79 @@ var _ = Describe("Exec", func() {
 		})
 	})
 
+	Context("the process has a terminal", func() {
+		var console *fakes.Console
+
+		BeforeEach(func() {
+			cm.ExecReturns(unwrappedProcess, nil)
+			processWrapper.WrapReturns(wrappedProcess)
+
+			console = &fakes.Console{}
+			console.SizeReturns(120, 40, nil)
+			io.Console = console
+		})
+
+		It("sizes the console to match the caller's and restores the caller's console afterwards", func() {
+			_, err := r.Exec(containerId, processSpecFile, pidFile, &specs.Process{Terminal: true}, io, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			spec, _ := cm.ExecArgsForCall(0)
+			Expect(spec.Terminal).To(BeTrue())
+			Expect(spec.ConsoleSize).To(Equal(&specs.Box{Width: 120, Height: 40}))
+
+			Expect(console.SetRawCallCount()).To(Equal(1))
+			Expect(console.ResetCallCount()).To(Equal(1))
+
+			width, height := unwrappedProcess.ResizeConsoleArgsForCall(0)
+			Expect(width).To(Equal(uint16(120)))
+			Expect(height).To(Equal(uint16(40)))
+		})
+
+		It("resizes the process's console when the caller's console is resized", func() {
+			console.SizeReturnsOnCall(2, 100, 30, nil)
+
+			resized := make(chan struct{})
+			unwrappedProcess.ResizeConsoleStub = func(width, height uint16) error {
+				if width == 100 {
+					close(resized)
+				}
+				return nil
+			}
+			wrappedProcess.AttachIOStub = attachUntil(resized)
+
+			_, err := r.Exec(containerId, processSpecFile, pidFile, &specs.Process{Terminal: true}, io, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			width, height := unwrappedProcess.ResizeConsoleArgsForCall(1)
+			Expect(width).To(Equal(uint16(100)))
+			Expect(height).To(Equal(uint16(30)))
+		})
+
+		Context("there is no console", func() {
+			BeforeEach(func() {
+				io.Console = nil
+			})
+
+			It("leaves the console size to HCS", func() {
+				_, err := r.Exec(containerId, processSpecFile, pidFile, &specs.Process{Terminal: true}, io, false)
+				Expect(err).NotTo(HaveOccurred())
+
+				spec, _ := cm.ExecArgsForCall(0)
+				Expect(spec.ConsoleSize).To(BeNil())
+				Expect(unwrappedProcess.ResizeConsoleCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("detach is true", func() {
+			It("returns an error", func() {
+				exitCode, err := r.Exec(containerId, processSpecFile, pidFile, &specs.Process{Terminal: true}, io, true)
+				Expect(err).To(MatchError("cannot allocate a tty for a detached process"))
+				Expect(exitCode).To(Equal(1))
+				Expect(cm.ExecCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Context("exec fails", func() {
 		BeforeEach(func() {
 			cm.ExecReturns(nil, errors.New("couldn't exec"))
@@ -256,3 +332,11 @@ var _ = Describe("Exec", func() {
 		})
 	})
 })
+
+// attachUntil returns an AttachIO stub that stays attached until done is closed
+func attachUntil(done <-chan struct{}) func(io.Reader, io.Writer, io.Writer) (int, error) {
+	return func(io.Reader, io.Writer, io.Writer) (int, error) {
+		<-done
+		return 0, nil
+	}
+}
@@ -0,0 +1,242 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/runtime"
+)
+
+type Console struct {
+	ResetStub        func() error
+	resetMutex       sync.RWMutex
+	resetArgsForCall []struct {
+	}
+	resetReturns struct {
+		result1 error
+	}
+	resetReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetRawStub        func() error
+	setRawMutex       sync.RWMutex
+	setRawArgsForCall []struct {
+	}
+	setRawReturns struct {
+		result1 error
+	}
+	setRawReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SizeStub        func() (uint16, uint16, error)
+	sizeMutex       sync.RWMutex
+	sizeArgsForCall []struct {
+	}
+	sizeReturns struct {
+		result1 uint16
+		result2 uint16
+		result3 error
+	}
+	sizeReturnsOnCall map[int]struct {
+		result1 uint16
+		result2 uint16
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Console) Reset() error {
+	fake.resetMutex.Lock()
+	ret, specificReturn := fake.resetReturnsOnCall[len(fake.resetArgsForCall)]
+	fake.resetArgsForCall = append(fake.resetArgsForCall, struct {
+	}{})
+	stub := fake.ResetStub
+	fakeReturns := fake.resetReturns
+	fake.recordInvocation("Reset", []interface{}{})
+	fake.resetMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Console) ResetCallCount() int {
+	fake.resetMutex.RLock()
+	defer fake.resetMutex.RUnlock()
+	return len(fake.resetArgsForCall)
+}
+
+func (fake *Console) ResetCalls(stub func() error) {
+	fake.resetMutex.Lock()
+	defer fake.resetMutex.Unlock()
+	fake.ResetStub = stub
+}
+
+func (fake *Console) ResetReturns(result1 error) {
+	fake.resetMutex.Lock()
+	defer fake.resetMutex.Unlock()
+	fake.ResetStub = nil
+	fake.resetReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Console) ResetReturnsOnCall(i int, result1 error) {
+	fake.resetMutex.Lock()
+	defer fake.resetMutex.Unlock()
+	fake.ResetStub = nil
+	if fake.resetReturnsOnCall == nil {
+		fake.resetReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resetReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Console) SetRaw() error {
+	fake.setRawMutex.Lock()
+	ret, specificReturn := fake.setRawReturnsOnCall[len(fake.setRawArgsForCall)]
+	fake.setRawArgsForCall = append(fake.setRawArgsForCall, struct {
+	}{})
+	stub := fake.SetRawStub
+	fakeReturns := fake.setRawReturns
+	fake.recordInvocation("SetRaw", []interface{}{})
+	fake.setRawMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Console) SetRawCallCount() int {
+	fake.setRawMutex.RLock()
+	defer fake.setRawMutex.RUnlock()
+	return len(fake.setRawArgsForCall)
+}
+
+func (fake *Console) SetRawCalls(stub func() error) {
+	fake.setRawMutex.Lock()
+	defer fake.setRawMutex.Unlock()
+	fake.SetRawStub = stub
+}
+
+func (fake *Console) SetRawReturns(result1 error) {
+	fake.setRawMutex.Lock()
+	defer fake.setRawMutex.Unlock()
+	fake.SetRawStub = nil
+	fake.setRawReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Console) SetRawReturnsOnCall(i int, result1 error) {
+	fake.setRawMutex.Lock()
+	defer fake.setRawMutex.Unlock()
+	fake.SetRawStub = nil
+	if fake.setRawReturnsOnCall == nil {
+		fake.setRawReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setRawReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Console) Size() (uint16, uint16, error) {
+	fake.sizeMutex.Lock()
+	ret, specificReturn := fake.sizeReturnsOnCall[len(fake.sizeArgsForCall)]
+	fake.sizeArgsForCall = append(fake.sizeArgsForCall, struct {
+	}{})
+	stub := fake.SizeStub
+	fakeReturns := fake.sizeReturns
+	fake.recordInvocation("Size", []interface{}{})
+	fake.sizeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *Console) SizeCallCount() int {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	return len(fake.sizeArgsForCall)
+}
+
+func (fake *Console) SizeCalls(stub func() (uint16, uint16, error)) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = stub
+}
+
+func (fake *Console) SizeReturns(result1 uint16, result2 uint16, result3 error) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	fake.sizeReturns = struct {
+		result1 uint16
+		result2 uint16
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *Console) SizeReturnsOnCall(i int, result1 uint16, result2 uint16, result3 error) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	if fake.sizeReturnsOnCall == nil {
+		fake.sizeReturnsOnCall = make(map[int]struct {
+			result1 uint16
+			result2 uint16
+			result3 error
+		})
+	}
+	fake.sizeReturnsOnCall[i] = struct {
+		result1 uint16
+		result2 uint16
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *Console) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.resetMutex.RLock()
+	defer fake.resetMutex.RUnlock()
+	fake.setRawMutex.RLock()
+	defer fake.setRawMutex.RUnlock()
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Console) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ runtime.Console = new(Console)
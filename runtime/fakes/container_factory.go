@@ -10,6 +10,18 @@ import (
 )
 
 type ContainerFactory struct {
+	FileMountsStub        func() ([]string, error)
+	fileMountsMutex       sync.RWMutex
+	fileMountsArgsForCall []struct {
+	}
+	fileMountsReturns struct {
+		result1 []string
+		result2 error
+	}
+	fileMountsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	NewManagerStub        func(*logrus.Entry, *hcs.Client, string) runtime.ContainerManager
 	newManagerMutex       sync.RWMutex
 	newManagerArgsForCall []struct {
@@ -27,6 +39,62 @@ type ContainerFactory struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ContainerFactory) FileMounts() ([]string, error) {
+	fake.fileMountsMutex.Lock()
+	ret, specificReturn := fake.fileMountsReturnsOnCall[len(fake.fileMountsArgsForCall)]
+	fake.fileMountsArgsForCall = append(fake.fileMountsArgsForCall, struct {
+	}{})
+	stub := fake.FileMountsStub
+	fakeReturns := fake.fileMountsReturns
+	fake.recordInvocation("FileMounts", []interface{}{})
+	fake.fileMountsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ContainerFactory) FileMountsCallCount() int {
+	fake.fileMountsMutex.RLock()
+	defer fake.fileMountsMutex.RUnlock()
+	return len(fake.fileMountsArgsForCall)
+}
+
+func (fake *ContainerFactory) FileMountsCalls(stub func() ([]string, error)) {
+	fake.fileMountsMutex.Lock()
+	defer fake.fileMountsMutex.Unlock()
+	fake.FileMountsStub = stub
+}
+
+func (fake *ContainerFactory) FileMountsReturns(result1 []string, result2 error) {
+	fake.fileMountsMutex.Lock()
+	defer fake.fileMountsMutex.Unlock()
+	fake.FileMountsStub = nil
+	fake.fileMountsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerFactory) FileMountsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.fileMountsMutex.Lock()
+	defer fake.fileMountsMutex.Unlock()
+	fake.FileMountsStub = nil
+	if fake.fileMountsReturnsOnCall == nil {
+		fake.fileMountsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.fileMountsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ContainerFactory) NewManager(arg1 *logrus.Entry, arg2 *hcs.Client, arg3 string) runtime.ContainerManager {
 	fake.newManagerMutex.Lock()
 	ret, specificReturn := fake.newManagerReturnsOnCall[len(fake.newManagerArgsForCall)]
@@ -93,6 +161,8 @@ func (fake *ContainerFactory) NewManagerReturnsOnCall(i int, result1 runtime.Con
 func (fake *ContainerFactory) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.fileMountsMutex.RLock()
+	defer fake.fileMountsMutex.RUnlock()
 	fake.newManagerMutex.RLock()
 	defer fake.newManagerMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -3,6 +3,8 @@ package fakes
 
 import (
 	"sync"
+	"syscall"
+	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
@@ -11,11 +13,12 @@ import (
 )
 
 type ContainerManager struct {
-	CreateStub        func(*specs.Spec, string) error
+	CreateStub        func(*specs.Spec, string, string) error
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
 		arg1 *specs.Spec
 		arg2 string
+		arg3 string
 	}
 	createReturns struct {
 		result1 error
@@ -53,10 +56,11 @@ type ContainerManager struct {
 		result1 string
 		result2 error
 	}
-	DeleteStub        func(bool) error
+	DeleteStub        func(bool, time.Duration) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
 		arg1 bool
+		arg2 time.Duration
 	}
 	deleteReturns struct {
 		result1 error
@@ -78,6 +82,86 @@ type ContainerManager struct {
 		result1 hcs.Process
 		result2 error
 	}
+	KillStub        func(syscall.Signal, bool) error
+	killMutex       sync.RWMutex
+	killArgsForCall []struct {
+		arg1 syscall.Signal
+		arg2 bool
+	}
+	killReturns struct {
+		result1 error
+	}
+	killReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PauseStub        func() error
+	pauseMutex       sync.RWMutex
+	pauseArgsForCall []struct {
+	}
+	pauseReturns struct {
+		result1 error
+	}
+	pauseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PreStopStub        func(int, time.Duration) error
+	preStopMutex       sync.RWMutex
+	preStopArgsForCall []struct {
+		arg1 int
+		arg2 time.Duration
+	}
+	preStopReturns struct {
+		result1 error
+	}
+	preStopReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ProcessesStub        func(bool) ([]container.ProcessInfo, error)
+	processesMutex       sync.RWMutex
+	processesArgsForCall []struct {
+		arg1 bool
+	}
+	processesReturns struct {
+		result1 []container.ProcessInfo
+		result2 error
+	}
+	processesReturnsOnCall map[int]struct {
+		result1 []container.ProcessInfo
+		result2 error
+	}
+	RemoveFileMountsStub        func() error
+	removeFileMountsMutex       sync.RWMutex
+	removeFileMountsArgsForCall []struct {
+	}
+	removeFileMountsReturns struct {
+		result1 error
+	}
+	removeFileMountsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResizeConsoleStub        func(int, uint16, uint16) error
+	resizeConsoleMutex       sync.RWMutex
+	resizeConsoleArgsForCall []struct {
+		arg1 int
+		arg2 uint16
+		arg3 uint16
+	}
+	resizeConsoleReturns struct {
+		result1 error
+	}
+	resizeConsoleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResumeStub        func() error
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct {
+	}
+	resumeReturns struct {
+		result1 error
+	}
+	resumeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SpecStub        func(string) (*specs.Spec, error)
 	specMutex       sync.RWMutex
 	specArgsForCall []struct {
@@ -103,23 +187,35 @@ type ContainerManager struct {
 		result1 container.Statistics
 		result2 error
 	}
+	UpdateStub        func(*specs.WindowsResources) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 *specs.WindowsResources
+	}
+	updateReturns struct {
+		result1 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *ContainerManager) Create(arg1 *specs.Spec, arg2 string) error {
+func (fake *ContainerManager) Create(arg1 *specs.Spec, arg2 string, arg3 string) error {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
 		arg1 *specs.Spec
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.CreateStub
 	fakeReturns := fake.createReturns
-	fake.recordInvocation("Create", []interface{}{arg1, arg2})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3})
 	fake.createMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -133,17 +229,17 @@ func (fake *ContainerManager) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *ContainerManager) CreateCalls(stub func(*specs.Spec, string) error) {
+func (fake *ContainerManager) CreateCalls(stub func(*specs.Spec, string, string) error) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = stub
 }
 
-func (fake *ContainerManager) CreateArgsForCall(i int) (*specs.Spec, string) {
+func (fake *ContainerManager) CreateArgsForCall(i int) (*specs.Spec, string, string) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	argsForCall := fake.createArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *ContainerManager) CreateReturns(result1 error) {
@@ -306,18 +402,19 @@ func (fake *ContainerManager) CredentialSpecFromFileReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *ContainerManager) Delete(arg1 bool) error {
+func (fake *ContainerManager) Delete(arg1 bool, arg2 time.Duration) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
 		arg1 bool
-	}{arg1})
+		arg2 time.Duration
+	}{arg1, arg2})
 	stub := fake.DeleteStub
 	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
 	fake.deleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -331,17 +428,17 @@ func (fake *ContainerManager) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
-func (fake *ContainerManager) DeleteCalls(stub func(bool) error) {
+func (fake *ContainerManager) DeleteCalls(stub func(bool, time.Duration) error) {
 	fake.deleteMutex.Lock()
 	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = stub
 }
 
-func (fake *ContainerManager) DeleteArgsForCall(i int) bool {
+func (fake *ContainerManager) DeleteArgsForCall(i int) (bool, time.Duration) {
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *ContainerManager) DeleteReturns(result1 error) {
@@ -432,6 +529,416 @@ func (fake *ContainerManager) ExecReturnsOnCall(i int, result1 hcs.Process, resu
 	}{result1, result2}
 }
 
+func (fake *ContainerManager) Kill(arg1 syscall.Signal, arg2 bool) error {
+	fake.killMutex.Lock()
+	ret, specificReturn := fake.killReturnsOnCall[len(fake.killArgsForCall)]
+	fake.killArgsForCall = append(fake.killArgsForCall, struct {
+		arg1 syscall.Signal
+		arg2 bool
+	}{arg1, arg2})
+	stub := fake.KillStub
+	fakeReturns := fake.killReturns
+	fake.recordInvocation("Kill", []interface{}{arg1, arg2})
+	fake.killMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ContainerManager) KillCallCount() int {
+	fake.killMutex.RLock()
+	defer fake.killMutex.RUnlock()
+	return len(fake.killArgsForCall)
+}
+
+func (fake *ContainerManager) KillCalls(stub func(syscall.Signal, bool) error) {
+	fake.killMutex.Lock()
+	defer fake.killMutex.Unlock()
+	fake.KillStub = stub
+}
+
+func (fake *ContainerManager) KillArgsForCall(i int) (syscall.Signal, bool) {
+	fake.killMutex.RLock()
+	defer fake.killMutex.RUnlock()
+	argsForCall := fake.killArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ContainerManager) KillReturns(result1 error) {
+	fake.killMutex.Lock()
+	defer fake.killMutex.Unlock()
+	fake.KillStub = nil
+	fake.killReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) KillReturnsOnCall(i int, result1 error) {
+	fake.killMutex.Lock()
+	defer fake.killMutex.Unlock()
+	fake.KillStub = nil
+	if fake.killReturnsOnCall == nil {
+		fake.killReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.killReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) Pause() error {
+	fake.pauseMutex.Lock()
+	ret, specificReturn := fake.pauseReturnsOnCall[len(fake.pauseArgsForCall)]
+	fake.pauseArgsForCall = append(fake.pauseArgsForCall, struct {
+	}{})
+	stub := fake.PauseStub
+	fakeReturns := fake.pauseReturns
+	fake.recordInvocation("Pause", []interface{}{})
+	fake.pauseMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ContainerManager) PauseCallCount() int {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return len(fake.pauseArgsForCall)
+}
+
+func (fake *ContainerManager) PauseCalls(stub func() error) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = stub
+}
+
+func (fake *ContainerManager) PauseReturns(result1 error) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = nil
+	fake.pauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) PauseReturnsOnCall(i int, result1 error) {
+	fake.pauseMutex.Lock()
+	defer fake.pauseMutex.Unlock()
+	fake.PauseStub = nil
+	if fake.pauseReturnsOnCall == nil {
+		fake.pauseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pauseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) PreStop(arg1 int, arg2 time.Duration) error {
+	fake.preStopMutex.Lock()
+	ret, specificReturn := fake.preStopReturnsOnCall[len(fake.preStopArgsForCall)]
+	fake.preStopArgsForCall = append(fake.preStopArgsForCall, struct {
+		arg1 int
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.PreStopStub
+	fakeReturns := fake.preStopReturns
+	fake.recordInvocation("PreStop", []interface{}{arg1, arg2})
+	fake.preStopMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ContainerManager) PreStopCallCount() int {
+	fake.preStopMutex.RLock()
+	defer fake.preStopMutex.RUnlock()
+	return len(fake.preStopArgsForCall)
+}
+
+func (fake *ContainerManager) PreStopCalls(stub func(int, time.Duration) error) {
+	fake.preStopMutex.Lock()
+	defer fake.preStopMutex.Unlock()
+	fake.PreStopStub = stub
+}
+
+func (fake *ContainerManager) PreStopArgsForCall(i int) (int, time.Duration) {
+	fake.preStopMutex.RLock()
+	defer fake.preStopMutex.RUnlock()
+	argsForCall := fake.preStopArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ContainerManager) PreStopReturns(result1 error) {
+	fake.preStopMutex.Lock()
+	defer fake.preStopMutex.Unlock()
+	fake.PreStopStub = nil
+	fake.preStopReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) PreStopReturnsOnCall(i int, result1 error) {
+	fake.preStopMutex.Lock()
+	defer fake.preStopMutex.Unlock()
+	fake.PreStopStub = nil
+	if fake.preStopReturnsOnCall == nil {
+		fake.preStopReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.preStopReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) Processes(arg1 bool) ([]container.ProcessInfo, error) {
+	fake.processesMutex.Lock()
+	ret, specificReturn := fake.processesReturnsOnCall[len(fake.processesArgsForCall)]
+	fake.processesArgsForCall = append(fake.processesArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.ProcessesStub
+	fakeReturns := fake.processesReturns
+	fake.recordInvocation("Processes", []interface{}{arg1})
+	fake.processesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ContainerManager) ProcessesCallCount() int {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return len(fake.processesArgsForCall)
+}
+
+func (fake *ContainerManager) ProcessesCalls(stub func(bool) ([]container.ProcessInfo, error)) {
+	fake.processesMutex.Lock()
+	defer fake.processesMutex.Unlock()
+	fake.ProcessesStub = stub
+}
+
+func (fake *ContainerManager) ProcessesArgsForCall(i int) bool {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	argsForCall := fake.processesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ContainerManager) ProcessesReturns(result1 []container.ProcessInfo, result2 error) {
+	fake.processesMutex.Lock()
+	defer fake.processesMutex.Unlock()
+	fake.ProcessesStub = nil
+	fake.processesReturns = struct {
+		result1 []container.ProcessInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) ProcessesReturnsOnCall(i int, result1 []container.ProcessInfo, result2 error) {
+	fake.processesMutex.Lock()
+	defer fake.processesMutex.Unlock()
+	fake.ProcessesStub = nil
+	if fake.processesReturnsOnCall == nil {
+		fake.processesReturnsOnCall = make(map[int]struct {
+			result1 []container.ProcessInfo
+			result2 error
+		})
+	}
+	fake.processesReturnsOnCall[i] = struct {
+		result1 []container.ProcessInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) RemoveFileMounts() error {
+	fake.removeFileMountsMutex.Lock()
+	ret, specificReturn := fake.removeFileMountsReturnsOnCall[len(fake.removeFileMountsArgsForCall)]
+	fake.removeFileMountsArgsForCall = append(fake.removeFileMountsArgsForCall, struct {
+	}{})
+	stub := fake.RemoveFileMountsStub
+	fakeReturns := fake.removeFileMountsReturns
+	fake.recordInvocation("RemoveFileMounts", []interface{}{})
+	fake.removeFileMountsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ContainerManager) RemoveFileMountsCallCount() int {
+	fake.removeFileMountsMutex.RLock()
+	defer fake.removeFileMountsMutex.RUnlock()
+	return len(fake.removeFileMountsArgsForCall)
+}
+
+func (fake *ContainerManager) RemoveFileMountsCalls(stub func() error) {
+	fake.removeFileMountsMutex.Lock()
+	defer fake.removeFileMountsMutex.Unlock()
+	fake.RemoveFileMountsStub = stub
+}
+
+func (fake *ContainerManager) RemoveFileMountsReturns(result1 error) {
+	fake.removeFileMountsMutex.Lock()
+	defer fake.removeFileMountsMutex.Unlock()
+	fake.RemoveFileMountsStub = nil
+	fake.removeFileMountsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) RemoveFileMountsReturnsOnCall(i int, result1 error) {
+	fake.removeFileMountsMutex.Lock()
+	defer fake.removeFileMountsMutex.Unlock()
+	fake.RemoveFileMountsStub = nil
+	if fake.removeFileMountsReturnsOnCall == nil {
+		fake.removeFileMountsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeFileMountsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) ResizeConsole(arg1 int, arg2 uint16, arg3 uint16) error {
+	fake.resizeConsoleMutex.Lock()
+	ret, specificReturn := fake.resizeConsoleReturnsOnCall[len(fake.resizeConsoleArgsForCall)]
+	fake.resizeConsoleArgsForCall = append(fake.resizeConsoleArgsForCall, struct {
+		arg1 int
+		arg2 uint16
+		arg3 uint16
+	}{arg1, arg2, arg3})
+	stub := fake.ResizeConsoleStub
+	fakeReturns := fake.resizeConsoleReturns
+	fake.recordInvocation("ResizeConsole", []interface{}{arg1, arg2, arg3})
+	fake.resizeConsoleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ContainerManager) ResizeConsoleCallCount() int {
+	fake.resizeConsoleMutex.RLock()
+	defer fake.resizeConsoleMutex.RUnlock()
+	return len(fake.resizeConsoleArgsForCall)
+}
+
+func (fake *ContainerManager) ResizeConsoleCalls(stub func(int, uint16, uint16) error) {
+	fake.resizeConsoleMutex.Lock()
+	defer fake.resizeConsoleMutex.Unlock()
+	fake.ResizeConsoleStub = stub
+}
+
+func (fake *ContainerManager) ResizeConsoleArgsForCall(i int) (int, uint16, uint16) {
+	fake.resizeConsoleMutex.RLock()
+	defer fake.resizeConsoleMutex.RUnlock()
+	argsForCall := fake.resizeConsoleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ContainerManager) ResizeConsoleReturns(result1 error) {
+	fake.resizeConsoleMutex.Lock()
+	defer fake.resizeConsoleMutex.Unlock()
+	fake.ResizeConsoleStub = nil
+	fake.resizeConsoleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) ResizeConsoleReturnsOnCall(i int, result1 error) {
+	fake.resizeConsoleMutex.Lock()
+	defer fake.resizeConsoleMutex.Unlock()
+	fake.ResizeConsoleStub = nil
+	if fake.resizeConsoleReturnsOnCall == nil {
+		fake.resizeConsoleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resizeConsoleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) Resume() error {
+	fake.resumeMutex.Lock()
+	ret, specificReturn := fake.resumeReturnsOnCall[len(fake.resumeArgsForCall)]
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct {
+	}{})
+	stub := fake.ResumeStub
+	fakeReturns := fake.resumeReturns
+	fake.recordInvocation("Resume", []interface{}{})
+	fake.resumeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ContainerManager) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *ContainerManager) ResumeCalls(stub func() error) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = stub
+}
+
+func (fake *ContainerManager) ResumeReturns(result1 error) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) ResumeReturnsOnCall(i int, result1 error) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = nil
+	if fake.resumeReturnsOnCall == nil {
+		fake.resumeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resumeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ContainerManager) Spec(arg1 string) (*specs.Spec, error) {
 	fake.specMutex.Lock()
 	ret, specificReturn := fake.specReturnsOnCall[len(fake.specArgsForCall)]
@@ -552,6 +1059,67 @@ func (fake *ContainerManager) StatsReturnsOnCall(i int, result1 container.Statis
 	}{result1, result2}
 }
 
+func (fake *ContainerManager) Update(arg1 *specs.WindowsResources) error {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 *specs.WindowsResources
+	}{arg1})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ContainerManager) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *ContainerManager) UpdateCalls(stub func(*specs.WindowsResources) error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *ContainerManager) UpdateArgsForCall(i int) *specs.WindowsResources {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ContainerManager) UpdateReturns(result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) UpdateReturnsOnCall(i int, result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ContainerManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -565,10 +1133,26 @@ func (fake *ContainerManager) Invocations() map[string][][]interface{} {
 	defer fake.deleteMutex.RUnlock()
 	fake.execMutex.RLock()
 	defer fake.execMutex.RUnlock()
+	fake.killMutex.RLock()
+	defer fake.killMutex.RUnlock()
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	fake.preStopMutex.RLock()
+	defer fake.preStopMutex.RUnlock()
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	fake.removeFileMountsMutex.RLock()
+	defer fake.removeFileMountsMutex.RUnlock()
+	fake.resizeConsoleMutex.RLock()
+	defer fake.resizeConsoleMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
 	fake.specMutex.RLock()
 	defer fake.specMutex.RUnlock()
 	fake.statsMutex.RLock()
 	defer fake.statsMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,170 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/runtime"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+type HookRunner struct {
+	RunStub        func([]specs.Hook, *specs.State, *logrus.Entry) error
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		arg1 []specs.Hook
+		arg2 *specs.State
+		arg3 *logrus.Entry
+	}
+	runReturns struct {
+		result1 error
+	}
+	runReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RunAllStub        func([]specs.Hook, *specs.State, *logrus.Entry)
+	runAllMutex       sync.RWMutex
+	runAllArgsForCall []struct {
+		arg1 []specs.Hook
+		arg2 *specs.State
+		arg3 *logrus.Entry
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *HookRunner) Run(arg1 []specs.Hook, arg2 *specs.State, arg3 *logrus.Entry) error {
+	var arg1Copy []specs.Hook
+	if arg1 != nil {
+		arg1Copy = make([]specs.Hook, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.runMutex.Lock()
+	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		arg1 []specs.Hook
+		arg2 *specs.State
+		arg3 *logrus.Entry
+	}{arg1Copy, arg2, arg3})
+	stub := fake.RunStub
+	fakeReturns := fake.runReturns
+	fake.recordInvocation("Run", []interface{}{arg1Copy, arg2, arg3})
+	fake.runMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *HookRunner) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *HookRunner) RunCalls(stub func([]specs.Hook, *specs.State, *logrus.Entry) error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = stub
+}
+
+func (fake *HookRunner) RunArgsForCall(i int) ([]specs.Hook, *specs.State, *logrus.Entry) {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	argsForCall := fake.runArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HookRunner) RunReturns(result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HookRunner) RunReturnsOnCall(i int, result1 error) {
+	fake.runMutex.Lock()
+	defer fake.runMutex.Unlock()
+	fake.RunStub = nil
+	if fake.runReturnsOnCall == nil {
+		fake.runReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.runReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HookRunner) RunAll(arg1 []specs.Hook, arg2 *specs.State, arg3 *logrus.Entry) {
+	var arg1Copy []specs.Hook
+	if arg1 != nil {
+		arg1Copy = make([]specs.Hook, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.runAllMutex.Lock()
+	fake.runAllArgsForCall = append(fake.runAllArgsForCall, struct {
+		arg1 []specs.Hook
+		arg2 *specs.State
+		arg3 *logrus.Entry
+	}{arg1Copy, arg2, arg3})
+	stub := fake.RunAllStub
+	fake.recordInvocation("RunAll", []interface{}{arg1Copy, arg2, arg3})
+	fake.runAllMutex.Unlock()
+	if stub != nil {
+		fake.RunAllStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *HookRunner) RunAllCallCount() int {
+	fake.runAllMutex.RLock()
+	defer fake.runAllMutex.RUnlock()
+	return len(fake.runAllArgsForCall)
+}
+
+func (fake *HookRunner) RunAllCalls(stub func([]specs.Hook, *specs.State, *logrus.Entry)) {
+	fake.runAllMutex.Lock()
+	defer fake.runAllMutex.Unlock()
+	fake.RunAllStub = stub
+}
+
+func (fake *HookRunner) RunAllArgsForCall(i int) ([]specs.Hook, *specs.State, *logrus.Entry) {
+	fake.runAllMutex.RLock()
+	defer fake.runAllMutex.RUnlock()
+	argsForCall := fake.runAllArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HookRunner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	fake.runAllMutex.RLock()
+	defer fake.runAllMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *HookRunner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ runtime.HookRunner = new(HookRunner)
@@ -9,12 +9,25 @@ import (
 )
 
 type Mounter struct {
-	MountStub        func(int, string, *logrus.Entry) error
+	LegacyMountsStub        func() ([]int, error)
+	legacyMountsMutex       sync.RWMutex
+	legacyMountsArgsForCall []struct {
+	}
+	legacyMountsReturns struct {
+		result1 []int
+		result2 error
+	}
+	legacyMountsReturnsOnCall map[int]struct {
+		result1 []int
+		result2 error
+	}
+	MountStub        func(string, int, string, *logrus.Entry) error
 	mountMutex       sync.RWMutex
 	mountArgsForCall []struct {
-		arg1 int
-		arg2 string
-		arg3 *logrus.Entry
+		arg1 string
+		arg2 int
+		arg3 string
+		arg4 *logrus.Entry
 	}
 	mountReturns struct {
 		result1 error
@@ -22,10 +35,23 @@ type Mounter struct {
 	mountReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UnmountStub        func(int) error
+	MountsStub        func() ([]string, error)
+	mountsMutex       sync.RWMutex
+	mountsArgsForCall []struct {
+	}
+	mountsReturns struct {
+		result1 []string
+		result2 error
+	}
+	mountsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	UnmountStub        func(string, int) error
 	unmountMutex       sync.RWMutex
 	unmountArgsForCall []struct {
-		arg1 int
+		arg1 string
+		arg2 int
 	}
 	unmountReturns struct {
 		result1 error
@@ -37,20 +63,77 @@ type Mounter struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *Mounter) Mount(arg1 int, arg2 string, arg3 *logrus.Entry) error {
+func (fake *Mounter) LegacyMounts() ([]int, error) {
+	fake.legacyMountsMutex.Lock()
+	ret, specificReturn := fake.legacyMountsReturnsOnCall[len(fake.legacyMountsArgsForCall)]
+	fake.legacyMountsArgsForCall = append(fake.legacyMountsArgsForCall, struct {
+	}{})
+	stub := fake.LegacyMountsStub
+	fakeReturns := fake.legacyMountsReturns
+	fake.recordInvocation("LegacyMounts", []interface{}{})
+	fake.legacyMountsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Mounter) LegacyMountsCallCount() int {
+	fake.legacyMountsMutex.RLock()
+	defer fake.legacyMountsMutex.RUnlock()
+	return len(fake.legacyMountsArgsForCall)
+}
+
+func (fake *Mounter) LegacyMountsCalls(stub func() ([]int, error)) {
+	fake.legacyMountsMutex.Lock()
+	defer fake.legacyMountsMutex.Unlock()
+	fake.LegacyMountsStub = stub
+}
+
+func (fake *Mounter) LegacyMountsReturns(result1 []int, result2 error) {
+	fake.legacyMountsMutex.Lock()
+	defer fake.legacyMountsMutex.Unlock()
+	fake.LegacyMountsStub = nil
+	fake.legacyMountsReturns = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mounter) LegacyMountsReturnsOnCall(i int, result1 []int, result2 error) {
+	fake.legacyMountsMutex.Lock()
+	defer fake.legacyMountsMutex.Unlock()
+	fake.LegacyMountsStub = nil
+	if fake.legacyMountsReturnsOnCall == nil {
+		fake.legacyMountsReturnsOnCall = make(map[int]struct {
+			result1 []int
+			result2 error
+		})
+	}
+	fake.legacyMountsReturnsOnCall[i] = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mounter) Mount(arg1 string, arg2 int, arg3 string, arg4 *logrus.Entry) error {
 	fake.mountMutex.Lock()
 	ret, specificReturn := fake.mountReturnsOnCall[len(fake.mountArgsForCall)]
 	fake.mountArgsForCall = append(fake.mountArgsForCall, struct {
-		arg1 int
-		arg2 string
-		arg3 *logrus.Entry
-	}{arg1, arg2, arg3})
+		arg1 string
+		arg2 int
+		arg3 string
+		arg4 *logrus.Entry
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.MountStub
 	fakeReturns := fake.mountReturns
-	fake.recordInvocation("Mount", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("Mount", []interface{}{arg1, arg2, arg3, arg4})
 	fake.mountMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -64,17 +147,17 @@ func (fake *Mounter) MountCallCount() int {
 	return len(fake.mountArgsForCall)
 }
 
-func (fake *Mounter) MountCalls(stub func(int, string, *logrus.Entry) error) {
+func (fake *Mounter) MountCalls(stub func(string, int, string, *logrus.Entry) error) {
 	fake.mountMutex.Lock()
 	defer fake.mountMutex.Unlock()
 	fake.MountStub = stub
 }
 
-func (fake *Mounter) MountArgsForCall(i int) (int, string, *logrus.Entry) {
+func (fake *Mounter) MountArgsForCall(i int) (string, int, string, *logrus.Entry) {
 	fake.mountMutex.RLock()
 	defer fake.mountMutex.RUnlock()
 	argsForCall := fake.mountArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *Mounter) MountReturns(result1 error) {
@@ -100,18 +183,75 @@ func (fake *Mounter) MountReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *Mounter) Unmount(arg1 int) error {
+func (fake *Mounter) Mounts() ([]string, error) {
+	fake.mountsMutex.Lock()
+	ret, specificReturn := fake.mountsReturnsOnCall[len(fake.mountsArgsForCall)]
+	fake.mountsArgsForCall = append(fake.mountsArgsForCall, struct {
+	}{})
+	stub := fake.MountsStub
+	fakeReturns := fake.mountsReturns
+	fake.recordInvocation("Mounts", []interface{}{})
+	fake.mountsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Mounter) MountsCallCount() int {
+	fake.mountsMutex.RLock()
+	defer fake.mountsMutex.RUnlock()
+	return len(fake.mountsArgsForCall)
+}
+
+func (fake *Mounter) MountsCalls(stub func() ([]string, error)) {
+	fake.mountsMutex.Lock()
+	defer fake.mountsMutex.Unlock()
+	fake.MountsStub = stub
+}
+
+func (fake *Mounter) MountsReturns(result1 []string, result2 error) {
+	fake.mountsMutex.Lock()
+	defer fake.mountsMutex.Unlock()
+	fake.MountsStub = nil
+	fake.mountsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mounter) MountsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.mountsMutex.Lock()
+	defer fake.mountsMutex.Unlock()
+	fake.MountsStub = nil
+	if fake.mountsReturnsOnCall == nil {
+		fake.mountsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.mountsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mounter) Unmount(arg1 string, arg2 int) error {
 	fake.unmountMutex.Lock()
 	ret, specificReturn := fake.unmountReturnsOnCall[len(fake.unmountArgsForCall)]
 	fake.unmountArgsForCall = append(fake.unmountArgsForCall, struct {
-		arg1 int
-	}{arg1})
+		arg1 string
+		arg2 int
+	}{arg1, arg2})
 	stub := fake.UnmountStub
 	fakeReturns := fake.unmountReturns
-	fake.recordInvocation("Unmount", []interface{}{arg1})
+	fake.recordInvocation("Unmount", []interface{}{arg1, arg2})
 	fake.unmountMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -125,17 +265,17 @@ func (fake *Mounter) UnmountCallCount() int {
 	return len(fake.unmountArgsForCall)
 }
 
-func (fake *Mounter) UnmountCalls(stub func(int) error) {
+func (fake *Mounter) UnmountCalls(stub func(string, int) error) {
 	fake.unmountMutex.Lock()
 	defer fake.unmountMutex.Unlock()
 	fake.UnmountStub = stub
 }
 
-func (fake *Mounter) UnmountArgsForCall(i int) int {
+func (fake *Mounter) UnmountArgsForCall(i int) (string, int) {
 	fake.unmountMutex.RLock()
 	defer fake.unmountMutex.RUnlock()
 	argsForCall := fake.unmountArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *Mounter) UnmountReturns(result1 error) {
@@ -164,8 +304,12 @@ func (fake *Mounter) UnmountReturnsOnCall(i int, result1 error) {
 func (fake *Mounter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.legacyMountsMutex.RLock()
+	defer fake.legacyMountsMutex.RUnlock()
 	fake.mountMutex.RLock()
 	defer fake.mountMutex.RUnlock()
+	fake.mountsMutex.RLock()
+	defer fake.mountsMutex.RUnlock()
 	fake.unmountMutex.RLock()
 	defer fake.unmountMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
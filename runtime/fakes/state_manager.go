@@ -3,9 +3,11 @@ package fakes
 
 import (
 	"sync"
+	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/state"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
@@ -20,10 +22,23 @@ type StateManager struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	InitializeStub        func(string) error
+	ExitCodeStub        func() (int, error)
+	exitCodeMutex       sync.RWMutex
+	exitCodeArgsForCall []struct {
+	}
+	exitCodeReturns struct {
+		result1 int
+		result2 error
+	}
+	exitCodeReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	InitializeStub        func(string, *specs.Spec) error
 	initializeMutex       sync.RWMutex
 	initializeArgsForCall []struct {
 		arg1 string
+		arg2 *specs.Spec
 	}
 	initializeReturns struct {
 		result1 error
@@ -31,6 +46,41 @@ type StateManager struct {
 	initializeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	LoadStub        func() (state.State, error)
+	loadMutex       sync.RWMutex
+	loadArgsForCall []struct {
+	}
+	loadReturns struct {
+		result1 state.State
+		result2 error
+	}
+	loadReturnsOnCall map[int]struct {
+		result1 state.State
+		result2 error
+	}
+	LockStub        func(time.Duration) error
+	lockMutex       sync.RWMutex
+	lockArgsForCall []struct {
+		arg1 time.Duration
+	}
+	lockReturns struct {
+		result1 error
+	}
+	lockReturnsOnCall map[int]struct {
+		result1 error
+	}
+	OwnEndpointIdStub        func() (string, error)
+	ownEndpointIdMutex       sync.RWMutex
+	ownEndpointIdArgsForCall []struct {
+	}
+	ownEndpointIdReturns struct {
+		result1 string
+		result2 error
+	}
+	ownEndpointIdReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	SetFailureStub        func() error
 	setFailureMutex       sync.RWMutex
 	setFailureArgsForCall []struct {
@@ -41,6 +91,38 @@ type StateManager struct {
 	setFailureReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetPausedStub        func(bool) error
+	setPausedMutex       sync.RWMutex
+	setPausedArgsForCall []struct {
+		arg1 bool
+	}
+	setPausedReturns struct {
+		result1 error
+	}
+	setPausedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetResourcesStub        func(*specs.WindowsResources) error
+	setResourcesMutex       sync.RWMutex
+	setResourcesArgsForCall []struct {
+		arg1 *specs.WindowsResources
+	}
+	setResourcesReturns struct {
+		result1 error
+	}
+	setResourcesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetStoppedStub        func() error
+	setStoppedMutex       sync.RWMutex
+	setStoppedArgsForCall []struct {
+	}
+	setStoppedReturns struct {
+		result1 error
+	}
+	setStoppedReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetSuccessStub        func(hcs.Process) error
 	setSuccessMutex       sync.RWMutex
 	setSuccessArgsForCall []struct {
@@ -64,6 +146,16 @@ type StateManager struct {
 		result1 *specs.State
 		result2 error
 	}
+	UnlockStub        func() error
+	unlockMutex       sync.RWMutex
+	unlockArgsForCall []struct {
+	}
+	unlockReturns struct {
+		result1 error
+	}
+	unlockReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -121,18 +213,75 @@ func (fake *StateManager) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *StateManager) Initialize(arg1 string) error {
+func (fake *StateManager) ExitCode() (int, error) {
+	fake.exitCodeMutex.Lock()
+	ret, specificReturn := fake.exitCodeReturnsOnCall[len(fake.exitCodeArgsForCall)]
+	fake.exitCodeArgsForCall = append(fake.exitCodeArgsForCall, struct {
+	}{})
+	stub := fake.ExitCodeStub
+	fakeReturns := fake.exitCodeReturns
+	fake.recordInvocation("ExitCode", []interface{}{})
+	fake.exitCodeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StateManager) ExitCodeCallCount() int {
+	fake.exitCodeMutex.RLock()
+	defer fake.exitCodeMutex.RUnlock()
+	return len(fake.exitCodeArgsForCall)
+}
+
+func (fake *StateManager) ExitCodeCalls(stub func() (int, error)) {
+	fake.exitCodeMutex.Lock()
+	defer fake.exitCodeMutex.Unlock()
+	fake.ExitCodeStub = stub
+}
+
+func (fake *StateManager) ExitCodeReturns(result1 int, result2 error) {
+	fake.exitCodeMutex.Lock()
+	defer fake.exitCodeMutex.Unlock()
+	fake.ExitCodeStub = nil
+	fake.exitCodeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) ExitCodeReturnsOnCall(i int, result1 int, result2 error) {
+	fake.exitCodeMutex.Lock()
+	defer fake.exitCodeMutex.Unlock()
+	fake.ExitCodeStub = nil
+	if fake.exitCodeReturnsOnCall == nil {
+		fake.exitCodeReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.exitCodeReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) Initialize(arg1 string, arg2 *specs.Spec) error {
 	fake.initializeMutex.Lock()
 	ret, specificReturn := fake.initializeReturnsOnCall[len(fake.initializeArgsForCall)]
 	fake.initializeArgsForCall = append(fake.initializeArgsForCall, struct {
 		arg1 string
-	}{arg1})
+		arg2 *specs.Spec
+	}{arg1, arg2})
 	stub := fake.InitializeStub
 	fakeReturns := fake.initializeReturns
-	fake.recordInvocation("Initialize", []interface{}{arg1})
+	fake.recordInvocation("Initialize", []interface{}{arg1, arg2})
 	fake.initializeMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -146,17 +295,17 @@ func (fake *StateManager) InitializeCallCount() int {
 	return len(fake.initializeArgsForCall)
 }
 
-func (fake *StateManager) InitializeCalls(stub func(string) error) {
+func (fake *StateManager) InitializeCalls(stub func(string, *specs.Spec) error) {
 	fake.initializeMutex.Lock()
 	defer fake.initializeMutex.Unlock()
 	fake.InitializeStub = stub
 }
 
-func (fake *StateManager) InitializeArgsForCall(i int) string {
+func (fake *StateManager) InitializeArgsForCall(i int) (string, *specs.Spec) {
 	fake.initializeMutex.RLock()
 	defer fake.initializeMutex.RUnlock()
 	argsForCall := fake.initializeArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *StateManager) InitializeReturns(result1 error) {
@@ -182,6 +331,179 @@ func (fake *StateManager) InitializeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *StateManager) Load() (state.State, error) {
+	fake.loadMutex.Lock()
+	ret, specificReturn := fake.loadReturnsOnCall[len(fake.loadArgsForCall)]
+	fake.loadArgsForCall = append(fake.loadArgsForCall, struct {
+	}{})
+	stub := fake.LoadStub
+	fakeReturns := fake.loadReturns
+	fake.recordInvocation("Load", []interface{}{})
+	fake.loadMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StateManager) LoadCallCount() int {
+	fake.loadMutex.RLock()
+	defer fake.loadMutex.RUnlock()
+	return len(fake.loadArgsForCall)
+}
+
+func (fake *StateManager) LoadCalls(stub func() (state.State, error)) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = stub
+}
+
+func (fake *StateManager) LoadReturns(result1 state.State, result2 error) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = nil
+	fake.loadReturns = struct {
+		result1 state.State
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) LoadReturnsOnCall(i int, result1 state.State, result2 error) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = nil
+	if fake.loadReturnsOnCall == nil {
+		fake.loadReturnsOnCall = make(map[int]struct {
+			result1 state.State
+			result2 error
+		})
+	}
+	fake.loadReturnsOnCall[i] = struct {
+		result1 state.State
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) Lock(arg1 time.Duration) error {
+	fake.lockMutex.Lock()
+	ret, specificReturn := fake.lockReturnsOnCall[len(fake.lockArgsForCall)]
+	fake.lockArgsForCall = append(fake.lockArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	stub := fake.LockStub
+	fakeReturns := fake.lockReturns
+	fake.recordInvocation("Lock", []interface{}{arg1})
+	fake.lockMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *StateManager) LockCallCount() int {
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	return len(fake.lockArgsForCall)
+}
+
+func (fake *StateManager) LockCalls(stub func(time.Duration) error) {
+	fake.lockMutex.Lock()
+	defer fake.lockMutex.Unlock()
+	fake.LockStub = stub
+}
+
+func (fake *StateManager) LockArgsForCall(i int) time.Duration {
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	argsForCall := fake.lockArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *StateManager) LockReturns(result1 error) {
+	fake.lockMutex.Lock()
+	defer fake.lockMutex.Unlock()
+	fake.LockStub = nil
+	fake.lockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) LockReturnsOnCall(i int, result1 error) {
+	fake.lockMutex.Lock()
+	defer fake.lockMutex.Unlock()
+	fake.LockStub = nil
+	if fake.lockReturnsOnCall == nil {
+		fake.lockReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.lockReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) OwnEndpointId() (string, error) {
+	fake.ownEndpointIdMutex.Lock()
+	ret, specificReturn := fake.ownEndpointIdReturnsOnCall[len(fake.ownEndpointIdArgsForCall)]
+	fake.ownEndpointIdArgsForCall = append(fake.ownEndpointIdArgsForCall, struct {
+	}{})
+	stub := fake.OwnEndpointIdStub
+	fakeReturns := fake.ownEndpointIdReturns
+	fake.recordInvocation("OwnEndpointId", []interface{}{})
+	fake.ownEndpointIdMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StateManager) OwnEndpointIdCallCount() int {
+	fake.ownEndpointIdMutex.RLock()
+	defer fake.ownEndpointIdMutex.RUnlock()
+	return len(fake.ownEndpointIdArgsForCall)
+}
+
+func (fake *StateManager) OwnEndpointIdCalls(stub func() (string, error)) {
+	fake.ownEndpointIdMutex.Lock()
+	defer fake.ownEndpointIdMutex.Unlock()
+	fake.OwnEndpointIdStub = stub
+}
+
+func (fake *StateManager) OwnEndpointIdReturns(result1 string, result2 error) {
+	fake.ownEndpointIdMutex.Lock()
+	defer fake.ownEndpointIdMutex.Unlock()
+	fake.OwnEndpointIdStub = nil
+	fake.ownEndpointIdReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) OwnEndpointIdReturnsOnCall(i int, result1 string, result2 error) {
+	fake.ownEndpointIdMutex.Lock()
+	defer fake.ownEndpointIdMutex.Unlock()
+	fake.OwnEndpointIdStub = nil
+	if fake.ownEndpointIdReturnsOnCall == nil {
+		fake.ownEndpointIdReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.ownEndpointIdReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *StateManager) SetFailure() error {
 	fake.setFailureMutex.Lock()
 	ret, specificReturn := fake.setFailureReturnsOnCall[len(fake.setFailureArgsForCall)]
@@ -235,6 +557,181 @@ func (fake *StateManager) SetFailureReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *StateManager) SetPaused(arg1 bool) error {
+	fake.setPausedMutex.Lock()
+	ret, specificReturn := fake.setPausedReturnsOnCall[len(fake.setPausedArgsForCall)]
+	fake.setPausedArgsForCall = append(fake.setPausedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.SetPausedStub
+	fakeReturns := fake.setPausedReturns
+	fake.recordInvocation("SetPaused", []interface{}{arg1})
+	fake.setPausedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *StateManager) SetPausedCallCount() int {
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
+	return len(fake.setPausedArgsForCall)
+}
+
+func (fake *StateManager) SetPausedCalls(stub func(bool) error) {
+	fake.setPausedMutex.Lock()
+	defer fake.setPausedMutex.Unlock()
+	fake.SetPausedStub = stub
+}
+
+func (fake *StateManager) SetPausedArgsForCall(i int) bool {
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
+	argsForCall := fake.setPausedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *StateManager) SetPausedReturns(result1 error) {
+	fake.setPausedMutex.Lock()
+	defer fake.setPausedMutex.Unlock()
+	fake.SetPausedStub = nil
+	fake.setPausedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetPausedReturnsOnCall(i int, result1 error) {
+	fake.setPausedMutex.Lock()
+	defer fake.setPausedMutex.Unlock()
+	fake.SetPausedStub = nil
+	if fake.setPausedReturnsOnCall == nil {
+		fake.setPausedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setPausedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetResources(arg1 *specs.WindowsResources) error {
+	fake.setResourcesMutex.Lock()
+	ret, specificReturn := fake.setResourcesReturnsOnCall[len(fake.setResourcesArgsForCall)]
+	fake.setResourcesArgsForCall = append(fake.setResourcesArgsForCall, struct {
+		arg1 *specs.WindowsResources
+	}{arg1})
+	stub := fake.SetResourcesStub
+	fakeReturns := fake.setResourcesReturns
+	fake.recordInvocation("SetResources", []interface{}{arg1})
+	fake.setResourcesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *StateManager) SetResourcesCallCount() int {
+	fake.setResourcesMutex.RLock()
+	defer fake.setResourcesMutex.RUnlock()
+	return len(fake.setResourcesArgsForCall)
+}
+
+func (fake *StateManager) SetResourcesCalls(stub func(*specs.WindowsResources) error) {
+	fake.setResourcesMutex.Lock()
+	defer fake.setResourcesMutex.Unlock()
+	fake.SetResourcesStub = stub
+}
+
+func (fake *StateManager) SetResourcesArgsForCall(i int) *specs.WindowsResources {
+	fake.setResourcesMutex.RLock()
+	defer fake.setResourcesMutex.RUnlock()
+	argsForCall := fake.setResourcesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *StateManager) SetResourcesReturns(result1 error) {
+	fake.setResourcesMutex.Lock()
+	defer fake.setResourcesMutex.Unlock()
+	fake.SetResourcesStub = nil
+	fake.setResourcesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetResourcesReturnsOnCall(i int, result1 error) {
+	fake.setResourcesMutex.Lock()
+	defer fake.setResourcesMutex.Unlock()
+	fake.SetResourcesStub = nil
+	if fake.setResourcesReturnsOnCall == nil {
+		fake.setResourcesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setResourcesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetStopped() error {
+	fake.setStoppedMutex.Lock()
+	ret, specificReturn := fake.setStoppedReturnsOnCall[len(fake.setStoppedArgsForCall)]
+	fake.setStoppedArgsForCall = append(fake.setStoppedArgsForCall, struct {
+	}{})
+	stub := fake.SetStoppedStub
+	fakeReturns := fake.setStoppedReturns
+	fake.recordInvocation("SetStopped", []interface{}{})
+	fake.setStoppedMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *StateManager) SetStoppedCallCount() int {
+	fake.setStoppedMutex.RLock()
+	defer fake.setStoppedMutex.RUnlock()
+	return len(fake.setStoppedArgsForCall)
+}
+
+func (fake *StateManager) SetStoppedCalls(stub func() error) {
+	fake.setStoppedMutex.Lock()
+	defer fake.setStoppedMutex.Unlock()
+	fake.SetStoppedStub = stub
+}
+
+func (fake *StateManager) SetStoppedReturns(result1 error) {
+	fake.setStoppedMutex.Lock()
+	defer fake.setStoppedMutex.Unlock()
+	fake.SetStoppedStub = nil
+	fake.setStoppedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetStoppedReturnsOnCall(i int, result1 error) {
+	fake.setStoppedMutex.Lock()
+	defer fake.setStoppedMutex.Unlock()
+	fake.SetStoppedStub = nil
+	if fake.setStoppedReturnsOnCall == nil {
+		fake.setStoppedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setStoppedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *StateManager) SetSuccess(arg1 hcs.Process) error {
 	fake.setSuccessMutex.Lock()
 	ret, specificReturn := fake.setSuccessReturnsOnCall[len(fake.setSuccessArgsForCall)]
@@ -352,19 +849,88 @@ func (fake *StateManager) StateReturnsOnCall(i int, result1 *specs.State, result
 	}{result1, result2}
 }
 
+func (fake *StateManager) Unlock() error {
+	fake.unlockMutex.Lock()
+	ret, specificReturn := fake.unlockReturnsOnCall[len(fake.unlockArgsForCall)]
+	fake.unlockArgsForCall = append(fake.unlockArgsForCall, struct {
+	}{})
+	stub := fake.UnlockStub
+	fakeReturns := fake.unlockReturns
+	fake.recordInvocation("Unlock", []interface{}{})
+	fake.unlockMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *StateManager) UnlockCallCount() int {
+	fake.unlockMutex.RLock()
+	defer fake.unlockMutex.RUnlock()
+	return len(fake.unlockArgsForCall)
+}
+
+func (fake *StateManager) UnlockCalls(stub func() error) {
+	fake.unlockMutex.Lock()
+	defer fake.unlockMutex.Unlock()
+	fake.UnlockStub = stub
+}
+
+func (fake *StateManager) UnlockReturns(result1 error) {
+	fake.unlockMutex.Lock()
+	defer fake.unlockMutex.Unlock()
+	fake.UnlockStub = nil
+	fake.unlockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) UnlockReturnsOnCall(i int, result1 error) {
+	fake.unlockMutex.Lock()
+	defer fake.unlockMutex.Unlock()
+	fake.UnlockStub = nil
+	if fake.unlockReturnsOnCall == nil {
+		fake.unlockReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unlockReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *StateManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.exitCodeMutex.RLock()
+	defer fake.exitCodeMutex.RUnlock()
 	fake.initializeMutex.RLock()
 	defer fake.initializeMutex.RUnlock()
+	fake.loadMutex.RLock()
+	defer fake.loadMutex.RUnlock()
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	fake.ownEndpointIdMutex.RLock()
+	defer fake.ownEndpointIdMutex.RUnlock()
 	fake.setFailureMutex.RLock()
 	defer fake.setFailureMutex.RUnlock()
+	fake.setPausedMutex.RLock()
+	defer fake.setPausedMutex.RUnlock()
+	fake.setResourcesMutex.RLock()
+	defer fake.setResourcesMutex.RUnlock()
+	fake.setStoppedMutex.RLock()
+	defer fake.setStoppedMutex.RUnlock()
 	fake.setSuccessMutex.RLock()
 	defer fake.setSuccessMutex.RUnlock()
 	fake.stateMutex.RLock()
 	defer fake.stateMutex.RUnlock()
+	fake.unlockMutex.RLock()
+	defer fake.unlockMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,285 @@
+package runtime_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	"github.com/Microsoft/hcsshim"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("GC", func() {
+	var (
+		mounter            *fakes.Mounter
+		stateFactory       *fakes.StateFactory
+		managers           map[string]*fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		containers         map[string]*fakes.ContainerManager
+		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		rootDir            string
+		r                  *runtime.Runtime
+		output             *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		var err error
+		rootDir, err = os.MkdirTemp("", "gc.root")
+		Expect(err).NotTo(HaveOccurred())
+
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		containerFactory = &fakes.ContainerFactory{}
+		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
+		output = gbytes.NewBuffer()
+
+		managers = map[string]*fakes.StateManager{}
+		containers = map[string]*fakes.ContainerManager{}
+		for _, id := range []string{"running-container", "state-only-container", "hcs-only-container", "crashed-create-container", "creating-container"} {
+			managers[id] = &fakes.StateManager{}
+			containers[id] = &fakes.ContainerManager{}
+		}
+
+		for _, id := range []string{"running-container", "state-only-container"} {
+			Expect(os.MkdirAll(filepath.Join(rootDir, id), 0755)).To(Succeed())
+		}
+
+		managers["running-container"].LoadReturns(state.State{PID: 99}, nil)
+		managers["running-container"].StateReturns(&specs.State{Status: specs.StateRunning, Pid: 99}, nil)
+		managers["state-only-container"].LoadReturns(state.State{PID: 88}, nil)
+		managers["state-only-container"].StateReturns(nil, &hcs.NotFoundError{Id: "state-only-container"})
+		managers["hcs-only-container"].LoadReturns(state.State{}, os.ErrNotExist)
+		managers["hcs-only-container"].StateReturns(nil, os.ErrNotExist)
+
+		stateFactory.NewManagerStub = func(_ *logrus.Entry, _ *hcs.Client, _ *winsyscall.WinSyscall, id, _ string) runtime.StateManager {
+			return managers[id]
+		}
+		containerFactory.NewManagerStub = func(_ *logrus.Entry, _ *hcs.Client, id string) runtime.ContainerManager {
+			return containers[id]
+		}
+
+		hcsQuery.GetContainersReturns([]hcsshim.ContainerProperties{
+			{ID: "running-container"},
+			{ID: "hcs-only-container", Owner: "winc:" + filepath.Clean(rootDir)},
+			{ID: "other-root-container", Owner: `winc:C:\other-root`},
+			{ID: "other-runtime-container", Owner: "docker"},
+		}, nil)
+
+		mounter.MountsReturns([]string{"running-container", "state-only-container", "leaked-container", "other-root-container"}, nil)
+
+		// 99 is the pid of running-container and 88 of state-only-container
+		mounter.LegacyMountsReturns([]int{99, 88, 4242}, nil)
+
+		containerFactory.FileMountsReturns([]string{"running-container", "other-root-container", "crashed-create-container", "creating-container"}, nil)
+		managers["creating-container"].LockReturns(&state.BusyError{Id: "creating-container"})
+
+		config := runtime.Config{}
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	report := func() runtime.GCReport {
+		var report runtime.GCReport
+		Expect(json.Unmarshal(output.Contents(), &report)).To(Succeed())
+		return report
+	}
+
+	It("removes the orphaned compute systems, state dirs and mounts and reports them", func() {
+		Expect(r.GC(false, output)).To(Succeed())
+
+		Expect(report()).To(Equal(runtime.GCReport{
+			ComputeSystems: []string{"hcs-only-container"},
+			StateDirs:      []string{"state-only-container"},
+			Mounts:         []string{"state-only-container", "leaked-container"},
+			LegacyMounts:   []int{88, 4242},
+			FileMounts:     []string{"crashed-create-container"},
+		}))
+
+		force, timeout := containers["hcs-only-container"].DeleteArgsForCall(0)
+		Expect(force).To(BeTrue())
+		Expect(timeout).To(Equal(time.Minute))
+		Expect(managers["hcs-only-container"].LockCallCount()).To(Equal(1))
+		Expect(managers["hcs-only-container"].UnlockCallCount()).To(Equal(1))
+
+		Expect(managers["state-only-container"].DeleteCallCount()).To(Equal(1))
+		Expect(containers["state-only-container"].DeleteCallCount()).To(Equal(0))
+		Expect(managers["state-only-container"].LockCallCount()).To(Equal(1))
+
+		Expect(managers["running-container"].DeleteCallCount()).To(Equal(0))
+		Expect(containers["running-container"].DeleteCallCount()).To(Equal(0))
+
+		By("leaving compute systems winc did not create for this root dir alone")
+		for _, call := range containerFactory.Invocations()["NewManager"] {
+			Expect(call[2]).NotTo(BeElementOf("other-root-container", "other-runtime-container"))
+		}
+
+		Expect(mounter.UnmountCallCount()).To(Equal(5))
+		id, _ := mounter.UnmountArgsForCall(0)
+		Expect(id).To(Equal("hcs-only-container"))
+		id, _ = mounter.UnmountArgsForCall(1)
+		Expect(id).To(Equal("state-only-container"))
+		id, _ = mounter.UnmountArgsForCall(2)
+		Expect(id).To(Equal("leaked-container"))
+
+		By("removing legacy mounts made by pid, except those of containers that are in use")
+		id, pid := mounter.UnmountArgsForCall(3)
+		Expect(id).To(BeEmpty())
+		Expect(pid).To(Equal(88))
+		id, pid = mounter.UnmountArgsForCall(4)
+		Expect(id).To(BeEmpty())
+		Expect(pid).To(Equal(4242))
+
+		By("removing the file mounts of creates that crashed before their compute system existed")
+		Expect(containers["crashed-create-container"].RemoveFileMountsCallCount()).To(Equal(1))
+		Expect(managers["crashed-create-container"].LockCallCount()).To(Equal(1))
+		Expect(managers["crashed-create-container"].UnlockCallCount()).To(Equal(1))
+		Expect(containers["running-container"].RemoveFileMountsCallCount()).To(Equal(0))
+
+		By("skipping the file mounts of a create that is still in progress")
+		Expect(containers["creating-container"].RemoveFileMountsCallCount()).To(Equal(0))
+	})
+
+	Context("dry run is true", func() {
+		It("reports the orphans without removing them", func() {
+			Expect(r.GC(true, output)).To(Succeed())
+
+			Expect(report()).To(Equal(runtime.GCReport{
+				DryRun:         true,
+				ComputeSystems: []string{"hcs-only-container"},
+				StateDirs:      []string{"state-only-container"},
+				Mounts:         []string{"state-only-container", "leaked-container"},
+				LegacyMounts:   []int{88, 4242},
+				FileMounts:     []string{"crashed-create-container", "creating-container"},
+			}))
+
+			Expect(containers["hcs-only-container"].DeleteCallCount()).To(Equal(0))
+			Expect(managers["state-only-container"].DeleteCallCount()).To(Equal(0))
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+			Expect(containers["crashed-create-container"].RemoveFileMountsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("another command is operating on an orphan", func() {
+		BeforeEach(func() {
+			managers["state-only-container"].LockReturns(&state.BusyError{Id: "state-only-container"})
+		})
+
+		It("skips it and its mount", func() {
+			Expect(r.GC(false, output)).To(Succeed())
+
+			Expect(report()).To(Equal(runtime.GCReport{
+				ComputeSystems: []string{"hcs-only-container"},
+				StateDirs:      []string{},
+				Mounts:         []string{"leaked-container"},
+				LegacyMounts:   []int{4242},
+				FileMounts:     []string{"crashed-create-container"},
+			}))
+
+			Expect(managers["state-only-container"].DeleteCallCount()).To(Equal(0))
+			Expect(mounter.UnmountCallCount()).To(Equal(3))
+			id, _ := mounter.UnmountArgsForCall(1)
+			Expect(id).To(Equal("leaked-container"))
+		})
+	})
+
+	Context("an orphan is no longer an orphan once locked", func() {
+		BeforeEach(func() {
+			managers["hcs-only-container"].LoadReturns(state.State{Bundle: "some/bundle"}, nil)
+		})
+
+		It("skips it", func() {
+			Expect(r.GC(false, output)).To(Succeed())
+
+			Expect(report().ComputeSystems).To(BeEmpty())
+			Expect(containers["hcs-only-container"].DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("removing an orphan fails", func() {
+		BeforeEach(func() {
+			containers["hcs-only-container"].DeleteReturns(errors.New("couldn't delete"))
+			mounter.UnmountStub = func(id string, pid int) error {
+				if id == "leaked-container" {
+					return errors.New("couldn't unmount")
+				}
+				if pid == 4242 {
+					return errors.New("couldn't unmount legacy mount")
+				}
+				return nil
+			}
+			containers["crashed-create-container"].RemoveFileMountsReturns(errors.New("couldn't remove file mounts"))
+		})
+
+		It("carries on, reports the errors and returns an error", func() {
+			err := r.GC(false, output)
+			Expect(err).To(MatchError("failed to remove some orphans"))
+
+			Expect(report()).To(Equal(runtime.GCReport{
+				ComputeSystems: []string{},
+				StateDirs:      []string{"state-only-container"},
+				Mounts:         []string{"state-only-container"},
+				LegacyMounts:   []int{88},
+				FileMounts:     []string{},
+				Errors:         []string{"couldn't delete", "couldn't unmount", "couldn't unmount legacy mount", "couldn't remove file mounts"},
+			}))
+		})
+	})
+
+	Context("listing the mounts fails", func() {
+		BeforeEach(func() {
+			mounter.MountsReturns(nil, errors.New("couldn't read c:\\proc"))
+		})
+
+		It("returns the error without removing anything", func() {
+			Expect(r.GC(false, output)).To(MatchError("couldn't read c:\\proc"))
+			Expect(hcsQuery.GetContainersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("listing the legacy mounts fails", func() {
+		BeforeEach(func() {
+			mounter.LegacyMountsReturns(nil, errors.New("couldn't read c:\\proc"))
+		})
+
+		It("returns the error without removing anything", func() {
+			Expect(r.GC(false, output)).To(MatchError("couldn't read c:\\proc"))
+			Expect(hcsQuery.GetContainersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("listing the file mounts fails", func() {
+		BeforeEach(func() {
+			containerFactory.FileMountsReturns(nil, errors.New("couldn't read winc-file-mounts"))
+		})
+
+		It("returns the error without removing anything", func() {
+			Expect(r.GC(false, output)).To(MatchError("couldn't read winc-file-mounts"))
+			Expect(hcsQuery.GetContainersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("output is nil", func() {
+		It("returns an error", func() {
+			Expect(r.GC(false, nil)).To(MatchError("provided output is nil"))
+		})
+	})
+})
@@ -16,14 +16,20 @@ import (
 * In the worst case, we wait for one more second before cutting off
 * the process
  */
-const MAX_GRACEFUL_SHUTDOWN_ALLOWED = 10*time.Second + 1*time.Second
+const DefaultDrainTimeout = 10*time.Second + 1*time.Second
 
 type Process struct {
-	process hcsshim.Process
+	process      hcsshim.Process
+	drainTimeout time.Duration
 }
 
-func New(p hcsshim.Process) *Process {
-	return &Process{process: p}
+// New wraps p. Once p exits, AttachIO waits up to drainTimeout for its
+// output to be copied, or DefaultDrainTimeout if drainTimeout isn't positive
+func New(p hcsshim.Process, drainTimeout time.Duration) *Process {
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+	return &Process{process: p, drainTimeout: drainTimeout}
 }
 
 func (p *Process) WritePIDFile(pidFile string) error {
@@ -68,19 +74,22 @@ func (p *Process) AttachIO(attachStdin io.Reader, attachStdout, attachStderr io.
 		_ = stdout.Close()
 	}
 
-	if attachStderr != nil {
-		wg.Add(1)
-		go func() {
-			_, _ = io.Copy(attachStderr, stderr)
+	// processes with an emulated console have no stderr pipe
+	if stderr != nil {
+		if attachStderr != nil {
+			wg.Add(1)
+			go func() {
+				_, _ = io.Copy(attachStderr, stderr)
+				_ = stderr.Close()
+				wg.Done()
+			}()
+		} else {
 			_ = stderr.Close()
-			wg.Done()
-		}()
-	} else {
-		_ = stderr.Close()
+		}
 	}
 
 	err = p.process.Wait()
-	waitWithTimeout(&wg, MAX_GRACEFUL_SHUTDOWN_ALLOWED)
+	waitWithTimeout(&wg, p.drainTimeout)
 	if err != nil {
 		return -1, err
 	}
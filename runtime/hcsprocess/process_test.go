@@ -2,8 +2,10 @@ package hcsprocess_test
 
 import (
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime/fakes"
@@ -20,7 +22,7 @@ var _ = Describe("Process", func() {
 
 	BeforeEach(func() {
 		fakeProcess = &hcsfakes.Process{}
-		wrappedProcess = hcsprocess.New(fakeProcess)
+		wrappedProcess = hcsprocess.New(fakeProcess, 0)
 		var err error
 		tempDir, err = os.MkdirTemp("", "process")
 		Expect(err).NotTo(HaveOccurred())
@@ -114,6 +116,25 @@ var _ = Describe("Process", func() {
 			})
 		})
 
+		Context("when stdout stays open after the process exits", func() {
+			BeforeEach(func() {
+				wrappedProcess = hcsprocess.New(fakeProcess, 100*time.Millisecond)
+				fakeProcess.StdioReturns(processStdin, io.NopCloser(&fakes.Reader{}), processStderr, nil)
+			})
+
+			It("stops waiting for it after the drain timeout", func() {
+				code := make(chan int)
+				go func() {
+					exitCode, err := wrappedProcess.AttachIO(attachedStdin, attachedStdout, attachedStderr)
+					Expect(err).NotTo(HaveOccurred())
+					code <- exitCode
+				}()
+
+				Eventually(code, time.Second).Should(Receive(Equal(0)))
+				Expect(attachedStderr).To(gbytes.Say("something-on-stderr"))
+			})
+		})
+
 		Context("when getting the stdio streams fails", func() {
 			BeforeEach(func() {
 				fakeProcess.StdioReturns(nil, nil, nil, errors.New("some error"))
@@ -194,6 +215,20 @@ var _ = Describe("Process", func() {
 				Expect(attachedStderr.Contents()).To(Equal([]byte{}))
 			})
 		})
+
+		Context("the process has no stderr pipe", func() {
+			BeforeEach(func() {
+				fakeProcess.StdioReturns(processStdin, processStdout, nil, nil)
+			})
+
+			It("attaches stdin and stdout", func() {
+				_, err := wrappedProcess.AttachIO(attachedStdin, attachedStdout, attachedStderr)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(processStdin).Should(gbytes.Say("something-on-stdin"))
+				Eventually(attachedStdout).Should(gbytes.Say("something-on-stdout"))
+				Expect(attachedStderr.Contents()).To(Equal([]byte{}))
+			})
+		})
 	})
 
 	Describe("SetInterrupt", func() {
@@ -0,0 +1,29 @@
+package hooks
+
+import (
+	"fmt"
+	"strings"
+)
+
+type HookFailedError struct {
+	Path          string
+	Output        string
+	InternalError error
+}
+
+func (e *HookFailedError) Error() string {
+	msg := fmt.Sprintf("hook %s failed: %s", e.Path, e.InternalError)
+	if output := strings.TrimSpace(e.Output); output != "" {
+		msg = fmt.Sprintf("%s: %s", msg, output)
+	}
+	return msg
+}
+
+type TimeoutError struct {
+	Path    string
+	Timeout int
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("hook %s timed out after %ds", e.Path, e.Timeout)
+}
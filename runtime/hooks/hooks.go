@@ -0,0 +1,81 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"time"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+type Runner struct{}
+
+// Run executes each hook on the host in order, passing the container state as
+// JSON on stdin, and stops at the first one that fails
+func (r *Runner) Run(hooks []specs.Hook, state *specs.State, logger *logrus.Entry) error {
+	stateJson, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		if err := runHook(hook, stateJson, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunAll executes every hook on the host in order, like Run, but carries on
+// past hooks that fail, logging them as warnings. The OCI spec requires this
+// of poststart and poststop hooks.
+func (r *Runner) RunAll(hooks []specs.Hook, state *specs.State, logger *logrus.Entry) {
+	stateJson, err := json.Marshal(state)
+	if err != nil {
+		logger.WithError(err).Warn("could not marshal state for hooks")
+		return
+	}
+
+	for _, hook := range hooks {
+		if err := runHook(hook, stateJson, logger); err != nil {
+			logger.WithError(err).Warn("hook failed")
+		}
+	}
+}
+
+func runHook(hook specs.Hook, stateJson []byte, logger *logrus.Entry) error {
+	logger = logger.WithField("hook", hook.Path)
+	logger.Debug("running hook")
+
+	ctx := context.Background()
+	if hook.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*hook.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, hook.Path)
+	if len(hook.Args) > 0 {
+		cmd.Args = hook.Args
+	}
+	cmd.Env = hook.Env
+	cmd.Stdin = bytes.NewReader(stateJson)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return &TimeoutError{Path: hook.Path, Timeout: *hook.Timeout}
+		}
+		return &HookFailedError{Path: hook.Path, Output: output.String(), InternalError: err}
+	}
+
+	logger.WithField("output", output.String()).Debug("hook succeeded")
+	return nil
+}
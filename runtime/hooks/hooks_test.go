@@ -0,0 +1,134 @@
+package hooks_test
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/winc/runtime/hooks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		runner     *hooks.Runner
+		logger     *logrus.Entry
+		state      *specs.State
+		tempDir    string
+		powershell string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "hooks")
+		Expect(err).NotTo(HaveOccurred())
+
+		runner = &hooks.Runner{}
+		logger = (&logrus.Logger{
+			Out: io.Discard,
+		}).WithField("test", "hooks")
+		state = &specs.State{
+			Version: specs.Version,
+			ID:      "some-container",
+			Status:  specs.StateCreating,
+			Bundle:  "some-bundle",
+		}
+		powershell = filepath.Join(os.Getenv("SystemRoot"), "System32", "WindowsPowerShell", "v1.0", "powershell.exe")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("runs each hook with the state on stdin and the hook env", func() {
+		first := filepath.Join(tempDir, "first.json")
+		second := filepath.Join(tempDir, "second.txt")
+
+		Expect(runner.Run([]specs.Hook{
+			{
+				Path: powershell,
+				Args: []string{"powershell.exe", "-NoProfile", "-Command", "[Console]::In.ReadToEnd() | Set-Content -NoNewline -Path " + first},
+			},
+			{
+				Path: powershell,
+				Args: []string{"powershell.exe", "-NoProfile", "-Command", "Set-Content -NoNewline -Path " + second + " -Value $env:HOOK_VALUE"},
+				Env:  []string{"HOOK_VALUE=some-value", "SystemRoot=" + os.Getenv("SystemRoot")},
+			},
+		}, state, logger)).To(Succeed())
+
+		contents, err := os.ReadFile(first)
+		Expect(err).NotTo(HaveOccurred())
+		var received specs.State
+		Expect(json.Unmarshal(contents, &received)).To(Succeed())
+		Expect(received).To(Equal(*state))
+
+		contents, err = os.ReadFile(second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-value"))
+	})
+
+	Context("a hook fails", func() {
+		It("returns an error including its output and does not run the remaining hooks", func() {
+			marker := filepath.Join(tempDir, "marker")
+
+			err := runner.Run([]specs.Hook{
+				{
+					Path: powershell,
+					Args: []string{"powershell.exe", "-NoProfile", "-Command", "Write-Output 'something went wrong'; exit 3"},
+				},
+				{
+					Path: powershell,
+					Args: []string{"powershell.exe", "-NoProfile", "-Command", "New-Item -Path " + marker},
+				},
+			}, state, logger)
+			Expect(err).To(BeAssignableToTypeOf(&hooks.HookFailedError{}))
+			Expect(err.Error()).To(ContainSubstring("exit status 3"))
+			Expect(err.Error()).To(ContainSubstring("something went wrong"))
+			Expect(marker).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("RunAll", func() {
+		It("runs the remaining hooks after one fails", func() {
+			marker := filepath.Join(tempDir, "marker")
+
+			runner.RunAll([]specs.Hook{
+				{
+					Path: powershell,
+					Args: []string{"powershell.exe", "-NoProfile", "-Command", "exit 3"},
+				},
+				{Path: filepath.Join(tempDir, "missing.exe")},
+				{
+					Path: powershell,
+					Args: []string{"powershell.exe", "-NoProfile", "-Command", "New-Item -Path " + marker},
+				},
+			}, state, logger)
+			Expect(marker).To(BeAnExistingFile())
+		})
+	})
+
+	Context("a hook exceeds its timeout", func() {
+		It("kills the hook and returns a timeout error", func() {
+			timeout := 1
+			err := runner.Run([]specs.Hook{
+				{
+					Path:    powershell,
+					Args:    []string{"powershell.exe", "-NoProfile", "-Command", "Start-Sleep -Seconds 30"},
+					Timeout: &timeout,
+				},
+			}, state, logger)
+			Expect(err).To(MatchError(&hooks.TimeoutError{Path: powershell, Timeout: 1}))
+		})
+	})
+
+	Context("the hook does not exist", func() {
+		It("returns an error", func() {
+			err := runner.Run([]specs.Hook{{Path: filepath.Join(tempDir, "missing.exe")}}, state, logger)
+			Expect(err).To(BeAssignableToTypeOf(&hooks.HookFailedError{}))
+		})
+	})
+})
@@ -0,0 +1,151 @@
+package runtime_test
+
+import (
+	"errors"
+	"syscall"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Kill", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-kill"
+	)
+	var (
+		mounter            *fakes.Mounter
+		stateFactory       *fakes.StateFactory
+		sm                 *fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		cm                 *fakes.ContainerManager
+		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		r                  *runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		sm.StateReturns(&specs.State{Status: specs.StateRunning}, nil)
+
+		config := runtime.Config{}
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+	})
+
+	It("kills the container and marks it as stopped", func() {
+		Expect(r.Kill(containerId, "SIGTERM", false)).To(Succeed())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+		Expect(id).To(Equal(containerId))
+		Expect(rd).To(Equal(rootDir))
+
+		Expect(cm.KillCallCount()).To(Equal(1))
+		signal, all := cm.KillArgsForCall(0)
+		Expect(signal).To(Equal(syscall.SIGTERM))
+		Expect(all).To(BeFalse())
+
+		Expect(sm.SetStoppedCallCount()).To(Equal(1))
+	})
+
+	DescribeTable("parsing the signal",
+		func(signal string, expected syscall.Signal) {
+			Expect(r.Kill(containerId, signal, true)).To(Succeed())
+
+			sig, all := cm.KillArgsForCall(0)
+			Expect(sig).To(Equal(expected))
+			Expect(all).To(BeTrue())
+		},
+		Entry("SIGTERM", "SIGTERM", syscall.SIGTERM),
+		Entry("TERM", "TERM", syscall.SIGTERM),
+		Entry("lowercase term", "term", syscall.SIGTERM),
+		Entry("15", "15", syscall.SIGTERM),
+		Entry("SIGKILL", "SIGKILL", syscall.SIGKILL),
+		Entry("KILL", "KILL", syscall.SIGKILL),
+		Entry("9", "9", syscall.SIGKILL),
+	)
+
+	Context("the signal is not supported", func() {
+		It("returns an error without killing the container", func() {
+			err := r.Kill(containerId, "SIGHUP", false)
+			Expect(err).To(MatchError(&container.UnsupportedSignalError{Signal: "SIGHUP"}))
+
+			Expect(cm.KillCallCount()).To(Equal(0))
+			Expect(sm.SetStoppedCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the container is already stopped", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: specs.StateStopped}, nil)
+		})
+
+		It("returns an error", func() {
+			err := r.Kill(containerId, "SIGKILL", false)
+			Expect(err).To(MatchError("cannot kill a container in the stopped state"))
+
+			Expect(cm.KillCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("getting the state fails", func() {
+		BeforeEach(func() {
+			sm.StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("returns the error", func() {
+			err := r.Kill(containerId, "SIGKILL", false)
+			Expect(err).To(MatchError("couldn't get state"))
+
+			Expect(cm.KillCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("killing the container fails", func() {
+		BeforeEach(func() {
+			cm.KillReturns(errors.New("couldn't kill"))
+		})
+
+		It("returns the error and does not update the state", func() {
+			err := r.Kill(containerId, "SIGKILL", false)
+			Expect(err).To(MatchError("couldn't kill"))
+
+			Expect(sm.SetStoppedCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("updating the state fails", func() {
+		BeforeEach(func() {
+			sm.SetStoppedReturns(errors.New("couldn't set stopped"))
+		})
+
+		It("returns the error", func() {
+			err := r.Kill(containerId, "SIGKILL", false)
+			Expect(err).To(MatchError("couldn't set stopped"))
+		})
+	})
+})
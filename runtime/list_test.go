@@ -0,0 +1,182 @@
+package runtime_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	"github.com/Microsoft/hcsshim"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("List", func() {
+	var (
+		mounter            *fakes.Mounter
+		stateFactory       *fakes.StateFactory
+		managers           map[string]*fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		rootDir            string
+		r                  *runtime.Runtime
+		output             *gbytes.Buffer
+		created            time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		rootDir, err = os.MkdirTemp("", "list.root")
+		Expect(err).NotTo(HaveOccurred())
+
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		containerFactory = &fakes.ContainerFactory{}
+		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
+		output = gbytes.NewBuffer()
+		created = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		managers = map[string]*fakes.StateManager{}
+		for _, id := range []string{"running-container", "state-only-container"} {
+			Expect(os.MkdirAll(filepath.Join(rootDir, id), 0755)).To(Succeed())
+			managers[id] = &fakes.StateManager{}
+		}
+		Expect(os.WriteFile(filepath.Join(rootDir, "not-a-container"), []byte{}, 0644)).To(Succeed())
+
+		managers["running-container"].LoadReturns(state.State{Bundle: "some/bundle", PID: 99, Created: created}, nil)
+		managers["running-container"].StateReturns(&specs.State{Status: specs.StateRunning}, nil)
+		managers["state-only-container"].LoadReturns(state.State{Bundle: "other/bundle", PID: 88, Created: created}, nil)
+
+		stateFactory.NewManagerStub = func(_ *logrus.Entry, _ *hcs.Client, _ *winsyscall.WinSyscall, id, _ string) runtime.StateManager {
+			return managers[id]
+		}
+
+		hcsQuery.GetContainersReturns([]hcsshim.ContainerProperties{
+			{ID: "running-container"},
+			{ID: "hcs-only-container", Stopped: true, Owner: "winc:" + filepath.Clean(rootDir)},
+		}, nil)
+
+		config := runtime.Config{}
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	It("joins the state directories with the HCS compute systems", func() {
+		Expect(r.List("json", false, output)).To(Succeed())
+
+		Expect(hcsQuery.GetContainersArgsForCall(0)).To(Equal(hcsshim.ComputeSystemQuery{Types: []string{"Container"}}))
+
+		var containers []runtime.ContainerSummary
+		Expect(json.Unmarshal(output.Contents(), &containers)).To(Succeed())
+		Expect(containers).To(Equal([]runtime.ContainerSummary{
+			{ID: "hcs-only-container", Status: "stopped", Orphan: runtime.OrphanNoState},
+			{ID: "running-container", Pid: 99, Status: "running", Bundle: "some/bundle", Created: created},
+			{ID: "state-only-container", Pid: 88, Status: "stopped", Bundle: "other/bundle", Created: created, Orphan: runtime.OrphanNoComputeSystem},
+		}))
+
+		Expect(managers["state-only-container"].StateCallCount()).To(Equal(0))
+	})
+
+	Context("there are compute systems winc did not create for this root dir", func() {
+		BeforeEach(func() {
+			hcsQuery.GetContainersReturns([]hcsshim.ContainerProperties{
+				{ID: "running-container"},
+				{ID: "hcs-only-container", Owner: "winc:" + filepath.Clean(rootDir)},
+				{ID: "hcs-only-sidecar", Owner: "hcs-only-container"},
+				{ID: "running-sidecar", Owner: "running-container"},
+				{ID: "other-root-container", Owner: `winc:C:\other-root`},
+				{ID: "other-runtime-container", Owner: "docker"},
+				{ID: "other-runtime-sidecar", Owner: "other-runtime-container"},
+			}, nil)
+		})
+
+		It("ignores them", func() {
+			Expect(r.List("json", true, output)).To(Succeed())
+
+			Expect(string(output.Contents())).To(Equal("hcs-only-container\nhcs-only-sidecar\nrunning-container\nrunning-sidecar\nstate-only-container\n"))
+		})
+	})
+
+	It("prints a table by default", func() {
+		Expect(r.List("table", false, output)).To(Succeed())
+
+		Expect(output).To(gbytes.Say(`ID\s+PID\s+STATUS\s+BUNDLE\s+CREATED\s+ORPHAN`))
+		Expect(output).To(gbytes.Say(`hcs-only-container\s+0\s+stopped\s+no state`))
+		Expect(output).To(gbytes.Say(`running-container\s+99\s+running\s+some/bundle\s+2020-01-02T03:04:05Z`))
+		Expect(output).To(gbytes.Say(`state-only-container\s+88\s+stopped\s+other/bundle\s+2020-01-02T03:04:05Z\s+no compute system`))
+	})
+
+	Context("quiet is true", func() {
+		It("only prints the container ids", func() {
+			Expect(r.List("table", true, output)).To(Succeed())
+			Expect(string(output.Contents())).To(Equal("hcs-only-container\nrunning-container\nstate-only-container\n"))
+		})
+	})
+
+	Context("the state of a container cannot be determined", func() {
+		BeforeEach(func() {
+			managers["running-container"].StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("reports the status as unknown", func() {
+			Expect(r.List("json", false, output)).To(Succeed())
+
+			var containers []runtime.ContainerSummary
+			Expect(json.Unmarshal(output.Contents(), &containers)).To(Succeed())
+			Expect(containers[1].ID).To(Equal("running-container"))
+			Expect(containers[1].Status).To(Equal("unknown"))
+		})
+	})
+
+	Context("the root dir does not exist", func() {
+		BeforeEach(func() {
+			Expect(os.RemoveAll(rootDir)).To(Succeed())
+			hcsQuery.GetContainersReturns(nil, nil)
+		})
+
+		It("prints an empty list", func() {
+			Expect(r.List("json", false, output)).To(Succeed())
+			Expect(string(output.Contents())).To(Equal("[]\n"))
+		})
+	})
+
+	Context("an invalid format is provided", func() {
+		It("returns an error", func() {
+			Expect(r.List("yaml", false, output)).To(MatchError("invalid format option: yaml"))
+			Expect(hcsQuery.GetContainersCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("querying HCS fails", func() {
+		BeforeEach(func() {
+			hcsQuery.GetContainersReturns(nil, errors.New("couldn't query"))
+		})
+
+		It("returns the error", func() {
+			Expect(r.List("table", false, output)).To(MatchError("couldn't query"))
+		})
+	})
+
+	Context("list is passed a nil io.Writer", func() {
+		It("returns an error", func() {
+			Expect(r.List("table", false, nil)).To(MatchError("provided output is nil"))
+		})
+	})
+})
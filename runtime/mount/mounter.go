@@ -19,29 +19,203 @@ var (
 	setVolumeMountPointW    = kernel32.NewProc("SetVolumeMountPointW")
 )
 
-type Mounter struct{}
+// DefaultRoot is where volumes are mounted if the winc config file doesn't
+// set a mount root
+const DefaultRoot = "c:\\proc"
 
-func (m *Mounter) Mount(pid int, volumePath string, logger *logrus.Entry) error {
-	if _, err := os.Stat(mountPath(pid)); !os.IsNotExist(err) {
-		err := fmt.Errorf("mountdir exists: %s", mountPath(pid))
+// stillActive is the exit code of a process that hasn't exited
+const stillActive = 259
+
+// Mounter mounts the volume of each container at <Root>\containers\<id>\root.
+// <Root>\<pid>, where pid is the container's init process, is a symlink to
+// <Root>\containers\<id> so that the volume can still be found at
+// <Root>\<pid>\root, where it was mounted before mounts were keyed by
+// container id.
+type Mounter struct {
+	Root string
+}
+
+func (m *Mounter) Mount(containerId string, pid int, volumePath string, logger *logrus.Entry) error {
+	if _, err := os.Stat(m.mountPath(containerId)); !os.IsNotExist(err) {
+		err := fmt.Errorf("mountdir exists: %s", m.mountPath(containerId))
 		logger.Error(err.Error())
 		return err
 	}
 
-	if err := os.MkdirAll(rootPath(pid), 0755); err != nil {
+	// a symlink for the pid is left over from a container whose mount leaked
+	// before the pid was reused, but a directory is the mount of a running
+	// container from a version of winc that keyed mounts by pid
+	if fi, err := os.Lstat(m.pidPath(pid)); err == nil {
+		if fi.Mode()&os.ModeSymlink == 0 {
+			err := fmt.Errorf("mountdir exists: %s", m.pidPath(pid))
+			logger.Error(err.Error())
+			return err
+		}
+
+		logger.WithField("pid", pid).Warn("replacing stale pid link")
+		if err := os.Remove(m.pidPath(pid)); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(m.rootPath(containerId), 0755); err != nil {
+		return err
+	}
+
+	if err := m.setPoint(m.rootPath(containerId), volumePath); err != nil {
+		// #nosec G104 - nothing was mounted, and the mount failure is the error we want to report
+		os.RemoveAll(m.mountPath(containerId))
+		return err
+	}
+
+	if err := os.Symlink(m.mountPath(containerId), m.pidPath(pid)); err != nil {
+		// #nosec G104 - the symlink failure is the error we want to report
+		m.Unmount(containerId, 0)
+		return err
+	}
+
+	return nil
+}
+
+// Unmount removes a container's mount and its pid symlink, and succeeds if
+// nothing is mounted. pid is only used to find volumes mounted by versions of
+// winc that keyed mounts by pid, and may be 0. containerId may be empty to
+// only unmount such a volume.
+func (m *Mounter) Unmount(containerId string, pid int) error {
+	if containerId == "" {
+		return m.unmountPid(pid)
+	}
+
+	if _, err := os.Stat(m.mountPath(containerId)); os.IsNotExist(err) {
+		return m.unmountPid(pid)
+	}
+
+	if err := m.removePidLinks(containerId); err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(m.mountPath(containerId))
+	if err := m.deletePoint(m.rootPath(containerId)); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(m.mountPath(containerId))
+}
+
+// Mounts returns the ids of the containers that have a mount dir, whether or
+// not a volume is still mounted there
+func (m *Mounter) Mounts() ([]string, error) {
+	entries, err := os.ReadDir(m.containersPath())
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ids = append(ids, entry.Name())
+		}
+	}
+
+	return ids, nil
+}
+
+// LegacyMounts returns the pids of the mounts made by versions of winc that
+// keyed mounts by pid, whose process has exited. Nothing records which
+// container these belong to, so a mount whose process is still running is
+// assumed to be in use.
+func (m *Mounter) LegacyMounts() ([]int, error) {
+	entries, err := os.ReadDir(m.root())
+	if os.IsNotExist(err) {
+		return []int{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	pids := []int{}
+	for _, entry := range entries {
+		// pid links to mounts keyed by container id are symlinks, not dirs
+		if !entry.IsDir() {
+			continue
+		}
+
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		if !processRunning(pid) {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+}
+
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// ERROR_INVALID_PARAMETER is returned if the process doesn't exist,
+		// anything else, e.g. access denied, means it does
+		return err != windows.ERROR_INVALID_PARAMETER
+	}
+	defer windows.CloseHandle(h)
+
+	var exitCode uint32
+	if err := windows.GetExitCodeProcess(h, &exitCode); err != nil {
+		return true
+	}
+
+	return exitCode == stillActive
+}
+
+func (m *Mounter) unmountPid(pid int) error {
+	if pid == 0 {
+		return nil
+	}
+
+	// a symlink is another container's, which has since been given the pid
+	fi, err := os.Lstat(m.pidPath(pid))
+	if os.IsNotExist(err) || (err == nil && fi.Mode()&os.ModeSymlink != 0) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(m.pidPath(pid))
+	if err := m.deletePoint(filepath.Join(m.pidPath(pid), "root")); err != nil {
 		return err
 	}
 
-	return m.setPoint(rootPath(pid), volumePath)
+	return os.RemoveAll(m.pidPath(pid))
 }
 
-func (m *Mounter) Unmount(pid int) error {
-	defer os.RemoveAll(mountPath(pid))
-	if err := m.deletePoint(rootPath(pid)); err != nil {
+func (m *Mounter) removePidLinks(containerId string) error {
+	entries, err := os.ReadDir(m.root())
+	if err != nil {
 		return err
 	}
 
-	return os.RemoveAll(mountPath(pid))
+	for _, entry := range entries {
+		if entry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		link := filepath.Join(m.root(), entry.Name())
+		if target, err := os.Readlink(link); err == nil && target == m.mountPath(containerId) {
+			if err := os.Remove(link); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 func (m *Mounter) setPoint(mountPoint, volume string) error {
@@ -90,12 +264,27 @@ func (m *Mounter) deletePoint(mountPoint string) error {
 	return nil
 }
 
-func mountPath(pid int) string {
-	return filepath.Join("c:\\", "proc", strconv.Itoa(pid))
+func (m *Mounter) root() string {
+	if m.Root == "" {
+		return DefaultRoot
+	}
+	return m.Root
+}
+
+func (m *Mounter) containersPath() string {
+	return filepath.Join(m.root(), "containers")
+}
+
+func (m *Mounter) mountPath(containerId string) string {
+	return filepath.Join(m.containersPath(), containerId)
+}
+
+func (m *Mounter) rootPath(containerId string) string {
+	return filepath.Join(m.mountPath(containerId), "root")
 }
 
-func rootPath(pid int) string {
-	return filepath.Join(mountPath(pid), "root")
+func (m *Mounter) pidPath(pid int) string {
+	return filepath.Join(m.root(), strconv.Itoa(pid))
 }
 
 func ensureTrailingBackslash(in string) string {
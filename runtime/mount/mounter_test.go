@@ -19,10 +19,14 @@ import (
 
 var _ = Describe("Mounter", func() {
 	var (
-		volumeGuid string
-		pid        int
-		mountPath  string
-		logger     *logrus.Entry
+		volumeGuid  string
+		containerId string
+		pid         int
+		root        string
+		mountPath   string
+		pidPath     string
+		mounter     *mount.Mounter
+		logger      *logrus.Entry
 	)
 
 	BeforeEach(func() {
@@ -35,38 +39,135 @@ var _ = Describe("Mounter", func() {
 		Expect(err).NotTo(HaveOccurred())
 		// negate so we don't collide with any 'real' pids created by winc
 		pid = -int(p.Int64())
+		containerId = "container-" + strconv.Itoa(-pid)
+
+		root, err = os.MkdirTemp("", "mount.root")
+		Expect(err).NotTo(HaveOccurred())
 
 		logger = (&logrus.Logger{
 			Out: io.Discard,
 		}).WithField("test", "state")
 
-		mountPath = filepath.Join("C:\\", "proc", strconv.Itoa(pid), "root")
+		mountPath = filepath.Join(root, "containers", containerId, "root")
+		pidPath = filepath.Join(root, strconv.Itoa(pid))
+		mounter = &mount.Mounter{Root: root}
 	})
 
 	AfterEach(func() {
-		if err := exec.Command("mountvol", mountPath, "/L").Run(); err == nil {
-			_ = exec.Command("mountvol", mountPath, "/D").Run()
+		for _, path := range []string{mountPath, filepath.Join(pidPath, "root")} {
+			if err := exec.Command("mountvol", path, "/L").Run(); err == nil {
+				_ = exec.Command("mountvol", path, "/D").Run()
+			}
 		}
+		Expect(os.RemoveAll(root)).To(Succeed())
 	})
 
-	It("mounts and unmounts a volume", func() {
-		mounter := &mount.Mounter{}
-
-		Expect(mounter.Mount(pid, volumeGuid, logger)).To(Succeed())
+	It("mounts and unmounts a volume by container id", func() {
+		Expect(mounter.Mount(containerId, pid, volumeGuid, logger)).To(Succeed())
 		outBytes, err := exec.Command("mountvol", mountPath, "/L").CombinedOutput()
 		Expect(err).ToNot(HaveOccurred())
 		Expect(string(outBytes)).To(ContainSubstring(volumeGuid))
 
-		Expect(mounter.Unmount(pid)).To(Succeed())
+		Expect(mounter.Unmount(containerId, 0)).To(Succeed())
+		Expect(filepath.Dir(mountPath)).NotTo(BeADirectory())
+	})
+
+	It("links the pid to the mount", func() {
+		Expect(mounter.Mount(containerId, pid, volumeGuid, logger)).To(Succeed())
+
+		target, err := os.Readlink(pidPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(Equal(filepath.Dir(mountPath)))
+		Expect(filepath.Join(pidPath, "root", "Windows")).To(BeADirectory())
+
+		Expect(mounter.Unmount(containerId, 0)).To(Succeed())
+		_, err = os.Lstat(pidPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
 	})
 
-	It("mount a volume for a pid that already exist", func() {
-		mounter := &mount.Mounter{}
+	It("lists the ids of the containers with mounts", func() {
+		Expect(mounter.Mount(containerId, pid, volumeGuid, logger)).To(Succeed())
+		Expect(mounter.Mounts()).To(Equal([]string{containerId}))
 
+		Expect(mounter.Unmount(containerId, 0)).To(Succeed())
+		Expect(mounter.Mounts()).To(BeEmpty())
+	})
+
+	It("succeeds unmounting a container with nothing mounted", func() {
+		Expect(mounter.Unmount(containerId, pid)).To(Succeed())
+	})
+
+	It("mount a volume for a container that already exist", func() {
 		Expect(os.MkdirAll(mountPath, 0755)).To(Succeed())
 
-		err := mounter.Mount(pid, volumeGuid, logger)
+		err := mounter.Mount(containerId, pid, volumeGuid, logger)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(MatchRegexp("^mountdir exists"))
 	})
+
+	Context("the pid is linked to a leaked mount of another container", func() {
+		BeforeEach(func() {
+			Expect(mounter.Mount("leaked-container", pid, volumeGuid, logger)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(mounter.Unmount("leaked-container", 0)).To(Succeed())
+		})
+
+		It("links the pid to the new mount instead", func() {
+			Expect(mounter.Mount(containerId, pid, volumeGuid, logger)).To(Succeed())
+
+			target, err := os.Readlink(pidPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(target).To(Equal(filepath.Dir(mountPath)))
+
+			Expect(mounter.Unmount(containerId, 0)).To(Succeed())
+		})
+	})
+
+	Context("the volume was mounted by pid by an older version of winc", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Join(pidPath, "root"), 0755)).To(Succeed())
+			Expect(exec.Command("mountvol", filepath.Join(pidPath, "root"), volumeGuid).Run()).To(Succeed())
+		})
+
+		It("doesn't mount over it", func() {
+			err := mounter.Mount(containerId, pid, volumeGuid, logger)
+			Expect(err).To(MatchError("mountdir exists: " + pidPath))
+		})
+
+		It("unmounts it given the pid", func() {
+			Expect(mounter.Unmount(containerId, pid)).To(Succeed())
+			Expect(pidPath).NotTo(BeADirectory())
+		})
+
+		It("lists it as a legacy mount once its process has exited", func() {
+			Expect(mounter.LegacyMounts()).To(Equal([]int{pid}))
+			Expect(mounter.Mounts()).To(BeEmpty())
+		})
+
+		It("unmounts it given only the pid", func() {
+			Expect(mounter.Unmount("", pid)).To(Succeed())
+			Expect(pidPath).NotTo(BeADirectory())
+		})
+	})
+
+	Context("a legacy mount's process is still running", func() {
+		var runningPidPath string
+
+		BeforeEach(func() {
+			runningPidPath = filepath.Join(root, strconv.Itoa(os.Getpid()))
+			Expect(os.MkdirAll(filepath.Join(runningPidPath, "root"), 0755)).To(Succeed())
+		})
+
+		It("is not listed", func() {
+			Expect(mounter.LegacyMounts()).To(BeEmpty())
+		})
+	})
+
+	It("doesn't list pid links to mounts keyed by container id as legacy mounts", func() {
+		Expect(mounter.Mount(containerId, pid, volumeGuid, logger)).To(Succeed())
+		Expect(mounter.LegacyMounts()).To(BeEmpty())
+		Expect(mounter.Unmount(containerId, 0)).To(Succeed())
+	})
 })
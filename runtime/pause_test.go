@@ -0,0 +1,151 @@
+package runtime_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Pause and Resume", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-pause"
+	)
+	var (
+		mounter            *fakes.Mounter
+		stateFactory       *fakes.StateFactory
+		sm                 *fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		cm                 *fakes.ContainerManager
+		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		r                  *runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		config := runtime.Config{}
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+	})
+
+	Describe("Pause", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: specs.StateRunning}, nil)
+		})
+
+		It("pauses the container and records it in the state", func() {
+			Expect(r.Pause(containerId)).To(Succeed())
+
+			_, c, id := containerFactory.NewManagerArgsForCall(0)
+			Expect(*c).To(Equal(hcs.Client{}))
+			Expect(id).To(Equal(containerId))
+
+			_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
+			Expect(*c).To(Equal(hcs.Client{}))
+			Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+			Expect(id).To(Equal(containerId))
+			Expect(rd).To(Equal(rootDir))
+
+			Expect(cm.PauseCallCount()).To(Equal(1))
+			Expect(sm.SetPausedArgsForCall(0)).To(BeTrue())
+		})
+
+		Context("the container is not running", func() {
+			BeforeEach(func() {
+				sm.StateReturns(&specs.State{Status: specs.StateCreated}, nil)
+			})
+
+			It("returns an error", func() {
+				Expect(r.Pause(containerId)).To(MatchError("cannot pause a container in the created state"))
+				Expect(cm.PauseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("pausing the container fails", func() {
+			BeforeEach(func() {
+				cm.PauseReturns(errors.New("couldn't pause"))
+			})
+
+			It("returns the error without updating the state", func() {
+				Expect(r.Pause(containerId)).To(MatchError("couldn't pause"))
+				Expect(sm.SetPausedCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("recording the pause fails", func() {
+			BeforeEach(func() {
+				sm.SetPausedReturns(errors.New("couldn't write state"))
+			})
+
+			It("resumes the container and returns the error", func() {
+				Expect(r.Pause(containerId)).To(MatchError("couldn't write state"))
+				Expect(cm.ResumeCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: state.StatePaused}, nil)
+		})
+
+		It("resumes the container and records it in the state", func() {
+			Expect(r.Resume(containerId)).To(Succeed())
+
+			Expect(cm.ResumeCallCount()).To(Equal(1))
+			Expect(sm.SetPausedArgsForCall(0)).To(BeFalse())
+		})
+
+		Context("the container is not paused", func() {
+			BeforeEach(func() {
+				sm.StateReturns(&specs.State{Status: specs.StateRunning}, nil)
+			})
+
+			It("returns an error", func() {
+				Expect(r.Resume(containerId)).To(MatchError("cannot resume a container in the running state"))
+				Expect(cm.ResumeCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("getting the state fails", func() {
+			BeforeEach(func() {
+				sm.StateReturns(nil, errors.New("couldn't get state"))
+			})
+
+			It("returns the error", func() {
+				Expect(r.Resume(containerId)).To(MatchError("couldn't get state"))
+			})
+		})
+
+		Context("resuming the container fails", func() {
+			BeforeEach(func() {
+				cm.ResumeReturns(errors.New("couldn't resume"))
+			})
+
+			It("returns the error without updating the state", func() {
+				Expect(r.Resume(containerId)).To(MatchError("couldn't resume"))
+				Expect(sm.SetPausedCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
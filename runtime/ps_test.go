@@ -0,0 +1,141 @@
+package runtime_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Ps", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-for-ps"
+	)
+	var (
+		mounter            *fakes.Mounter
+		stateFactory       *fakes.StateFactory
+		sm                 *fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		cm                 *fakes.ContainerManager
+		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		r                  *runtime.Runtime
+		output             *gbytes.Buffer
+		startTime          time.Time
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		output = gbytes.NewBuffer()
+
+		config := runtime.Config{}
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+
+		startTime = time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+		cm.ProcessesReturns([]container.ProcessInfo{
+			{Pid: 200, Image: "cmd.exe", User: `User Manager\ContainerUser`, StartTime: startTime, CPUTime: 1500000000, WorkingSet: 4096 * 1024},
+			{Pid: 100, Image: "smss.exe", StartTime: startTime, CPUTime: 0, WorkingSet: 1024},
+		}, nil)
+	})
+
+	It("writes a table of the processes sorted by pid", func() {
+		Expect(r.Ps(containerId, "table", output)).To(Succeed())
+
+		Expect(cm.ProcessesArgsForCall(0)).To(BeFalse())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		Expect(string(output.Contents())).To(Equal(
+			"PID         IMAGE       USER                         START TIME             CPU TIME    WORKING SET\n" +
+				"100         smss.exe                                 2020-01-02T03:04:05Z   0s          1K\n" +
+				"200         cmd.exe     User Manager\\ContainerUser   2020-01-02T03:04:05Z   1.5s        4096K\n"))
+	})
+
+	Context("the format is json", func() {
+		It("writes the processes as json", func() {
+			Expect(r.Ps(containerId, "json", output)).To(Succeed())
+			Expect(output).To(gbytes.Say(`\[{"pid":100,"image":"smss.exe","start_time":"2020-01-02T03:04:05Z","cpu_time":0,"working_set":1024},` +
+				`{"pid":200,"image":"cmd.exe","user":"User Manager\\\\ContainerUser","start_time":"2020-01-02T03:04:05Z","cpu_time":1500000000,"working_set":4194304}\]`))
+		})
+	})
+
+	Context("the container is hyper-v isolated", func() {
+		BeforeEach(func() {
+			sm.LoadReturns(state.State{Isolation: state.IsolationHyperV}, nil)
+		})
+
+		It("lists the processes of the utility VM", func() {
+			Expect(r.Ps(containerId, "table", output)).To(Succeed())
+			Expect(cm.ProcessesArgsForCall(0)).To(BeTrue())
+		})
+	})
+
+	Context("the container has no state", func() {
+		BeforeEach(func() {
+			sm.LoadReturns(state.State{}, os.ErrNotExist)
+		})
+
+		It("lists the processes as process isolated", func() {
+			Expect(r.Ps(containerId, "table", output)).To(Succeed())
+			Expect(cm.ProcessesArgsForCall(0)).To(BeFalse())
+		})
+	})
+
+	Context("loading the state fails", func() {
+		BeforeEach(func() {
+			sm.LoadReturns(state.State{}, &state.CorruptStateError{Id: containerId, Reason: "potato"})
+		})
+
+		It("returns the error", func() {
+			Expect(r.Ps(containerId, "table", output)).To(MatchError(ContainSubstring("potato")))
+			Expect(cm.ProcessesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the format is invalid", func() {
+		It("returns an error", func() {
+			Expect(r.Ps(containerId, "yaml", output)).To(MatchError("invalid format option: yaml"))
+			Expect(cm.ProcessesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("listing the processes fails", func() {
+		BeforeEach(func() {
+			cm.ProcessesReturns(nil, errors.New("couldn't list processes"))
+		})
+
+		It("returns the error", func() {
+			Expect(r.Ps(containerId, "table", output)).To(MatchError("couldn't list processes"))
+		})
+	})
+
+	Context("provided output is nil", func() {
+		It("returns an error", func() {
+			Expect(r.Ps(containerId, "table", nil)).To(MatchError("provided output is nil"))
+		})
+	})
+})
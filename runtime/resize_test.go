@@ -0,0 +1,112 @@
+package runtime_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Resize", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-resize"
+	)
+	var (
+		mounter            *fakes.Mounter
+		stateFactory       *fakes.StateFactory
+		sm                 *fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		cm                 *fakes.ContainerManager
+		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		r                  *runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		config := runtime.Config{}
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+
+		sm.StateReturns(&specs.State{Status: specs.StateRunning, Pid: 99}, nil)
+	})
+
+	It("resizes the console of the given process", func() {
+		Expect(r.Resize(containerId, 42, 120, 40)).To(Succeed())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		pid, width, height := cm.ResizeConsoleArgsForCall(0)
+		Expect(pid).To(Equal(42))
+		Expect(width).To(Equal(uint16(120)))
+		Expect(height).To(Equal(uint16(40)))
+
+		Expect(sm.StateCallCount()).To(Equal(0))
+	})
+
+	Context("no pid is given", func() {
+		It("resizes the console of the init process", func() {
+			Expect(r.Resize(containerId, 0, 120, 40)).To(Succeed())
+
+			pid, _, _ := cm.ResizeConsoleArgsForCall(0)
+			Expect(pid).To(Equal(99))
+		})
+
+		Context("the container is not running", func() {
+			BeforeEach(func() {
+				sm.StateReturns(&specs.State{Status: specs.StateCreated}, nil)
+			})
+
+			It("returns an error", func() {
+				Expect(r.Resize(containerId, 0, 120, 40)).To(MatchError("cannot resize a container in the created state"))
+				Expect(cm.ResizeConsoleCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("getting the state fails", func() {
+			BeforeEach(func() {
+				sm.StateReturns(nil, errors.New("couldn't get state"))
+			})
+
+			It("returns an error", func() {
+				Expect(r.Resize(containerId, 0, 120, 40)).To(MatchError("couldn't get state"))
+			})
+		})
+	})
+
+	Context("the size is invalid", func() {
+		It("returns an error", func() {
+			Expect(r.Resize(containerId, 42, 0, 40)).To(MatchError("invalid console size: 0x40"))
+			Expect(cm.ResizeConsoleCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("resizing fails", func() {
+		BeforeEach(func() {
+			cm.ResizeConsoleReturns(errors.New("not a console process"))
+		})
+
+		It("returns the error", func() {
+			Expect(r.Resize(containerId, 42, 120, 40)).To(MatchError("not a console process"))
+		})
+	})
+})
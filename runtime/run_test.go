@@ -1,6 +1,7 @@
 package runtime_test
 
 import (
+	"io"
 	"os"
 
 	"github.com/pkg/errors"
@@ -31,6 +32,7 @@ var _ = Describe("Run", func() {
 		containerFactory   *fakes.ContainerFactory
 		cm                 *fakes.ContainerManager
 		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
 		wrappedProcess     *fakes.WrappedProcess
 		unwrappedProcess   *hcsfakes.Process
 		hcsQuery           *fakes.HCSQuery
@@ -51,6 +53,7 @@ var _ = Describe("Run", func() {
 		containerFactory = &fakes.ContainerFactory{}
 		cm = &fakes.ContainerManager{}
 		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
 		wrappedProcess = &fakes.WrappedProcess{}
 		unwrappedProcess = &hcsfakes.Process{}
 		spec = &specs.Spec{}
@@ -67,7 +70,7 @@ var _ = Describe("Run", func() {
 		containerFactory.NewManagerReturns(cm)
 
 		config := runtime.Config{}
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 
 		stdin = gbytes.NewBuffer()
 		stdout = gbytes.NewBuffer()
@@ -102,8 +105,11 @@ var _ = Describe("Run", func() {
 			Expect(rd).To(Equal(rootDir))
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
-			Expect(cm.CreateArgsForCall(0)).To(Equal(spec))
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			createSpec, _, _ := cm.CreateArgsForCall(0)
+			Expect(createSpec).To(Equal(spec))
+			bp, initSpec := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(initSpec).To(Equal(spec))
 
 			p, attach := cm.ExecArgsForCall(0)
 			Expect(p).To(Equal(spec.Process))
@@ -113,7 +119,8 @@ var _ = Describe("Run", func() {
 
 			Expect(sm.SetSuccessArgsForCall(0)).To(Equal(unwrappedProcess))
 
-			pid, path, _ := mounter.MountArgsForCall(0)
+			id, pid, path, _ := mounter.MountArgsForCall(0)
+			Expect(id).To(Equal(containerId))
 			Expect(pid).To(Equal(99))
 			Expect(path).To(Equal("/some/path"))
 
@@ -156,8 +163,11 @@ var _ = Describe("Run", func() {
 			Expect(rd).To(Equal(rootDir))
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
-			Expect(cm.CreateArgsForCall(0)).To(Equal(spec))
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			createSpec, _, _ := cm.CreateArgsForCall(0)
+			Expect(createSpec).To(Equal(spec))
+			bp, initSpec := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(initSpec).To(Equal(spec))
 
 			p, attach := cm.ExecArgsForCall(0)
 			Expect(p).To(Equal(spec.Process))
@@ -167,7 +177,8 @@ var _ = Describe("Run", func() {
 
 			Expect(sm.SetSuccessArgsForCall(0)).To(Equal(unwrappedProcess))
 
-			pid, path, _ := mounter.MountArgsForCall(0)
+			id, pid, path, _ := mounter.MountArgsForCall(0)
+			Expect(id).To(Equal(containerId))
 			Expect(pid).To(Equal(99))
 			Expect(path).To(Equal("/some/path"))
 
@@ -185,9 +196,37 @@ var _ = Describe("Run", func() {
 			Expect(so).To(Equal(stdout))
 			Expect(se).To(Equal(stderr))
 
-			Expect(mounter.UnmountArgsForCall(0)).To(Equal(99))
+			id, pid = mounter.UnmountArgsForCall(0)
+			Expect(id).To(Equal(containerId))
+			Expect(pid).To(Equal(99))
+			Expect(sm.DeleteCallCount()).To(Equal(1))
+			force, _ := cm.DeleteArgsForCall(0)
+			Expect(force).To(BeFalse())
+		})
+
+		It("releases the container's lock while the process runs", func() {
+			wrappedProcess.AttachIOStub = attachAndCheck(func() {
+				Expect(sm.LockCallCount()).To(Equal(1))
+				Expect(sm.UnlockCallCount()).To(Equal(1))
+			})
+
+			_, err := r.Run(containerId, bundlePath, pidFile, io, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sm.LockCallCount()).To(Equal(2))
 			Expect(sm.DeleteCallCount()).To(Equal(1))
-			Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+		})
+
+		Context("the lock can't be retaken after the process exits", func() {
+			BeforeEach(func() {
+				sm.LockReturnsOnCall(1, errors.New("container is busy"))
+			})
+
+			It("returns the error without deleting the container", func() {
+				_, err := r.Run(containerId, bundlePath, pidFile, io, false)
+				Expect(err).To(MatchError("container is busy"))
+				Expect(cm.DeleteCallCount()).To(Equal(0))
+			})
 		})
 
 		Context("attaching io fails", func() {
@@ -202,9 +241,12 @@ var _ = Describe("Run", func() {
 				Expect(err).To(MatchError("couldn't attach"))
 				Expect(exitCode).To(Equal(-1))
 
-				Expect(mounter.UnmountArgsForCall(0)).To(Equal(99))
+				id, pid := mounter.UnmountArgsForCall(0)
+				Expect(id).To(Equal(containerId))
+				Expect(pid).To(Equal(99))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
-				Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeFalse())
 			})
 		})
 
@@ -218,9 +260,12 @@ var _ = Describe("Run", func() {
 				Expect(err).To(MatchError("couldn't get state"))
 				Expect(exitCode).To(Equal(1))
 
-				Expect(mounter.UnmountCallCount()).To(Equal(0))
+				id, pid := mounter.UnmountArgsForCall(0)
+				Expect(id).To(Equal(containerId))
+				Expect(pid).To(Equal(0))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
-				Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeFalse())
 			})
 		})
 
@@ -229,14 +274,17 @@ var _ = Describe("Run", func() {
 				sm.StateReturns(&specs.State{}, nil)
 			})
 
-			It("deletes the state and deletes the container", func() {
+			It("unmounts the volume by container id, deletes the state and deletes the container", func() {
 				exitCode, err := r.Run(containerId, bundlePath, pidFile, io, false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(exitCode).To(Equal(9))
 
-				Expect(mounter.UnmountCallCount()).To(Equal(0))
+				id, pid := mounter.UnmountArgsForCall(0)
+				Expect(id).To(Equal(containerId))
+				Expect(pid).To(Equal(0))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
-				Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeFalse())
 			})
 		})
 
@@ -252,7 +300,8 @@ var _ = Describe("Run", func() {
 
 				Expect(mounter.UnmountCallCount()).To(Equal(1))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
-				Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeFalse())
 			})
 		})
 
@@ -268,7 +317,8 @@ var _ = Describe("Run", func() {
 
 				Expect(mounter.UnmountCallCount()).To(Equal(1))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
-				Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeFalse())
 			})
 		})
 
@@ -284,7 +334,52 @@ var _ = Describe("Run", func() {
 
 				Expect(mounter.UnmountCallCount()).To(Equal(1))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
-				Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeFalse())
+			})
+		})
+	})
+
+	Context("the spec requests a terminal", func() {
+		var console *fakes.Console
+
+		BeforeEach(func() {
+			spec.Process.Terminal = true
+			cm.SpecReturns(spec, nil)
+			cm.ExecReturns(unwrappedProcess, nil)
+			unwrappedProcess.PidReturns(99)
+			processWrapper.WrapReturns(wrappedProcess)
+
+			state := &specs.State{Status: "stopped", Bundle: bundlePath, Pid: 99}
+			sm.StateReturns(state, nil)
+
+			console = &fakes.Console{}
+			console.SizeReturns(120, 40, nil)
+			io.Console = console
+		})
+
+		It("runs the init process with a console sized to match the caller's", func() {
+			_, err := r.Run(containerId, bundlePath, pidFile, io, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			p, _ := cm.ExecArgsForCall(0)
+			Expect(p.ConsoleSize).To(Equal(&specs.Box{Width: 120, Height: 40}))
+
+			Expect(console.SetRawCallCount()).To(Equal(1))
+			Expect(console.ResetCallCount()).To(Equal(1))
+			Expect(unwrappedProcess.ResizeConsoleCallCount()).To(BeNumerically(">=", 1))
+		})
+
+		Context("detach is true", func() {
+			It("deletes the container and returns an error", func() {
+				exitCode, err := r.Run(containerId, bundlePath, pidFile, io, true)
+				Expect(err).To(MatchError("cannot allocate a tty for a detached process"))
+				Expect(exitCode).To(Equal(1))
+
+				Expect(cm.ExecCallCount()).To(Equal(0))
+				Expect(sm.DeleteCallCount()).To(Equal(1))
+				force, _ := cm.DeleteArgsForCall(0)
+				Expect(force).To(BeFalse())
 			})
 		})
 	})
@@ -324,7 +419,7 @@ var _ = Describe("Run", func() {
 			Expect(exitCode).To(Equal(1))
 
 			Expect(cm.DeleteCallCount()).To(Equal(1))
-			force := cm.DeleteArgsForCall(0)
+			force, _ := cm.DeleteArgsForCall(0)
 			Expect(force).To(Equal(false))
 		})
 	})
@@ -421,3 +516,11 @@ var _ = Describe("Run", func() {
 		})
 	})
 })
+
+// attachAndCheck returns an AttachIO stub that calls check while attached
+func attachAndCheck(check func()) func(io.Reader, io.Writer, io.Writer) (int, error) {
+	return func(io.Reader, io.Writer, io.Writer) (int, error) {
+		check()
+		return 0, nil
+	}
+}
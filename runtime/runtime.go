@@ -5,13 +5,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime/config"
 	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	"github.com/Microsoft/hcsshim"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -20,8 +27,10 @@ import (
 
 //go:generate counterfeiter -o fakes/mounter.go --fake-name Mounter . Mounter
 type Mounter interface {
-	Mount(pid int, volumePath string, logger *logrus.Entry) error
-	Unmount(pid int) error
+	Mount(containerId string, pid int, volumePath string, logger *logrus.Entry) error
+	Unmount(containerId string, pid int) error
+	Mounts() ([]string, error)
+	LegacyMounts() ([]int, error)
 }
 
 //go:generate counterfeiter -o fakes/state_factory.go --fake-name StateFactory . StateFactory
@@ -31,16 +40,25 @@ type StateFactory interface {
 
 //go:generate counterfeiter -o fakes/state_manager.go --fake-name StateManager . StateManager
 type StateManager interface {
-	Initialize(string) error
+	Initialize(string, *specs.Spec) error
 	Delete() error
 	SetFailure() error
 	SetSuccess(hcs.Process) error
+	SetStopped() error
+	SetPaused(bool) error
+	SetResources(*specs.WindowsResources) error
+	Lock(time.Duration) error
+	Unlock() error
+	ExitCode() (int, error)
 	State() (*specs.State, error)
+	Load() (state.State, error)
+	OwnEndpointId() (string, error)
 }
 
 //go:generate counterfeiter -o fakes/container_factory.go --fake-name ContainerFactory . ContainerFactory
 type ContainerFactory interface {
 	NewManager(*logrus.Entry, *hcs.Client, string) ContainerManager
+	FileMounts() ([]string, error)
 }
 
 //go:generate counterfeiter -o fakes/container_manager.go --fake-name ContainerManager . ContainerManager
@@ -48,10 +66,18 @@ type ContainerManager interface {
 	Spec(string) (*specs.Spec, error)
 	CredentialSpecFromFile(string) (string, error)
 	CredentialSpecFromEnv([]string, string, string, string, string) (string, error)
-	Create(*specs.Spec, string) error
+	Create(*specs.Spec, string, string) error
 	Exec(*specs.Process, bool) (hcs.Process, error)
 	Stats() (container.Statistics, error)
-	Delete(bool) error
+	Kill(syscall.Signal, bool) error
+	Pause() error
+	Resume() error
+	Update(*specs.WindowsResources) error
+	Processes(bool) ([]container.ProcessInfo, error)
+	ResizeConsole(int, uint16, uint16) error
+	PreStop(int, time.Duration) error
+	Delete(bool, time.Duration) error
+	RemoveFileMounts() error
 }
 
 //go:generate counterfeiter -o fakes/process_wrapper.go --fake-name ProcessWrapper . ProcessWrapper
@@ -66,6 +92,12 @@ type WrappedProcess interface {
 	WritePIDFile(string) error
 }
 
+//go:generate counterfeiter -o fakes/hook_runner.go --fake-name HookRunner . HookRunner
+type HookRunner interface {
+	Run([]specs.Hook, *specs.State, *logrus.Entry) error
+	RunAll([]specs.Hook, *specs.State, *logrus.Entry)
+}
+
 //go:generate counterfeiter -o fakes/hcsquery.go --fake-name HCSQuery . HCSQuery
 type HCSQuery interface {
 	GetContainers(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
@@ -76,32 +108,137 @@ type Config struct {
 	UaaCredhubClientSecret string `json:"uaa_credhub_client_secret"`
 	CredhubEndpoint        string `json:"credhub_endpoint"`
 	CredhubCaCertificate   string `json:"credhub_ca_certificate"`
+
+	// MountRoot is where container volumes are mounted and files mounted
+	// into containers are linked on the host, c:\proc if unset
+	MountRoot string `json:"mount_root"`
+
+	// StopTimeoutSeconds is how long delete waits for each phase of stopping
+	// a running container if --timeout isn't passed, one minute if unset
+	StopTimeoutSeconds int `json:"stop_timeout_seconds"`
+
+	// OutputDrainTimeoutSeconds is how long run, start and exec wait for the
+	// output of a process that has exited to be copied, 11 seconds if unset
+	OutputDrainTimeoutSeconds int `json:"output_drain_timeout_seconds"`
+
+	// PreStop makes delete send CTRL_SHUTDOWN to a running container's init
+	// process, as if --pre-stop were passed
+	PreStop bool `json:"pre_stop"`
+}
+
+// ContainerSummary describes a single container in the output of List.
+// Orphan is set when only one of the state directory and the HCS compute
+// system exists for the container.
+type ContainerSummary struct {
+	ID      string    `json:"id"`
+	Pid     int       `json:"pid"`
+	Status  string    `json:"status"`
+	Bundle  string    `json:"bundle"`
+	Created time.Time `json:"created"`
+	Orphan  string    `json:"orphan,omitempty"`
+}
+
+const (
+	OrphanNoComputeSystem = "no compute system"
+	OrphanNoState         = "no state"
+)
+
+// GCReport is the output of GC: the ids of the orphaned compute systems, state
+// dirs, volume mounts and file mount dirs that were removed, and the pids of
+// the removed volume mounts made by versions of winc that keyed mounts by pid.
+// With DryRun set nothing is removed.
+type GCReport struct {
+	DryRun         bool     `json:"dry_run"`
+	ComputeSystems []string `json:"compute_systems"`
+	StateDirs      []string `json:"state_dirs"`
+	Mounts         []string `json:"mounts"`
+	LegacyMounts   []int    `json:"legacy_mounts"`
+	FileMounts     []string `json:"file_mounts"`
+	Errors         []string `json:"errors,omitempty"`
+}
+
+// ContainerState is the output of State: the OCI state plus any resource
+// limits that have been changed since the container was created
+type ContainerState struct {
+	specs.State
+	Resources *specs.WindowsResources `json:"resources,omitempty"`
+}
+
+const (
+	EventTypeStats = "stats"
+	EventTypeOOM   = "oom"
+	EventTypeExit  = "exit"
+)
+
+// Event is a single line of the streaming output of Events
+type Event struct {
+	Type string      `json:"type"`
+	ID   string      `json:"id"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+type ExitEventData struct {
+	Status int `json:"status"`
 }
 
 type IO struct {
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
+
+	// Console is the terminal Stdin and Stdout are attached to, if any. It is
+	// only used for processes that have a TTY.
+	Console Console
+}
+
+//go:generate counterfeiter -o fakes/console.go --fake-name Console . Console
+type Console interface {
+	Size() (width, height uint16, err error)
+	SetRaw() error
+	Reset() error
 }
 
+// consoleSizeInterval is how often an attached console is checked for size
+// changes, as Windows has no equivalent of SIGWINCH
+const consoleSizeInterval = 250 * time.Millisecond
+
+// gcLockTimeout is much shorter than lockTimeout, as a container that another
+// command is operating on is skipped by GC rather than waited for
+const gcLockTimeout = time.Second
+
+// defaultStopTimeout is how long each phase of stopping a running container
+// is waited for when neither --timeout nor the config file set a timeout
+const defaultStopTimeout = time.Minute
+
+// ownerPrefix is followed by the root dir in the owner of the compute systems
+// winc creates, so that list and gc can tell them apart from those of other
+// runtimes and other roots
+const ownerPrefix = "winc:"
+
+// lockTimeout is how long a command waits for another winc command operating
+// on the same container before giving up with a state.BusyError
+const lockTimeout = 30 * time.Second
+
 type Runtime struct {
 	stateFactory       StateFactory
 	containerFactory   ContainerFactory
 	mounter            Mounter
 	hcsQuery           HCSQuery
 	processWrapper     ProcessWrapper
+	hookRunner         HookRunner
 	rootDir            string
 	credentialSpecPath string
 	config             Config
 }
 
-func New(s StateFactory, c ContainerFactory, m Mounter, h HCSQuery, p ProcessWrapper, rootDir, credentialSpecPath string, config Config) *Runtime {
+func New(s StateFactory, c ContainerFactory, m Mounter, h HCSQuery, p ProcessWrapper, hr HookRunner, rootDir, credentialSpecPath string, config Config) *Runtime {
 	return &Runtime{
 		stateFactory:       s,
 		containerFactory:   c,
 		mounter:            m,
 		hcsQuery:           h,
 		processWrapper:     p,
+		hookRunner:         hr,
 		rootDir:            rootDir,
 		credentialSpecPath: credentialSpecPath,
 		config:             config,
@@ -121,14 +258,24 @@ func (r *Runtime) Create(containerId, bundlePath string) error {
 	wsc := winsyscall.WinSyscall{}
 	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
 
-	_, err := r.createContainer(cm, sm, bundlePath)
+	if err := sm.Lock(lockTimeout); err != nil {
+		return err
+	}
+	defer sm.Unlock()
+
+	_, err := r.createContainer(cm, sm, containerId, bundlePath, false, logger)
 	return err
 }
 
-func (r *Runtime) Delete(containerId string, force bool) error {
+func (r *Runtime) Delete(containerId string, force bool, timeout time.Duration, preStop bool) error {
+	timeout = r.stopTimeout(timeout)
+	preStop = preStop || r.config.PreStop
+
 	logger := logrus.WithFields(logrus.Fields{
 		"containerId": containerId,
 		"force":       force,
+		"timeout":     timeout,
+		"preStop":     preStop,
 	})
 	logger.Debug("deleting container")
 
@@ -153,9 +300,17 @@ func (r *Runtime) Delete(containerId string, force bool) error {
 
 		sm := r.stateFactory.NewManager(logger, &client, &wsc, containerIdToDelete, r.rootDir)
 
-		if err := r.deleteContainer(cm, sm, force, logger); err != nil {
+		if err := sm.Lock(lockTimeout); err != nil {
+			allErrors = append(allErrors, err.Error())
+			continue
+		}
+
+		if err := r.deleteContainer(cm, sm, containerIdToDelete, force, timeout, preStop, logger); err != nil {
 			allErrors = append(allErrors, err.Error())
 		}
+
+		// #nosec G104 - the lock is released when winc exits anyway
+		sm.Unlock()
 	}
 
 	if len(allErrors) == 0 {
@@ -165,36 +320,503 @@ func (r *Runtime) Delete(containerId string, force bool) error {
 	}
 }
 
-func (r *Runtime) Events(containerId string, output io.Writer, showStats bool) error {
+func (r *Runtime) Events(containerId string, output io.Writer, showStats bool, interval time.Duration) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"stats":       showStats,
+		"interval":    interval,
+	})
+	logger.Debug("retrieving container events and info")
+
+	client := hcs.Client{}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if !showStats {
+		if output == nil {
+			return errors.New("provided output is nil")
+		}
+		if interval <= 0 {
+			return fmt.Errorf("invalid interval: %s", interval)
+		}
+		return r.streamEvents(logger, cm, sm, containerId, output, interval)
+	}
+
+	stats, err := cm.Stats()
+	if err != nil {
+		return err
+	}
+
+	stats.Data.Memory.Limit, stats.Data.Pids.Limit = configuredLimits(logger, cm, sm)
+
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
+
+	statsJson, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = output.Write(statsJson)
+	return err
+}
+
+func (r *Runtime) Kill(containerId, signal string, all bool) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"signal":      signal,
+		"all":         all,
+	})
+	logger.Debug("killing container")
+
+	sig, err := parseSignal(signal)
+	if err != nil {
+		return err
+	}
+
+	client := hcs.Client{}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if err := sm.Lock(lockTimeout); err != nil {
+		return err
+	}
+	defer sm.Unlock()
+
+	ociState, err := sm.State()
+	if err != nil {
+		return err
+	}
+
+	if ociState.Status == specs.StateStopped {
+		return fmt.Errorf("cannot kill a container in the %s state", ociState.Status)
+	}
+
+	if err := cm.Kill(sig, all); err != nil {
+		return err
+	}
+
+	return sm.SetStopped()
+}
+
+func (r *Runtime) Pause(containerId string) error {
+	logger := logrus.WithField("containerId", containerId)
+	logger.Debug("pausing container")
+
+	client := hcs.Client{}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if err := sm.Lock(lockTimeout); err != nil {
+		return err
+	}
+	defer sm.Unlock()
+
+	ociState, err := sm.State()
+	if err != nil {
+		return err
+	}
+
+	if ociState.Status != specs.StateRunning {
+		return fmt.Errorf("cannot pause a container in the %s state", ociState.Status)
+	}
+
+	if err := cm.Pause(); err != nil {
+		return err
+	}
+
+	if err := sm.SetPaused(true); err != nil {
+		// #nosec G104 - we don't need to capture errors from resuming the container we failed to record as paused
+		cm.Resume()
+		return err
+	}
+
+	return nil
+}
+
+func (r *Runtime) Resume(containerId string) error {
+	logger := logrus.WithField("containerId", containerId)
+	logger.Debug("resuming container")
+
+	client := hcs.Client{}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if err := sm.Lock(lockTimeout); err != nil {
+		return err
+	}
+	defer sm.Unlock()
+
+	ociState, err := sm.State()
+	if err != nil {
+		return err
+	}
+
+	if ociState.Status != state.StatePaused {
+		return fmt.Errorf("cannot resume a container in the %s state", ociState.Status)
+	}
+
+	if err := cm.Resume(); err != nil {
+		return err
+	}
+
+	return sm.SetPaused(false)
+}
+
+func (r *Runtime) Update(containerId string, resources *specs.WindowsResources) error {
+	logger := logrus.WithField("containerId", containerId)
+	logger.Debug("updating container resources")
+
+	client := hcs.Client{}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if err := sm.Lock(lockTimeout); err != nil {
+		return err
+	}
+	defer sm.Unlock()
+
+	ociState, err := sm.State()
+	if err != nil {
+		return err
+	}
+
+	if ociState.Status == specs.StateStopped {
+		return fmt.Errorf("cannot update a container in the %s state", ociState.Status)
+	}
+
+	spec, err := cm.Spec(ociState.Bundle)
+	if err != nil {
+		return err
+	}
+
+	if err := config.ValidateResources(logger, spec, resources); err != nil {
+		return err
+	}
+
+	persisted, err := sm.Load()
+	if err != nil {
+		return err
+	}
+
+	current := persisted.Resources
+	if current == nil && spec.Windows != nil {
+		current = spec.Windows.Resources
+	}
+
+	// a limit in the update can conflict with one set earlier
+	merged := mergeResources(current, resources)
+	if err := config.ValidateResources(logger, spec, merged); err != nil {
+		return err
+	}
+
+	if err := cm.Update(resources); err != nil {
+		return err
+	}
+
+	return sm.SetResources(merged)
+}
+
+// Resize changes the console size of a process that was created with a TTY.
+// If pid is 0 the container's init process is resized.
+func (r *Runtime) Resize(containerId string, pid int, width, height uint16) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"pid":         pid,
+		"width":       width,
+		"height":      height,
+	})
+	logger.Debug("resizing process console")
+
+	if width == 0 || height == 0 {
+		return fmt.Errorf("invalid console size: %dx%d", width, height)
+	}
+
+	client := hcs.Client{}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	if pid == 0 {
+		wsc := winsyscall.WinSyscall{}
+		sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+		ociState, err := sm.State()
+		if err != nil {
+			return err
+		}
+
+		if ociState.Status != specs.StateRunning {
+			return fmt.Errorf("cannot resize a container in the %s state", ociState.Status)
+		}
+		pid = ociState.Pid
+	}
+
+	return cm.ResizeConsole(pid, width, height)
+}
+
+func (r *Runtime) List(format string, quiet bool, output io.Writer) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"format": format,
+		"quiet":  quiet,
+	})
+	logger.Debug("listing containers")
+
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid format option: %s", format)
+	}
+
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
+
+	containers, err := r.listContainers(logger)
+	if err != nil {
+		return err
+	}
+
+	if quiet {
+		for _, c := range containers {
+			fmt.Fprintln(output, c.ID)
+		}
+		return nil
+	}
+
+	if format == "json" {
+		return json.NewEncoder(output).Encode(containers)
+	}
+
+	w := tabwriter.NewWriter(output, 12, 1, 3, ' ', 0)
+	fmt.Fprint(w, "ID\tPID\tSTATUS\tBUNDLE\tCREATED\tORPHAN\n")
+	for _, c := range containers {
+		created := ""
+		if !c.Created.IsZero() {
+			created = c.Created.Format(time.RFC3339Nano)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", c.ID, c.Pid, c.Status, c.Bundle, created, c.Orphan)
+	}
+	return w.Flush()
+}
+
+// GC removes what crashed winc invocations leave behind: compute systems
+// without a state dir, state dirs without a compute system, and volume
+// mounts and file mount dirs that don't belong to a container. These are the
+// orphans reported by List.
+func (r *Runtime) GC(dryRun bool, output io.Writer) error {
+	logger := logrus.WithField("dryRun", dryRun)
+	logger.Debug("garbage collecting orphaned containers")
+
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
+
+	// mounts are listed before containers, so that the mount of a container
+	// started in between isn't mistaken for an orphan
+	mounts, err := r.mounter.Mounts()
+	if err != nil {
+		return err
+	}
+
+	legacyMounts, err := r.mounter.LegacyMounts()
+	if err != nil {
+		return err
+	}
+
+	fileMounts, err := r.containerFactory.FileMounts()
+	if err != nil {
+		return err
+	}
+
+	// the mount of a compute system that belongs to another root dir or
+	// runtime is still in use, even though that compute system isn't listed
+	computeSystems, err := r.hcsQuery.GetContainers(hcsshim.ComputeSystemQuery{Types: []string{"Container"}})
+	if err != nil {
+		return err
+	}
+
+	containers, err := r.listContainers(logger)
+	if err != nil {
+		return err
+	}
+
+	report := GCReport{
+		DryRun:         dryRun,
+		ComputeSystems: []string{},
+		StateDirs:      []string{},
+		Mounts:         []string{},
+		LegacyMounts:   []int{},
+		FileMounts:     []string{},
+	}
+
+	client := hcs.Client{}
+	wsc := winsyscall.WinSyscall{}
+
+	inUse := map[string]bool{}
+	for _, cs := range computeSystems {
+		inUse[cs.ID] = true
+	}
+	for _, c := range containers {
+		if c.Orphan == "" {
+			inUse[c.ID] = true
+			continue
+		}
+
+		cLogger := logger.WithFields(logrus.Fields{"containerId": c.ID, "orphan": c.Orphan})
+		cm := r.containerFactory.NewManager(cLogger, &client, c.ID)
+		sm := r.stateFactory.NewManager(cLogger, &client, &wsc, c.ID, r.rootDir)
+
+		reaped, err := r.reapContainer(cm, sm, c.ID, c.Orphan, dryRun, cLogger)
+		if err != nil {
+			cLogger.Error(err)
+			report.Errors = append(report.Errors, err.Error())
+		}
+
+		if !reaped {
+			inUse[c.ID] = true
+			continue
+		}
+		delete(inUse, c.ID)
+
+		if c.Orphan == OrphanNoState {
+			report.ComputeSystems = append(report.ComputeSystems, c.ID)
+		} else {
+			report.StateDirs = append(report.StateDirs, c.ID)
+		}
+	}
+
+	for _, id := range mounts {
+		if inUse[id] {
+			continue
+		}
+
+		if !dryRun {
+			if err := r.mounter.Unmount(id, 0); err != nil {
+				logger.WithField("containerId", id).Error(err)
+				report.Errors = append(report.Errors, err.Error())
+				continue
+			}
+		}
+
+		report.Mounts = append(report.Mounts, id)
+	}
+
+	// the mounter doesn't list legacy mounts whose process is still running,
+	// but a container that is still in use may have been stopped
+	containerPids := map[int]bool{}
+	for _, c := range containers {
+		if inUse[c.ID] {
+			containerPids[c.Pid] = true
+		}
+	}
+
+	for _, pid := range legacyMounts {
+		if containerPids[pid] {
+			continue
+		}
+
+		if !dryRun {
+			if err := r.mounter.Unmount("", pid); err != nil {
+				logger.WithField("pid", pid).Error(err)
+				report.Errors = append(report.Errors, err.Error())
+				continue
+			}
+		}
+
+		report.LegacyMounts = append(report.LegacyMounts, pid)
+	}
+
+	for _, id := range fileMounts {
+		if inUse[id] {
+			continue
+		}
+
+		cLogger := logger.WithField("containerId", id)
+		cm := r.containerFactory.NewManager(cLogger, &client, id)
+		sm := r.stateFactory.NewManager(cLogger, &client, &wsc, id, r.rootDir)
+
+		removed, err := r.removeFileMounts(cm, sm, dryRun, cLogger)
+		if err != nil {
+			cLogger.Error(err)
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+
+		if removed {
+			report.FileMounts = append(report.FileMounts, id)
+		}
+	}
+
+	if err := json.NewEncoder(output).Encode(report); err != nil {
+		return err
+	}
+
+	if len(report.Errors) != 0 {
+		return errors.New("failed to remove some orphans")
+	}
+
+	return nil
+}
+
+func (r *Runtime) Ps(containerId, format string, output io.Writer) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"containerId": containerId,
+		"format":      format,
 	})
-	logger.Debug("retrieving container events and info")
+	logger.Debug("listing container processes")
+
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid format option: %s", format)
+	}
+
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
 
 	client := hcs.Client{}
+	wsc := winsyscall.WinSyscall{}
 	cm := r.containerFactory.NewManager(logger, &client, containerId)
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
 
-	stats, err := cm.Stats()
+	// a container without state, e.g. one left behind by a crashed create,
+	// is listed as process isolated
+	persisted, err := sm.Load()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	processes, err := cm.Processes(persisted.Isolation == state.IsolationHyperV)
 	if err != nil {
 		return err
 	}
 
-	if showStats {
-		if output == nil {
-			return errors.New("provided output is nil")
-		}
-		statsJson, err := json.MarshalIndent(stats, "", "  ")
-		if err != nil {
-			return err
-		}
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].Pid < processes[j].Pid
+	})
 
-		_, err = output.Write(statsJson)
-		if err != nil {
-			return err
-		}
+	if format == "json" {
+		return json.NewEncoder(output).Encode(processes)
 	}
 
-	return nil
+	w := tabwriter.NewWriter(output, 12, 1, 3, ' ', 0)
+	fmt.Fprint(w, "PID\tIMAGE\tUSER\tSTART TIME\tCPU TIME\tWORKING SET\n")
+	for _, p := range processes {
+		startTime := ""
+		if !p.StartTime.IsZero() {
+			startTime = p.StartTime.Format(time.RFC3339)
+		}
+		cpuTime := time.Duration(p.CPUTime).Round(time.Millisecond)
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%dK\n", p.Pid, p.Image, p.User, startTime, cpuTime, p.WorkingSet/1024)
+	}
+	return w.Flush()
 }
 
 func (r *Runtime) Exec(containerId, processConfigFile, pidFile string, processOverrides *specs.Process, io IO, detach bool) (int, error) {
@@ -216,10 +838,23 @@ func (r *Runtime) Exec(containerId, processConfigFile, pidFile string, processOv
 	})
 	logger.Debug("executing process in container")
 
+	if err := prepareTerminal(processSpec, io, detach); err != nil {
+		return 1, err
+	}
+
 	client := hcs.Client{}
 	cm := r.containerFactory.NewManager(logger, &client, containerId)
 
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if err := sm.Lock(lockTimeout); err != nil {
+		return 1, err
+	}
+
 	p, err := cm.Exec(processSpec, !detach)
+	// #nosec G104 - the lock is released when winc exits anyway
+	sm.Unlock()
 	if err != nil {
 		return 1, err
 	}
@@ -231,6 +866,10 @@ func (r *Runtime) Exec(containerId, processConfigFile, pidFile string, processOv
 	}
 
 	if !detach {
+		if processSpec.Terminal && io.Console != nil {
+			defer attachConsole(p, io.Console, logger)()
+		}
+
 		s := make(chan os.Signal, 1)
 		wrappedProcess.SetInterrupt(s)
 		return wrappedProcess.AttachIO(io.Stdin, io.Stdout, io.Stderr)
@@ -254,12 +893,23 @@ func (r *Runtime) Run(containerId, bundlePath, pidFile string, io IO, detach boo
 	wsc := winsyscall.WinSyscall{}
 	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
 
-	spec, err := r.createContainer(cm, sm, bundlePath)
+	if err := sm.Lock(lockTimeout); err != nil {
+		return 1, err
+	}
+	defer sm.Unlock()
+
+	spec, err := r.createContainer(cm, sm, containerId, bundlePath, true, logger)
 	if err != nil {
 		return 1, err
 	}
 
-	process, err := r.startProcess(cm, sm, spec, pidFile, detach, logger)
+	if err := prepareTerminal(spec.Process, io, detach); err != nil {
+		// #nosec G104 - the terminal error is the one we want to report
+		r.deleteContainer(cm, sm, containerId, false, r.stopTimeout(0), false, logger)
+		return 1, err
+	}
+
+	process, err := r.startProcess(cm, sm, containerId, spec, pidFile, detach, logger)
 	if err != nil {
 		return 1, err
 	}
@@ -270,12 +920,27 @@ func (r *Runtime) Run(containerId, bundlePath, pidFile string, io IO, detach boo
 		return 1, err
 	}
 
+	r.runPoststartHooks(sm, spec, logger)
+
 	if !detach {
+		if spec.Process.Terminal && io.Console != nil {
+			defer attachConsole(process, io.Console, logger)()
+		}
+
 		s := make(chan os.Signal, 1)
 		wrappedProcess.SetInterrupt(s)
 
+		// other commands, e.g. kill and exec, need the lock while the
+		// process runs
+		// #nosec G104 - the lock is released when winc exits anyway
+		sm.Unlock()
+
 		exitCode, attachErr := wrappedProcess.AttachIO(io.Stdin, io.Stdout, io.Stderr)
-		deleteErr := r.deleteContainer(cm, sm, false, logger)
+
+		deleteErr := sm.Lock(lockTimeout)
+		if deleteErr == nil {
+			deleteErr = r.deleteContainer(cm, sm, containerId, false, r.stopTimeout(0), false, logger)
+		}
 		if attachErr != nil {
 			return exitCode, attachErr
 		}
@@ -303,6 +968,11 @@ func (r *Runtime) Start(containerId, pidFile string) error {
 	wsc := winsyscall.WinSyscall{}
 	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
 
+	if err := sm.Lock(lockTimeout); err != nil {
+		return err
+	}
+	defer sm.Unlock()
+
 	ociState, err := sm.State()
 	if err != nil {
 		return err
@@ -323,7 +993,7 @@ func (r *Runtime) Start(containerId, pidFile string) error {
 	* statemanager can do OpenProcess() to collect information about the process.
 	 */
 	bDetach := false
-	process, err := r.startProcess(cm, sm, spec, pidFile, bDetach, logger)
+	process, err := r.startProcess(cm, sm, containerId, spec, pidFile, bDetach, logger)
 	if err != nil {
 		return err
 	}
@@ -335,6 +1005,7 @@ func (r *Runtime) Start(containerId, pidFile string) error {
 		return err
 	}
 
+	r.runPoststartHooks(sm, spec, logger)
 	return nil
 }
 
@@ -352,12 +1023,18 @@ func (r *Runtime) State(containerId string, output io.Writer) error {
 		return errors.New("provided output is nil")
 	}
 
-	state, err := sm.State()
+	ociState, err := sm.State()
 	if err != nil {
 		return err
 	}
+	addOwnEndpoint(sm, ociState, logger)
+
+	containerState := ContainerState{State: *ociState}
+	if persisted, err := sm.Load(); err == nil {
+		containerState.Resources = persisted.Resources
+	}
 
-	stateJson, err := json.MarshalIndent(state, "", "  ")
+	stateJson, err := json.MarshalIndent(containerState, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -366,12 +1043,182 @@ func (r *Runtime) State(containerId string, output io.Writer) error {
 	return err
 }
 
-func (r *Runtime) createContainer(cm ContainerManager, sm StateManager, bundlePath string) (*specs.Spec, error) {
+func (r *Runtime) owner() string {
+	return ownerPrefix + filepath.Clean(r.rootDir)
+}
+
+// listContainers lists the containers with a state dir under the root dir,
+// along with the compute systems winc created for this root dir that have
+// none. Other compute systems on the host are ignored.
+func (r *Runtime) listContainers(logger *logrus.Entry) ([]ContainerSummary, error) {
+	computeSystems, err := r.hcsQuery.GetContainers(hcsshim.ComputeSystemQuery{Types: []string{"Container"}})
+	if err != nil {
+		return nil, err
+	}
+
+	unclaimed := map[string]hcsshim.ContainerProperties{}
+	for _, cs := range computeSystems {
+		unclaimed[cs.ID] = cs
+	}
+
+	entries, err := os.ReadDir(r.rootDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	client := hcs.Client{}
+	wsc := winsyscall.WinSyscall{}
+
+	containers := []ContainerSummary{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		id := entry.Name()
+
+		sm := r.stateFactory.NewManager(logger, &client, &wsc, id, r.rootDir)
+		persisted, err := sm.Load()
+		if err != nil {
+			logger.WithField("id", id).Error(err)
+		}
+
+		summary := ContainerSummary{
+			ID:      id,
+			Pid:     persisted.PID,
+			Bundle:  persisted.Bundle,
+			Created: persisted.Created,
+		}
+
+		if _, ok := unclaimed[id]; !ok {
+			summary.Status = string(specs.StateStopped)
+			summary.Orphan = OrphanNoComputeSystem
+		} else {
+			delete(unclaimed, id)
+
+			ociState, err := sm.State()
+			if err != nil {
+				logger.WithField("id", id).Error(err)
+				summary.Status = "unknown"
+			} else {
+				summary.Status = string(ociState.Status)
+			}
+		}
+
+		containers = append(containers, summary)
+	}
+
+	owned := map[string]bool{}
+	for _, c := range containers {
+		owned[c.ID] = true
+	}
+	for id, cs := range unclaimed {
+		if cs.Owner == r.owner() {
+			owned[id] = true
+		}
+	}
+
+	for id, cs := range unclaimed {
+		// containers sharing the network of another container are owned by it
+		if !owned[id] && !owned[cs.Owner] {
+			continue
+		}
+
+		status := specs.StateRunning
+		if cs.Stopped {
+			status = specs.StateStopped
+		}
+
+		containers = append(containers, ContainerSummary{
+			ID:     id,
+			Status: string(status),
+			Orphan: OrphanNoState,
+		})
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].ID < containers[j].ID
+	})
+
+	return containers, nil
+}
+
+// streamEvents writes an Event for the container's stats every interval until
+// the container stops, along with an oom event each time it runs out of memory
+// and a final exit event
+func (r *Runtime) streamEvents(logger *logrus.Entry, cm ContainerManager, sm StateManager, containerId string, output io.Writer, interval time.Duration) error {
+	encoder := json.NewEncoder(output)
+
+	ociState, err := sm.State()
+	if err != nil {
+		return err
+	}
+
+	memLimit, pidsLimit := configuredLimits(logger, cm, sm)
+	// HCS only enforces the memory limit to the nearest MB below it
+	oomThreshold := memLimit / 1024 / 1024 * 1024 * 1024
+
+	outOfMemory := false
+	for ociState.Status != specs.StateStopped {
+		stats, err := cm.Stats()
+		if _, ok := err.(*hcs.LowMemoryError); ok {
+			if !outOfMemory {
+				if err := encoder.Encode(Event{Type: EventTypeOOM, ID: containerId}); err != nil {
+					return err
+				}
+			}
+			outOfMemory = true
+		} else if err != nil {
+			return err
+		} else {
+			stats.Data.Memory.Limit = memLimit
+			stats.Data.Pids.Limit = pidsLimit
+			if err := encoder.Encode(Event{Type: EventTypeStats, ID: containerId, Data: stats.Data}); err != nil {
+				return err
+			}
+
+			atLimit := oomThreshold != 0 && stats.Data.Memory.Usage >= oomThreshold
+			if atLimit && !outOfMemory {
+				if err := encoder.Encode(Event{Type: EventTypeOOM, ID: containerId}); err != nil {
+					return err
+				}
+			}
+			outOfMemory = atLimit
+		}
+
+		time.Sleep(interval)
+
+		ociState, err = sm.State()
+		if _, ok := err.(*hcs.NotFoundError); ok {
+			logger.Debug("container was deleted while streaming events")
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+
+	exit := Event{Type: EventTypeExit, ID: containerId}
+	if exitCode, err := sm.ExitCode(); err == nil {
+		exit.Data = ExitEventData{Status: exitCode}
+	} else {
+		logger.WithError(err).Debug("exit code of init process is unavailable")
+	}
+
+	return encoder.Encode(exit)
+}
+
+// createContainer creates the container for the bundle. A tty is only
+// allowed for its init process when the caller will attach to it, as there's
+// nothing to hand the console to otherwise.
+func (r *Runtime) createContainer(cm ContainerManager, sm StateManager, containerId, bundlePath string, allowTerminal bool, logger *logrus.Entry) (*specs.Spec, error) {
 	spec, err := cm.Spec(bundlePath)
 	if err != nil {
 		return nil, err
 	}
 
+	if spec.Process != nil && spec.Process.Terminal && !allowTerminal {
+		return nil, errors.New("cannot allocate a tty for a container that is created without a console socket, use run instead")
+	}
+
 	var credentialSpec string
 	if r.config.UaaCredhubClientId != "" && r.config.UaaCredhubClientSecret != "" {
 		credentialSpec, err = cm.CredentialSpecFromEnv(spec.Process.Env, r.config.CredhubEndpoint, r.config.UaaCredhubClientId, r.config.UaaCredhubClientSecret, r.config.CredhubCaCertificate)
@@ -386,24 +1233,52 @@ func (r *Runtime) createContainer(cm ContainerManager, sm StateManager, bundlePa
 		}
 	}
 
-	if err := cm.Create(spec, credentialSpec); err != nil {
+	if err := cm.Create(spec, credentialSpec, r.owner()); err != nil {
 		return nil, err
 	}
 
-	if err := sm.Initialize(bundlePath); err != nil {
+	if err := sm.Initialize(bundlePath, spec); err != nil {
 		// #nosec G104 - we don't need to capture errors from deleting the thing that failed to initialize
-		cm.Delete(false)
+		cm.Delete(false, r.stopTimeout(0))
 		return nil, err
 	}
 
+	if spec.Hooks != nil {
+		ociState := &specs.State{
+			Version:     specs.Version,
+			ID:          containerId,
+			Status:      specs.StateCreating,
+			Bundle:      bundlePath,
+			Annotations: spec.Annotations,
+		}
+
+		// prestart hooks are deprecated in favour of createRuntime hooks, but
+		// run at the same point
+		hooks := []specs.Hook{}
+		hooks = append(hooks, spec.Hooks.Prestart...)
+		hooks = append(hooks, spec.Hooks.CreateRuntime...)
+		if err := r.hookRunner.Run(hooks, ociState, logger); err != nil {
+			// #nosec G104 - the hook failure is the error we want to report
+			sm.Delete()
+			// #nosec G104 - the hook failure is the error we want to report
+			cm.Delete(false, r.stopTimeout(0))
+			return nil, errors.Wrap(err, "running createRuntime hooks")
+		}
+	}
+
 	return spec, nil
 }
 
-func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bool, logger *logrus.Entry) error {
+// deleteContainer waits up to timeout for each phase of stopping the
+// container. With preStop, a running container's init process is signalled
+// before its volume is unmounted and the container is shut down.
+func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, containerId string, force bool, timeout time.Duration, preStop bool, logger *logrus.Entry) error {
 	var errs []string
 
 	ociState, err := sm.State()
-	if err != nil {
+	if _, ok := err.(*state.CorruptStateError); ok || os.IsNotExist(err) {
+		logger.WithError(err).Warn("deleting container with missing or corrupt state")
+	} else if err != nil {
 		logger.Error(err)
 
 		if _, ok := err.(*hcs.NotFoundError); ok {
@@ -414,8 +1289,23 @@ func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bo
 		}
 
 		errs = append(errs, err.Error())
-	} else if ociState.Pid != 0 {
-		if err := r.mounter.Unmount(ociState.Pid); err != nil {
+	}
+
+	if preStop && ociState != nil && ociState.Status == specs.StateRunning {
+		if err := cm.PreStop(ociState.Pid, timeout); err != nil {
+			logger.WithError(err).Warn("pre-stop failed, stopping the container anyway")
+		}
+	}
+
+	// mounts are found by container id, the pid is only needed for volumes
+	// mounted by older versions of winc
+	if ociState == nil || ociState.Annotations[state.IsolationAnnotation] != state.IsolationHyperV {
+		pid := 0
+		if ociState != nil {
+			pid = ociState.Pid
+		}
+
+		if err := r.mounter.Unmount(containerId, pid); err != nil {
 			logger.Error(err)
 			errs = append(errs, err.Error())
 		}
@@ -426,11 +1316,15 @@ func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bo
 		errs = append(errs, err.Error())
 	}
 
-	if err := cm.Delete(force); err != nil {
+	if err := cm.Delete(force, timeout); err != nil {
 		logger.Error(err)
 		errs = append(errs, err.Error())
 	}
 
+	if ociState != nil && ociState.Bundle != "" {
+		r.runPoststopHooks(cm, sm, ociState, logger)
+	}
+
 	if len(errs) != 0 {
 		return errors.New(strings.Join(errs, "\n"))
 	}
@@ -438,7 +1332,19 @@ func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bo
 	return nil
 }
 
-func (r *Runtime) startProcess(cm ContainerManager, sm StateManager, spec *specs.Spec, pidFile string, detach bool, logger *logrus.Entry) (hcs.Process, error) {
+func (r *Runtime) stopTimeout(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+
+	if r.config.StopTimeoutSeconds > 0 {
+		return time.Duration(r.config.StopTimeoutSeconds) * time.Second
+	}
+
+	return defaultStopTimeout
+}
+
+func (r *Runtime) startProcess(cm ContainerManager, sm StateManager, containerId string, spec *specs.Spec, pidFile string, detach bool, logger *logrus.Entry) (hcs.Process, error) {
 	process, err := cm.Exec(spec.Process, !detach)
 	if err != nil {
 		if cErr, ok := errors.Cause(err).(*container.CouldNotCreateProcessError); ok {
@@ -454,9 +1360,314 @@ func (r *Runtime) startProcess(cm ContainerManager, sm StateManager, spec *specs
 		return nil, err
 	}
 
-	if err := r.mounter.Mount(process.Pid(), spec.Root.Path, logger); err != nil {
+	// the volume of a Hyper-V container is attached to its utility VM and its
+	// init process pid is only meaningful inside the VM, so there is nothing
+	// to mount under c:\proc on the host
+	if hyperV(spec) {
+		logger.Debug("not mounting the volume of a hyper-v isolated container")
+		return process, nil
+	}
+
+	if err := r.mounter.Mount(containerId, process.Pid(), spec.Root.Path, logger); err != nil {
 		return nil, err
 	}
 
 	return process, nil
 }
+
+// reapContainer removes the compute system or state dir of an orphaned
+// container, returning whether it was (or in a dry run would be) removed. The
+// container is skipped if another command is operating on it, or if it is no
+// longer an orphan once locked.
+func (r *Runtime) reapContainer(cm ContainerManager, sm StateManager, containerId, orphan string, dryRun bool, logger *logrus.Entry) (bool, error) {
+	if dryRun {
+		return true, nil
+	}
+
+	if err := sm.Lock(gcLockTimeout); err != nil {
+		if _, ok := err.(*state.BusyError); ok {
+			logger.Debug("skipping container that another command is operating on")
+			return false, nil
+		}
+		return false, err
+	}
+	defer sm.Unlock()
+
+	switch orphan {
+	case OrphanNoState:
+		if _, err := sm.Load(); !os.IsNotExist(err) {
+			logger.Debug("skipping container that now has state")
+			return false, nil
+		}
+
+		if err := r.deleteContainer(cm, sm, containerId, true, r.stopTimeout(0), false, logger); err != nil {
+			return false, err
+		}
+	case OrphanNoComputeSystem:
+		if _, err := sm.State(); err == nil {
+			logger.Debug("skipping container that now has a compute system")
+			return false, nil
+		} else if _, ok := err.(*hcs.NotFoundError); !ok {
+			return false, err
+		}
+
+		if err := sm.Delete(); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// removeFileMounts removes the file mount dir of a container whose create
+// crashed before its compute system existed. The container's lock is taken
+// first, as a create that is still in progress holds it.
+func (r *Runtime) removeFileMounts(cm ContainerManager, sm StateManager, dryRun bool, logger *logrus.Entry) (bool, error) {
+	if dryRun {
+		return true, nil
+	}
+
+	if err := sm.Lock(gcLockTimeout); err != nil {
+		if _, ok := err.(*state.BusyError); ok {
+			logger.Debug("skipping file mounts of a container that another command is operating on")
+			return false, nil
+		}
+		return false, err
+	}
+	defer sm.Unlock()
+
+	if err := cm.RemoveFileMounts(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func hyperV(spec *specs.Spec) bool {
+	return spec.Windows != nil && spec.Windows.HyperV != nil
+}
+
+// runPoststartHooks runs the poststart hooks once the init process has been
+// started. Failures are only logged, as the OCI spec requires.
+func (r *Runtime) runPoststartHooks(sm StateManager, spec *specs.Spec, logger *logrus.Entry) {
+	if spec.Hooks == nil || len(spec.Hooks.Poststart) == 0 {
+		return
+	}
+
+	ociState, err := sm.State()
+	if err != nil {
+		logger.WithError(err).Warn("could not get state to run poststart hooks")
+		return
+	}
+	addOwnEndpoint(sm, ociState, logger)
+
+	r.hookRunner.RunAll(spec.Hooks.Poststart, ociState, logger)
+}
+
+// runPoststopHooks runs the poststop hooks after the container has been
+// deleted. Failures are only logged, since the container is already gone.
+func (r *Runtime) runPoststopHooks(cm ContainerManager, sm StateManager, ociState *specs.State, logger *logrus.Entry) {
+	spec, err := cm.Spec(ociState.Bundle)
+	if err != nil {
+		logger.WithError(err).Warn("could not load spec to run poststop hooks")
+		return
+	}
+
+	if spec == nil || spec.Hooks == nil || len(spec.Hooks.Poststop) == 0 {
+		return
+	}
+
+	stoppedState := *ociState
+	stoppedState.Status = specs.StateStopped
+	addOwnEndpoint(sm, &stoppedState, logger)
+
+	r.hookRunner.RunAll(spec.Hooks.Poststop, &stoppedState, logger)
+}
+
+// addOwnEndpoint adds the endpoint of a container with its own network to its
+// state, unless the state already records a shared one. It's an HNS query, so
+// it's only made for winc state and hooks rather than for every State.
+func addOwnEndpoint(sm StateManager, ociState *specs.State, logger *logrus.Entry) {
+	if _, ok := ociState.Annotations[state.EndpointIdAnnotation]; ok {
+		return
+	}
+
+	id, err := sm.OwnEndpointId()
+	if err != nil {
+		logger.WithError(err).Debug("container has no endpoint of its own")
+		return
+	}
+	if id == "" {
+		return
+	}
+
+	annotations := map[string]string{}
+	for k, v := range ociState.Annotations {
+		annotations[k] = v
+	}
+	annotations[state.EndpointIdAnnotation] = id
+	ociState.Annotations = annotations
+}
+
+// prepareTerminal checks that a process with a TTY will be attached to, and
+// sizes its console to match the caller's
+func prepareTerminal(process *specs.Process, io IO, detach bool) error {
+	if !process.Terminal {
+		return nil
+	}
+
+	if detach {
+		return errors.New("cannot allocate a tty for a detached process")
+	}
+
+	if process.ConsoleSize == nil && io.Console != nil {
+		if width, height, err := io.Console.Size(); err == nil {
+			process.ConsoleSize = &specs.Box{Width: uint(width), Height: uint(height)}
+		}
+	}
+
+	return nil
+}
+
+// attachConsole puts the console into raw mode and resizes the process's
+// console whenever it changes size. The returned function stops watching and
+// restores the console.
+func attachConsole(process hcs.Process, console Console, logger *logrus.Entry) func() {
+	if err := console.SetRaw(); err != nil {
+		logger.WithError(err).Warn("could not set console to raw mode")
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(consoleSizeInterval)
+		defer ticker.Stop()
+
+		var lastWidth, lastHeight uint16
+		for {
+			width, height, err := console.Size()
+			if err == nil && (width != lastWidth || height != lastHeight) {
+				if err := process.ResizeConsole(width, height); err != nil {
+					logger.WithError(err).Debug("could not resize process console")
+				}
+				lastWidth, lastHeight = width, height
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		if err := console.Reset(); err != nil {
+			logger.WithError(err).Warn("could not restore console")
+		}
+	}
+}
+
+// parseSignal accepts a signal in any of the forms "SIGTERM", "TERM" or "15"
+func parseSignal(signal string) (syscall.Signal, error) {
+	if n, err := strconv.Atoi(signal); err == nil {
+		sig := syscall.Signal(n)
+		if sig != syscall.SIGTERM && sig != syscall.SIGKILL {
+			return 0, &container.UnsupportedSignalError{Signal: signal}
+		}
+		return sig, nil
+	}
+
+	switch strings.TrimPrefix(strings.ToUpper(signal), "SIG") {
+	case "TERM":
+		return syscall.SIGTERM, nil
+	case "KILL":
+		return syscall.SIGKILL, nil
+	default:
+		return 0, &container.UnsupportedSignalError{Signal: signal}
+	}
+}
+
+// mergeResources returns the limits in base with any set in update applied
+// on top, without modifying either
+func mergeResources(base, update *specs.WindowsResources) *specs.WindowsResources {
+	merged := &specs.WindowsResources{}
+
+	if base != nil {
+		if base.Memory != nil {
+			memory := *base.Memory
+			merged.Memory = &memory
+		}
+		if base.CPU != nil {
+			cpu := *base.CPU
+			merged.CPU = &cpu
+		}
+		if base.Storage != nil {
+			storage := *base.Storage
+			merged.Storage = &storage
+		}
+	}
+
+	if update.Memory != nil && update.Memory.Limit != nil {
+		if merged.Memory == nil {
+			merged.Memory = &specs.WindowsMemoryResources{}
+		}
+		merged.Memory.Limit = update.Memory.Limit
+	}
+
+	if update.CPU != nil {
+		if merged.CPU == nil {
+			merged.CPU = &specs.WindowsCPUResources{}
+		}
+		if update.CPU.Shares != nil {
+			merged.CPU.Shares = update.CPU.Shares
+		}
+		if update.CPU.Maximum != nil {
+			merged.CPU.Maximum = update.CPU.Maximum
+		}
+	}
+
+	return merged
+}
+
+// configuredLimits returns the memory limit the container is running with,
+// taking any live update into account, and the pid limit from its spec. HCS
+// cannot enforce a pid limit, so that is only reported for information.
+func configuredLimits(logger *logrus.Entry, cm ContainerManager, sm StateManager) (uint64, uint64) {
+	persisted, err := sm.Load()
+	if err != nil {
+		logger.WithError(err).Debug("could not load state to determine limits")
+		return 0, 0
+	}
+
+	var memory, pids uint64
+	spec, err := cm.Spec(persisted.Bundle)
+	if err != nil {
+		logger.WithError(err).Debug("could not load spec to determine limits")
+	} else if spec != nil {
+		if spec.Windows != nil {
+			memory = memoryLimit(spec.Windows.Resources)
+		}
+		if spec.Linux != nil && spec.Linux.Resources != nil && spec.Linux.Resources.Pids != nil && spec.Linux.Resources.Pids.Limit > 0 {
+			pids = uint64(spec.Linux.Resources.Pids.Limit)
+		}
+	}
+
+	if persisted.Resources != nil {
+		memory = memoryLimit(persisted.Resources)
+	}
+
+	return memory, pids
+}
+
+func memoryLimit(resources *specs.WindowsResources) uint64 {
+	if resources == nil || resources.Memory == nil || resources.Memory.Limit == nil {
+		return 0
+	}
+
+	return *resources.Memory.Limit
+}
@@ -6,6 +6,7 @@ import (
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,6 +28,7 @@ var _ = Describe("Start", func() {
 		containerFactory   *fakes.ContainerFactory
 		cm                 *fakes.ContainerManager
 		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
 		wrappedProcess     *fakes.WrappedProcess
 		unwrappedProcess   *hcsfakes.Process
 		hcsQuery           *fakes.HCSQuery
@@ -43,6 +45,7 @@ var _ = Describe("Start", func() {
 		containerFactory = &fakes.ContainerFactory{}
 		cm = &fakes.ContainerManager{}
 		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
 		wrappedProcess = &fakes.WrappedProcess{}
 		unwrappedProcess = &hcsfakes.Process{}
 		spec = &specs.Spec{}
@@ -59,7 +62,7 @@ var _ = Describe("Start", func() {
 		containerFactory.NewManagerReturns(cm)
 
 		config := runtime.Config{}
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 	})
 
 	Context("starting the container succeeds", func() {
@@ -101,7 +104,8 @@ var _ = Describe("Start", func() {
 
 			Expect(sm.SetSuccessArgsForCall(0)).To(Equal(unwrappedProcess))
 
-			pid, path, _ := mounter.MountArgsForCall(0)
+			id, pid, path, _ := mounter.MountArgsForCall(0)
+			Expect(id).To(Equal(containerId))
 			Expect(pid).To(Equal(99))
 			Expect(path).To(Equal("/some/path"))
 
@@ -111,6 +115,26 @@ var _ = Describe("Start", func() {
 		})
 	})
 
+	Context("the container is hyper-v isolated", func() {
+		BeforeEach(func() {
+			spec.Windows = &specs.Windows{HyperV: &specs.WindowsHyperV{}}
+
+			sm.StateReturns(&specs.State{Status: "created", Bundle: bundlePath}, nil)
+			cm.SpecReturns(spec, nil)
+			cm.ExecReturns(unwrappedProcess, nil)
+			unwrappedProcess.PidReturns(99)
+			processWrapper.WrapReturns(wrappedProcess)
+		})
+
+		It("does not mount the volume on the host", func() {
+			Expect(r.Start(containerId, pidFile)).To(Succeed())
+
+			Expect(sm.SetSuccessArgsForCall(0)).To(Equal(unwrappedProcess))
+			Expect(mounter.MountCallCount()).To(Equal(0))
+			Expect(wrappedProcess.WritePIDFileArgsForCall(0)).To(Equal(pidFile))
+		})
+	})
+
 	Context("the state of the container is not 'created'", func() {
 		BeforeEach(func() {
 			state := &specs.State{Status: "running", Bundle: bundlePath}
@@ -237,4 +261,64 @@ var _ = Describe("Start", func() {
 			Expect(err).To(MatchError("couldn't write pidfile"))
 		})
 	})
+
+	Context("the spec has poststart hooks", func() {
+		var runningState *specs.State
+
+		BeforeEach(func() {
+			spec.Hooks = &specs.Hooks{
+				Poststart: []specs.Hook{{Path: "C:\\poststart.exe"}},
+			}
+			spec.Annotations = map[string]string{"some-key": "some-value"}
+			runningState = &specs.State{ID: containerId, Status: specs.StateRunning, Bundle: bundlePath, Pid: 99, Annotations: map[string]string{
+				"some-key":                "some-value",
+				state.IsolationAnnotation: state.IsolationProcess,
+			}}
+			sm.StateReturnsOnCall(0, &specs.State{Status: "created", Bundle: bundlePath}, nil)
+			sm.StateReturnsOnCall(1, runningState, nil)
+
+			cm.SpecReturns(spec, nil)
+			cm.ExecReturns(unwrappedProcess, nil)
+			unwrappedProcess.PidReturns(99)
+			processWrapper.WrapReturns(wrappedProcess)
+		})
+
+		It("runs them with the running state, including the winc annotations, after the process is started", func() {
+			Expect(r.Start(containerId, pidFile)).To(Succeed())
+
+			Expect(wrappedProcess.WritePIDFileCallCount()).To(Equal(1))
+			Expect(hookRunner.RunAllCallCount()).To(Equal(1))
+			hooks, state, _ := hookRunner.RunAllArgsForCall(0)
+			Expect(hooks).To(Equal(spec.Hooks.Poststart))
+			Expect(state).To(Equal(runningState))
+			Expect(hookRunner.RunCallCount()).To(Equal(0))
+		})
+
+		Context("the container has an endpoint of its own", func() {
+			BeforeEach(func() {
+				sm.OwnEndpointIdReturns("own-endpoint-id", nil)
+			})
+
+			It("includes it in the state passed to the hooks", func() {
+				Expect(r.Start(containerId, pidFile)).To(Succeed())
+
+				_, state, _ := hookRunner.RunAllArgsForCall(0)
+				Expect(state.Annotations).To(HaveKeyWithValue("org.cloudfoundry.winc.endpoint-id", "own-endpoint-id"))
+				Expect(runningState.Annotations).NotTo(HaveKey("org.cloudfoundry.winc.endpoint-id"))
+			})
+		})
+
+		Context("getting the running state fails", func() {
+			BeforeEach(func() {
+				sm.StateReturnsOnCall(1, nil, errors.New("couldn't get state"))
+			})
+
+			It("leaves the container running and skips the hooks", func() {
+				Expect(r.Start(containerId, pidFile)).To(Succeed())
+				Expect(hookRunner.RunAllCallCount()).To(Equal(0))
+				Expect(cm.KillCallCount()).To(Equal(0))
+				Expect(sm.SetStoppedCallCount()).To(Equal(0))
+			})
+		})
+	})
 })
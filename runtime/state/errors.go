@@ -0,0 +1,30 @@
+package state
+
+import "fmt"
+
+// CorruptStateError is returned when state.json cannot be parsed or does not
+// describe a valid container, e.g. because winc crashed while writing it
+type CorruptStateError struct {
+	Id     string
+	Reason string
+}
+
+func (e *CorruptStateError) Error() string {
+	return fmt.Sprintf("state for container %s is corrupt: %s", e.Id, e.Reason)
+}
+
+type BusyError struct {
+	Id string
+}
+
+func (e *BusyError) Error() string {
+	return fmt.Sprintf("container %s is busy: another winc command is operating on it", e.Id)
+}
+
+type processNotFoundError struct {
+	pid int
+}
+
+func (e *processNotFoundError) Error() string {
+	return fmt.Sprintf("process %d not found", e.pid)
+}
@@ -4,6 +4,7 @@ package fakes
 import (
 	"sync"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime/state"
 	"github.com/Microsoft/hcsshim"
 )
@@ -22,6 +23,32 @@ type HCSClient struct {
 		result1 hcsshim.ContainerProperties
 		result2 error
 	}
+	GetHNSEndpointByNameStub        func(string) (*hcsshim.HNSEndpoint, error)
+	getHNSEndpointByNameMutex       sync.RWMutex
+	getHNSEndpointByNameArgsForCall []struct {
+		arg1 string
+	}
+	getHNSEndpointByNameReturns struct {
+		result1 *hcsshim.HNSEndpoint
+		result2 error
+	}
+	getHNSEndpointByNameReturnsOnCall map[int]struct {
+		result1 *hcsshim.HNSEndpoint
+		result2 error
+	}
+	OpenContainerStub        func(string) (hcs.Container, error)
+	openContainerMutex       sync.RWMutex
+	openContainerArgsForCall []struct {
+		arg1 string
+	}
+	openContainerReturns struct {
+		result1 hcs.Container
+		result2 error
+	}
+	openContainerReturnsOnCall map[int]struct {
+		result1 hcs.Container
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -90,11 +117,143 @@ func (fake *HCSClient) GetContainerPropertiesReturnsOnCall(i int, result1 hcsshi
 	}{result1, result2}
 }
 
+func (fake *HCSClient) GetHNSEndpointByName(arg1 string) (*hcsshim.HNSEndpoint, error) {
+	fake.getHNSEndpointByNameMutex.Lock()
+	ret, specificReturn := fake.getHNSEndpointByNameReturnsOnCall[len(fake.getHNSEndpointByNameArgsForCall)]
+	fake.getHNSEndpointByNameArgsForCall = append(fake.getHNSEndpointByNameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetHNSEndpointByNameStub
+	fakeReturns := fake.getHNSEndpointByNameReturns
+	fake.recordInvocation("GetHNSEndpointByName", []interface{}{arg1})
+	fake.getHNSEndpointByNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) GetHNSEndpointByNameCallCount() int {
+	fake.getHNSEndpointByNameMutex.RLock()
+	defer fake.getHNSEndpointByNameMutex.RUnlock()
+	return len(fake.getHNSEndpointByNameArgsForCall)
+}
+
+func (fake *HCSClient) GetHNSEndpointByNameCalls(stub func(string) (*hcsshim.HNSEndpoint, error)) {
+	fake.getHNSEndpointByNameMutex.Lock()
+	defer fake.getHNSEndpointByNameMutex.Unlock()
+	fake.GetHNSEndpointByNameStub = stub
+}
+
+func (fake *HCSClient) GetHNSEndpointByNameArgsForCall(i int) string {
+	fake.getHNSEndpointByNameMutex.RLock()
+	defer fake.getHNSEndpointByNameMutex.RUnlock()
+	argsForCall := fake.getHNSEndpointByNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HCSClient) GetHNSEndpointByNameReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.getHNSEndpointByNameMutex.Lock()
+	defer fake.getHNSEndpointByNameMutex.Unlock()
+	fake.GetHNSEndpointByNameStub = nil
+	fake.getHNSEndpointByNameReturns = struct {
+		result1 *hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) GetHNSEndpointByNameReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.getHNSEndpointByNameMutex.Lock()
+	defer fake.getHNSEndpointByNameMutex.Unlock()
+	fake.GetHNSEndpointByNameStub = nil
+	if fake.getHNSEndpointByNameReturnsOnCall == nil {
+		fake.getHNSEndpointByNameReturnsOnCall = make(map[int]struct {
+			result1 *hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.getHNSEndpointByNameReturnsOnCall[i] = struct {
+		result1 *hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) OpenContainer(arg1 string) (hcs.Container, error) {
+	fake.openContainerMutex.Lock()
+	ret, specificReturn := fake.openContainerReturnsOnCall[len(fake.openContainerArgsForCall)]
+	fake.openContainerArgsForCall = append(fake.openContainerArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.OpenContainerStub
+	fakeReturns := fake.openContainerReturns
+	fake.recordInvocation("OpenContainer", []interface{}{arg1})
+	fake.openContainerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) OpenContainerCallCount() int {
+	fake.openContainerMutex.RLock()
+	defer fake.openContainerMutex.RUnlock()
+	return len(fake.openContainerArgsForCall)
+}
+
+func (fake *HCSClient) OpenContainerCalls(stub func(string) (hcs.Container, error)) {
+	fake.openContainerMutex.Lock()
+	defer fake.openContainerMutex.Unlock()
+	fake.OpenContainerStub = stub
+}
+
+func (fake *HCSClient) OpenContainerArgsForCall(i int) string {
+	fake.openContainerMutex.RLock()
+	defer fake.openContainerMutex.RUnlock()
+	argsForCall := fake.openContainerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HCSClient) OpenContainerReturns(result1 hcs.Container, result2 error) {
+	fake.openContainerMutex.Lock()
+	defer fake.openContainerMutex.Unlock()
+	fake.OpenContainerStub = nil
+	fake.openContainerReturns = struct {
+		result1 hcs.Container
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) OpenContainerReturnsOnCall(i int, result1 hcs.Container, result2 error) {
+	fake.openContainerMutex.Lock()
+	defer fake.openContainerMutex.Unlock()
+	fake.OpenContainerStub = nil
+	if fake.openContainerReturnsOnCall == nil {
+		fake.openContainerReturnsOnCall = make(map[int]struct {
+			result1 hcs.Container
+			result2 error
+		})
+	}
+	fake.openContainerReturnsOnCall[i] = struct {
+		result1 hcs.Container
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *HCSClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getContainerPropertiesMutex.RLock()
 	defer fake.getContainerPropertiesMutex.RUnlock()
+	fake.getHNSEndpointByNameMutex.RLock()
+	defer fake.getHNSEndpointByNameMutex.RUnlock()
+	fake.openContainerMutex.RLock()
+	defer fake.openContainerMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
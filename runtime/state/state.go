@@ -2,38 +2,85 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 
+	"code.cloudfoundry.org/filelock"
 	"code.cloudfoundry.org/winc/hcs"
 	"github.com/Microsoft/hcsshim"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
 )
 
 const stateFile = "state.json"
+
+// StateVersion is the version of the state.json format written by this
+// version of winc. State files without a version predate versioning and are
+// treated as version 0.
+const StateVersion = 1
 const STILL_ACTIVE_EXIT_CODE = uint32(259)
 
+// IsolationAnnotation is reported in the state of every container and is
+// either IsolationProcess or IsolationHyperV
+const (
+	IsolationAnnotation = "org.cloudfoundry.winc.isolation"
+	IsolationProcess    = "process"
+	IsolationHyperV     = "hyperv"
+)
+
+// winc adds these annotations to the ones from config.json so that tooling
+// can correlate a container with its HCS and HNS objects. They take
+// precedence over annotations of the same name in config.json.
+const (
+	HCSSystemIdAnnotation = "org.cloudfoundry.winc.hcs-system-id"
+	EndpointIdAnnotation  = "org.cloudfoundry.winc.endpoint-id"
+	VolumePathAnnotation  = "org.cloudfoundry.winc.volume-path"
+	CreatedAnnotation     = "org.cloudfoundry.winc.created"
+)
+
+// StatePaused is not defined by the OCI runtime spec, but is reported by
+// runc for frozen containers, so we use the same value
+const StatePaused specs.ContainerState = "paused"
+
 type Manager struct {
 	logger      *logrus.Entry
 	hcsClient   HCSClient
 	sc          WinSyscall
 	containerId string
 	rootDir     string
+	lock        filelock.LockedFile
 }
 
 type State struct {
+	Version    int              `json:"version"`
 	Bundle     string           `json:"bundle"`
 	PID        int              `json:"pid"`
 	StartTime  syscall.Filetime `json:"start_time"`
 	ExecFailed bool             `json:"exec_failed"`
+	Stopped    bool             `json:"stopped"`
+	Paused     bool             `json:"paused"`
+	Created    time.Time        `json:"created"`
+	Isolation  string           `json:"isolation,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+	VolumePath  string            `json:"volume_path,omitempty"`
+	EndpointId  string            `json:"endpoint_id,omitempty"`
+
+	// Resources holds the effective limits after a live update; nil if the
+	// container still has the limits it was created with
+	Resources *specs.WindowsResources `json:"resources,omitempty"`
 }
 
 //go:generate counterfeiter -o fakes/hcsclient.go --fake-name HCSClient . HCSClient
 type HCSClient interface {
 	GetContainerProperties(string) (hcsshim.ContainerProperties, error)
+	GetHNSEndpointByName(string) (*hcsshim.HNSEndpoint, error)
+	OpenContainer(string) (hcs.Container, error)
 }
 
 //go:generate counterfeiter -o fakes/winsyscall.go --fake-name WinSyscall . WinSyscall
@@ -54,15 +101,100 @@ func New(logger *logrus.Entry, hcsClient HCSClient, winSyscall WinSyscall, id, r
 	}
 }
 
-func (m *Manager) Initialize(bundlePath string) error {
+// Initialize records the state of a newly created container, along with the
+// isolation, annotations, volume and network endpoint from its spec
+func (m *Manager) Initialize(bundlePath string, spec *specs.Spec) error {
+	state := State{
+		Bundle:      bundlePath,
+		Created:     time.Now().UTC(),
+		Isolation:   IsolationProcess,
+		Annotations: spec.Annotations,
+	}
+
+	if spec.Windows != nil && spec.Windows.HyperV != nil {
+		state.Isolation = IsolationHyperV
+	}
+
+	if spec.Root != nil {
+		state.VolumePath = spec.Root.Path
+	}
+
+	if spec.Windows != nil && spec.Windows.Network != nil && spec.Windows.Network.NetworkSharedContainerName != "" {
+		endpoint, err := m.hcsClient.GetHNSEndpointByName(spec.Windows.Network.NetworkSharedContainerName)
+		if err != nil {
+			return err
+		}
+		state.EndpointId = endpoint.Id
+	}
+
 	if err := os.MkdirAll(m.stateDir(), 0755); err != nil {
 		return err
 	}
 
-	state := State{Bundle: bundlePath}
 	return m.writeState(state)
 }
 
+// Lock takes an exclusive lock on the container so that concurrent winc
+// invocations don't interleave changes to it, returning a BusyError if the
+// lock isn't acquired within the timeout. The lock file sits next to the
+// state dir rather than in it, so the state dir can be deleted while the
+// lock is held.
+func (m *Manager) Lock(timeout time.Duration) error {
+	type result struct {
+		file filelock.LockedFile
+		err  error
+	}
+
+	locked := make(chan result, 1)
+	go func() {
+		file, err := filelock.NewLocker(m.lockFile()).Open()
+		locked <- result{file: file, err: err}
+	}()
+
+	select {
+	case r := <-locked:
+		if r.err != nil {
+			return r.err
+		}
+		m.lock = r.file
+		return nil
+	case <-time.After(timeout):
+		// release the lock if it's acquired after we've given up on it
+		go func() {
+			if r := <-locked; r.err == nil {
+				// #nosec G104 - nothing is waiting on this lock any more
+				r.file.Close()
+			}
+		}()
+		return &BusyError{Id: m.containerId}
+	}
+}
+
+// Unlock releases the lock taken by Lock. Once the container's state dir has
+// been deleted the lock file is removed too, unless another winc command
+// already has it open.
+func (m *Manager) Unlock() error {
+	if m.lock == nil {
+		return nil
+	}
+
+	err := m.lock.Close()
+	m.lock = nil
+
+	if _, statErr := os.Stat(m.stateDir()); os.IsNotExist(statErr) {
+		// #nosec G104 - the file is in use if this fails, and will be removed by whoever is using it
+		os.Remove(m.lockFile())
+	}
+
+	return err
+}
+
+// Load returns the state persisted in the state directory without consulting
+// HCS, so it works even when the compute system no longer exists
+func (m *Manager) Load() (State, error) {
+	return m.loadState()
+}
+
 func (m *Manager) Delete() error {
 	return os.RemoveAll(m.stateDir())
 }
@@ -79,6 +211,36 @@ func (m *Manager) SetFailure() error {
 	return m.writeState(state)
 }
 
+func (m *Manager) SetStopped() error {
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	state.Stopped = true
+	return m.writeState(state)
+}
+
+func (m *Manager) SetPaused(paused bool) error {
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	state.Paused = paused
+	return m.writeState(state)
+}
+
+func (m *Manager) SetResources(resources *specs.WindowsResources) error {
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	state.Resources = resources
+	return m.writeState(state)
+}
+
 func (m *Manager) SetSuccess(proc hcs.Process) error {
 	state, err := m.loadState()
 	if err != nil {
@@ -97,6 +259,25 @@ func (m *Manager) SetSuccess(proc hcs.Process) error {
 	 */
 	// time.Sleep(10 * time.Second)
 
+	// the init process of a hyper-v isolated container runs in its utility
+	// VM, where the host can't open it, so its start time comes from HCS
+	if state.Isolation == IsolationHyperV {
+		item, err := m.hyperVProcess(state.PID)
+		if err != nil {
+			retErr := fmt.Errorf("ProcessList: %s", err.Error())
+			m.logger.Error(retErr)
+			state.ExecFailed = true
+			writeErr := m.writeState(state)
+			if writeErr != nil {
+				m.logger.Error(writeErr)
+			}
+			return retErr
+		}
+
+		state.StartTime = syscall.NsecToFiletime(item.CreateTimestamp.UnixNano())
+		return m.writeState(state)
+	}
+
 	h, err := m.sc.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(state.PID))
 	if err != nil {
 		retErr := fmt.Errorf("OpenProcess: %s", err.Error())
@@ -149,19 +330,106 @@ func (m *Manager) State() (*specs.State, error) {
 			logrus.Debugf("state failed to retrieve userProgramStatus for %s: %s\n", m.containerId, err.Error())
 			return nil, err
 		}
+
+		if status == specs.StateRunning && state.Paused {
+			status = StatePaused
+		}
 	}
 
 	return &specs.State{
-		Version: specs.Version,
-		ID:      m.containerId,
-		Status:  status,
-		Bundle:  state.Bundle,
-		Pid:     state.PID,
+		Version:     specs.Version,
+		ID:          m.containerId,
+		Status:      status,
+		Bundle:      state.Bundle,
+		Pid:         state.PID,
+		Annotations: annotations(cp, state),
 	}, nil
 }
 
+// OwnEndpointId returns the id of the endpoint winc-network created for a
+// container with its own network, or "" if it has none. winc-network creates
+// it after the container, so it isn't in state.json, and looking it up is an
+// HNS query State doesn't make.
+func (m *Manager) OwnEndpointId() (string, error) {
+	endpoint, err := m.hcsClient.GetHNSEndpointByName(m.containerId)
+	if err != nil {
+		return "", err
+	}
+	if endpoint == nil {
+		return "", nil
+	}
+
+	return endpoint.Id, nil
+}
+
+func annotations(cp hcsshim.ContainerProperties, state State) map[string]string {
+	annotations := map[string]string{}
+	for k, v := range state.Annotations {
+		annotations[k] = v
+	}
+
+	annotations[IsolationAnnotation] = state.Isolation
+	annotations[HCSSystemIdAnnotation] = cp.ID
+
+	if state.EndpointId != "" {
+		annotations[EndpointIdAnnotation] = state.EndpointId
+	}
+	if state.VolumePath != "" {
+		annotations[VolumePathAnnotation] = state.VolumePath
+	}
+	if !state.Created.IsZero() {
+		annotations[CreatedAnnotation] = state.Created.Format(time.RFC3339Nano)
+	}
+
+	return annotations
+}
+
+// ExitCode returns the exit code of the container's init process. This is
+// only available while the process can still be opened, i.e. until the last
+// handle to it is closed.
+func (m *Manager) ExitCode() (int, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return 0, err
+	}
+
+	if state.PID == 0 {
+		return 0, errors.New("container has no init process")
+	}
+
+	if state.Isolation == IsolationHyperV {
+		return 0, errors.New("exit code of a hyper-v isolated container's init process is not available")
+	}
+
+	h, err := m.sc.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(state.PID))
+	if err != nil {
+		return 0, fmt.Errorf("OpenProcess: %s", err.Error())
+	}
+	defer m.sc.CloseHandle(h)
+
+	creationTime, err := m.sc.GetProcessStartTime(h)
+	if err != nil {
+		return 0, fmt.Errorf("GetProcessStartTime: %s", err.Error())
+	}
+
+	if creationTime != state.StartTime {
+		return 0, errors.New("init process is no longer available")
+	}
+
+	exitCode, err := m.sc.GetExitCodeProcess(h)
+	if err != nil {
+		return 0, fmt.Errorf("GetExitCodeProcess: %s", err.Error())
+	}
+
+	if exitCode == STILL_ACTIVE_EXIT_CODE {
+		return 0, errors.New("init process is still running")
+	}
+
+	return int(exitCode), nil
+}
+
 func (m *Manager) userProgramStatus(state State) (specs.ContainerState, error) {
-	if state.ExecFailed {
+	if state.ExecFailed || state.Stopped {
 		return specs.StateStopped, nil
 	}
 
@@ -173,6 +441,21 @@ func (m *Manager) userProgramStatus(state State) (specs.ContainerState, error) {
 		return specs.StateCreated, nil
 	}
 
+	if state.Isolation == IsolationHyperV {
+		item, err := m.hyperVProcess(state.PID)
+		if err != nil {
+			if _, ok := err.(*processNotFoundError); ok {
+				return specs.StateStopped, nil
+			}
+			return "", fmt.Errorf("ProcessList: %s", err.Error())
+		}
+
+		if syscall.NsecToFiletime(item.CreateTimestamp.UnixNano()) == state.StartTime {
+			return specs.StateRunning, nil
+		}
+		return specs.StateStopped, nil
+	}
+
 	h, err := m.sc.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(state.PID))
 	if err != nil {
 		if errno, ok := err.(syscall.Errno); ok {
@@ -206,6 +489,29 @@ func (m *Manager) userProgramStatus(state State) (specs.ContainerState, error) {
 	return "stopped", nil
 }
 
+// hyperVProcess looks up a process of a hyper-v isolated container in the
+// process list HCS reports for its utility VM
+func (m *Manager) hyperVProcess(pid int) (hcsshim.ProcessListItem, error) {
+	container, err := m.hcsClient.OpenContainer(m.containerId)
+	if err != nil {
+		return hcsshim.ProcessListItem{}, err
+	}
+	defer container.Close()
+
+	items, err := container.ProcessList()
+	if err != nil {
+		return hcsshim.ProcessListItem{}, err
+	}
+
+	for _, item := range items {
+		if int(item.ProcessId) == pid {
+			return item, nil
+		}
+	}
+
+	return hcsshim.ProcessListItem{}, &processNotFoundError{pid: pid}
+}
+
 func stateValid(state State) bool {
 	return (state.PID == 0 && state.StartTime == syscall.Filetime{}) ||
 		(state.PID != 0 && state.StartTime != syscall.Filetime{})
@@ -215,6 +521,10 @@ func (m *Manager) stateDir() string {
 	return filepath.Join(m.rootDir, m.containerId)
 }
 
+func (m *Manager) lockFile() string {
+	return filepath.Join(m.rootDir, m.containerId+".lock")
+}
+
 func (m *Manager) loadState() (State, error) {
 	logrus.Debugf("load state")
 	contents, err := os.ReadFile(filepath.Join(m.stateDir(), stateFile))
@@ -223,17 +533,126 @@ func (m *Manager) loadState() (State, error) {
 	}
 	var state State
 	if err := json.Unmarshal(contents, &state); err != nil {
-		return State{}, err
+		return State{}, &CorruptStateError{Id: m.containerId, Reason: err.Error()}
+	}
+
+	if state.Version > StateVersion {
+		return State{}, &CorruptStateError{Id: m.containerId, Reason: fmt.Sprintf("version %d is newer than the supported version %d", state.Version, StateVersion)}
 	}
+
+	version := state.Version
+	migrate(&state)
+
+	if reason := invalidState(state, version); reason != "" {
+		return State{}, &CorruptStateError{Id: m.containerId, Reason: reason}
+	}
+
 	logrus.Debugf("state for bundle: %s", state.Bundle)
 	return state, nil
 }
 
+// migrate brings state loaded from an older state.json up to StateVersion.
+// The migrated state is persisted the next time the state is written.
+func migrate(state *State) {
+	// containers created before isolation was recorded for every container
+	// are all process isolated, as hyper-v isolation was always recorded
+	if state.Isolation == "" {
+		state.Isolation = IsolationProcess
+	}
+
+	state.Version = StateVersion
+}
+
+// invalidState returns why a migrated state is not one winc could have
+// written, e.g. because state.json was edited or only partly written, or ""
+// if it is valid
+func invalidState(state State, loadedVersion int) string {
+	if state.Isolation != IsolationProcess && state.Isolation != IsolationHyperV {
+		return fmt.Sprintf("unknown isolation %q", state.Isolation)
+	}
+
+	// the creation time is recorded by every version of winc that versions
+	// its state
+	if loadedVersion >= 1 && state.Created.IsZero() {
+		return "creation time is missing"
+	}
+
+	if state.PID < 0 {
+		return fmt.Sprintf("invalid pid %d", state.PID)
+	}
+
+	return ""
+}
+
+// renameAttempts and renameBackoff bound how long writeState retries renaming
+// over state.json while a command that doesn't take the lock, such as state
+// or list, has it open. Windows can't replace a file that is open without
+// FILE_SHARE_DELETE.
+const (
+	renameAttempts = 10
+	renameBackoff  = 20 * time.Millisecond
+)
+
+// writeState writes to a temporary file and renames it over state.json, so
+// a crash part way through leaves either the old or the new state behind
 func (m *Manager) writeState(state State) error {
+	state.Version = StateVersion
+
 	contents, err := json.Marshal(state)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(filepath.Join(m.stateDir(), stateFile), contents, 0644)
+	// the caller holds the lock, so temporary files are left from a crash
+	m.removeTempFiles()
+
+	tmp, err := os.CreateTemp(m.stateDir(), stateFile+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(contents)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = renameOver(tmp.Name(), filepath.Join(m.stateDir(), stateFile))
+	}
+
+	if err != nil {
+		// #nosec G104 - the failed write is the error we want to report
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
+
+func (m *Manager) removeTempFiles() {
+	tmps, err := filepath.Glob(filepath.Join(m.stateDir(), stateFile+".tmp*"))
+	if err != nil {
+		return
+	}
+
+	for _, tmp := range tmps {
+		if err := os.Remove(tmp); err != nil {
+			m.logger.WithError(err).Debug("could not remove temporary state file")
+		}
+	}
+}
+
+func renameOver(from, to string) error {
+	var err error
+	for attempt := 1; attempt <= renameAttempts; attempt++ {
+		err = os.Rename(from, to)
+		if !errors.Is(err, windows.ERROR_ACCESS_DENIED) && !errors.Is(err, windows.ERROR_SHARING_VIOLATION) {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * renameBackoff)
+	}
+
+	return err
 }
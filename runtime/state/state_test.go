@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime/state"
@@ -53,7 +54,7 @@ var _ = Describe("State", func() {
 
 	Describe("Initialize", func() {
 		It("writes the bundle path to state.json in <rootDir>/<containerId>/", func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
 
 			var state state.State
 			contents, err := os.ReadFile(stateFile)
@@ -64,12 +65,278 @@ var _ = Describe("State", func() {
 			Expect(state.PID).To(Equal(0))
 			Expect(state.StartTime).To(Equal(syscall.Filetime{}))
 			Expect(state.ExecFailed).To(Equal(false))
+			Expect(state.Created).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(state.Isolation).To(Equal("process"))
+		})
+
+		It("reports the container as process isolated and created in the oci state", func() {
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
+			hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{ID: containerId}, nil)
+
+			ociState, err := sm.State()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ociState.Status).To(Equal(specs.StateCreated))
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.IsolationAnnotation, state.IsolationProcess))
+		})
+
+		Context("the spec requests hyper-v isolation", func() {
+			It("records the isolation in state.json and reports it in the oci state", func() {
+				Expect(sm.Initialize(bundlePath, &specs.Spec{Windows: &specs.Windows{HyperV: &specs.WindowsHyperV{}}})).To(Succeed())
+
+				var s state.State
+				contents, err := os.ReadFile(stateFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &s)).To(Succeed())
+				Expect(s.Isolation).To(Equal(state.IsolationHyperV))
+
+				hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{ID: containerId}, nil)
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Annotations).To(HaveKeyWithValue(state.IsolationAnnotation, state.IsolationHyperV))
+			})
+		})
+
+		Context("the spec has annotations, a volume and a network endpoint", func() {
+			var spec *specs.Spec
+
+			BeforeEach(func() {
+				spec = &specs.Spec{
+					Annotations: map[string]string{"some-key": "some-value"},
+					Root:        &specs.Root{Path: "\\\\?\\Volume{some-guid}\\"},
+					Windows: &specs.Windows{
+						Network: &specs.WindowsNetwork{NetworkSharedContainerName: containerId},
+					},
+				}
+				hcsClient.GetHNSEndpointByNameReturns(&hcsshim.HNSEndpoint{Id: "some-endpoint-id"}, nil)
+			})
+
+			It("records them in state.json", func() {
+				Expect(sm.Initialize(bundlePath, spec)).To(Succeed())
+
+				var s state.State
+				contents, err := os.ReadFile(stateFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &s)).To(Succeed())
+
+				Expect(s.Annotations).To(Equal(map[string]string{"some-key": "some-value"}))
+				Expect(s.VolumePath).To(Equal("\\\\?\\Volume{some-guid}\\"))
+				Expect(s.EndpointId).To(Equal("some-endpoint-id"))
+				Expect(hcsClient.GetHNSEndpointByNameArgsForCall(0)).To(Equal(containerId))
+			})
+
+			Context("the endpoint cannot be found", func() {
+				BeforeEach(func() {
+					hcsClient.GetHNSEndpointByNameReturns(nil, errors.New("no endpoint"))
+				})
+
+				It("returns an error without writing the state", func() {
+					Expect(sm.Initialize(bundlePath, spec)).To(MatchError("no endpoint"))
+					Expect(stateFile).NotTo(BeAnExistingFile())
+				})
+			})
+		})
+	})
+
+	Describe("Load", func() {
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
+		})
+
+		It("returns the persisted state without querying hcs", func() {
+			s, err := sm.Load()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Bundle).To(Equal(bundlePath))
+			Expect(hcsClient.GetContainerPropertiesCallCount()).To(Equal(0))
+		})
+
+		It("writes the current version and leaves no temporary files behind", func() {
+			s, err := sm.Load()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Version).To(Equal(state.StateVersion))
+
+			entries, err := os.ReadDir(filepath.Dir(stateFile))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Name()).To(Equal("state.json"))
+		})
+
+		Context("the state dir does not exist", func() {
+			BeforeEach(func() {
+				Expect(sm.Delete()).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				_, err := sm.Load()
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("state.json predates versioning", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(stateFile, []byte(`{"bundle":"some/path/some-container","pid":0}`), 0644)).To(Succeed())
+			})
+
+			It("migrates it to the current version", func() {
+				s, err := sm.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(s.Version).To(Equal(state.StateVersion))
+				Expect(s.Bundle).To(Equal(bundlePath))
+				Expect(s.Isolation).To(Equal(state.IsolationProcess))
+			})
+
+			It("persists the migrated state on the next write", func() {
+				Expect(sm.SetStopped()).To(Succeed())
+
+				var s state.State
+				contents, err := os.ReadFile(stateFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &s)).To(Succeed())
+				Expect(s.Version).To(Equal(state.StateVersion))
+				Expect(s.Isolation).To(Equal(state.IsolationProcess))
+			})
+		})
+
+		Context("state.json is truncated", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(stateFile, []byte(`{"version":1,"bundle":"some/pa`), 0644)).To(Succeed())
+			})
+
+			It("returns a CorruptStateError", func() {
+				_, err := sm.Load()
+				Expect(err).To(BeAssignableToTypeOf(&state.CorruptStateError{}))
+				Expect(err.Error()).To(HavePrefix("state for container some-container is corrupt: "))
+			})
+		})
+
+		Context("state.json records an unknown isolation", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(stateFile, []byte(`{"version":1,"bundle":"some/path/some-container","created":"2020-01-02T03:04:05Z","isolation":"potato"}`), 0644)).To(Succeed())
+			})
+
+			It("returns a CorruptStateError", func() {
+				_, err := sm.Load()
+				Expect(err).To(MatchError(`state for container some-container is corrupt: unknown isolation "potato"`))
+			})
+		})
+
+		Context("state.json of the current version has no creation time", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(stateFile, []byte(`{"version":1,"bundle":"some/path/some-container","isolation":"process"}`), 0644)).To(Succeed())
+			})
+
+			It("returns a CorruptStateError", func() {
+				_, err := sm.Load()
+				Expect(err).To(MatchError("state for container some-container is corrupt: creation time is missing"))
+			})
+		})
+
+		Context("state.json has a negative pid", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(stateFile, []byte(`{"version":1,"bundle":"some/path/some-container","created":"2020-01-02T03:04:05Z","pid":-1}`), 0644)).To(Succeed())
+			})
+
+			It("returns a CorruptStateError", func() {
+				_, err := sm.Load()
+				Expect(err).To(MatchError("state for container some-container is corrupt: invalid pid -1"))
+			})
+		})
+
+		Context("state.json was written by a newer version of winc", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(stateFile, []byte(`{"version":99,"bundle":"some/path/some-container"}`), 0644)).To(Succeed())
+			})
+
+			It("returns a CorruptStateError", func() {
+				_, err := sm.Load()
+				Expect(err).To(MatchError("state for container some-container is corrupt: version 99 is newer than the supported version 1"))
+			})
+		})
+	})
+
+	Describe("Lock", func() {
+		var other *state.Manager
+
+		BeforeEach(func() {
+			logger := (&logrus.Logger{Out: io.Discard}).WithField("test", "state")
+			other = state.New(logger, hcsClient, sc, containerId, rootDir)
+		})
+
+		AfterEach(func() {
+			Expect(sm.Unlock()).To(Succeed())
+			Expect(other.Unlock()).To(Succeed())
+		})
+
+		It("creates a lock file next to the state dir", func() {
+			Expect(sm.Lock(time.Second)).To(Succeed())
+			Expect(filepath.Join(rootDir, containerId+".lock")).To(BeAnExistingFile())
+		})
+
+		Context("another manager holds the lock", func() {
+			BeforeEach(func() {
+				Expect(other.Lock(time.Second)).To(Succeed())
+			})
+
+			It("returns a BusyError after the timeout", func() {
+				err := sm.Lock(100 * time.Millisecond)
+				Expect(err).To(BeAssignableToTypeOf(&state.BusyError{}))
+				Expect(err).To(MatchError("container some-container is busy: another winc command is operating on it"))
+			})
+
+			It("acquires the lock once it is released", func() {
+				go func() {
+					defer GinkgoRecover()
+					time.Sleep(100 * time.Millisecond)
+					Expect(other.Unlock()).To(Succeed())
+				}()
+
+				Expect(sm.Lock(5 * time.Second)).To(Succeed())
+			})
+		})
+
+		It("can be held while the state dir is deleted", func() {
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
+			Expect(sm.Lock(time.Second)).To(Succeed())
+			Expect(sm.Delete()).To(Succeed())
+			Expect(filepath.Dir(stateFile)).NotTo(BeADirectory())
+		})
+
+		Context("the state dir exists when the lock is released", func() {
+			It("leaves the lock file", func() {
+				Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
+				Expect(sm.Lock(time.Second)).To(Succeed())
+				Expect(sm.Unlock()).To(Succeed())
+				Expect(filepath.Join(rootDir, containerId+".lock")).To(BeAnExistingFile())
+			})
+		})
+
+		Context("the state dir has been deleted when the lock is released", func() {
+			It("removes the lock file", func() {
+				Expect(sm.Lock(time.Second)).To(Succeed())
+				Expect(sm.Unlock()).To(Succeed())
+				Expect(filepath.Join(rootDir, containerId+".lock")).NotTo(BeAnExistingFile())
+			})
+
+			Context("another manager is waiting for the lock", func() {
+				It("leaves the lock file for it", func() {
+					Expect(sm.Lock(time.Second)).To(Succeed())
+
+					locked := make(chan error, 1)
+					go func() {
+						locked <- other.Lock(5 * time.Second)
+					}()
+					time.Sleep(100 * time.Millisecond)
+
+					Expect(sm.Unlock()).To(Succeed())
+					Eventually(locked).Should(Receive(BeNil()))
+					Expect(filepath.Join(rootDir, containerId+".lock")).To(BeAnExistingFile())
+				})
+			})
 		})
 	})
 
 	Describe("Delete", func() {
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 		})
 
@@ -82,7 +349,7 @@ var _ = Describe("State", func() {
 
 	Describe("SetFailure", func() {
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 		})
 
@@ -101,6 +368,114 @@ var _ = Describe("State", func() {
 		})
 	})
 
+	Describe("SetStopped", func() {
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
+			Expect(stateFile).To(BeAnExistingFile())
+		})
+
+		It("sets that the container was stopped in the state.json", func() {
+			Expect(sm.SetStopped()).To(Succeed())
+
+			var state state.State
+			contents, err := os.ReadFile(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(contents, &state)).To(Succeed())
+
+			Expect(state.Bundle).To(Equal(bundlePath))
+			Expect(state.Stopped).To(Equal(true))
+		})
+
+		Context("another command has state.json open", func() {
+			var reader *os.File
+
+			BeforeEach(func() {
+				var err error
+				reader, err = os.Open(stateFile)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				reader.Close()
+			})
+
+			It("waits for it to be closed", func() {
+				go func() {
+					defer GinkgoRecover()
+					time.Sleep(100 * time.Millisecond)
+					Expect(reader.Close()).To(Succeed())
+				}()
+
+				Expect(sm.SetStopped()).To(Succeed())
+				s, err := sm.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(s.Stopped).To(BeTrue())
+			})
+
+			It("gives up eventually and leaves no temporary file behind", func() {
+				Expect(sm.SetStopped()).NotTo(Succeed())
+
+				entries, err := os.ReadDir(filepath.Dir(stateFile))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(HaveLen(1))
+				Expect(entries[0].Name()).To(Equal("state.json"))
+			})
+		})
+
+		Context("a crashed write left a temporary file behind", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(stateFile+".tmp123", []byte("{"), 0644)).To(Succeed())
+			})
+
+			It("removes it", func() {
+				Expect(sm.SetStopped()).To(Succeed())
+				Expect(stateFile + ".tmp123").NotTo(BeAnExistingFile())
+			})
+		})
+	})
+
+	Describe("SetPaused", func() {
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
+		})
+
+		It("records whether the container is paused in the state.json", func() {
+			Expect(sm.SetPaused(true)).To(Succeed())
+
+			var s state.State
+			contents, err := os.ReadFile(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(contents, &s)).To(Succeed())
+			Expect(s.Paused).To(BeTrue())
+
+			Expect(sm.SetPaused(false)).To(Succeed())
+
+			contents, err = os.ReadFile(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(contents, &s)).To(Succeed())
+			Expect(s.Paused).To(BeFalse())
+		})
+	})
+
+	Describe("SetResources", func() {
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
+		})
+
+		It("records the resource limits in the state.json", func() {
+			limit := uint64(1024 * 1024 * 1024)
+			Expect(sm.SetResources(&specs.WindowsResources{
+				Memory: &specs.WindowsMemoryResources{Limit: &limit},
+			})).To(Succeed())
+
+			var s state.State
+			contents, err := os.ReadFile(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(contents, &s)).To(Succeed())
+			Expect(*s.Resources.Memory.Limit).To(Equal(limit))
+		})
+	})
+
 	Describe("SetSuccess", func() {
 		var (
 			proc *hcsfakes.Process
@@ -108,7 +483,7 @@ var _ = Describe("State", func() {
 		)
 
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 
 			proc = &hcsfakes.Process{}
@@ -170,6 +545,56 @@ var _ = Describe("State", func() {
 			})
 		})
 
+		Context("the container is hyper-v isolated", func() {
+			var (
+				container *hcsfakes.Container
+				startTime time.Time
+			)
+
+			BeforeEach(func() {
+				Expect(sm.Initialize(bundlePath, &specs.Spec{Windows: &specs.Windows{HyperV: &specs.WindowsHyperV{}}})).To(Succeed())
+
+				startTime = time.Date(2020, 1, 2, 3, 4, 5, 600, time.UTC)
+				container = &hcsfakes.Container{}
+				container.ProcessListReturns([]hcsshim.ProcessListItem{
+					{ProcessId: 4, CreateTimestamp: startTime.Add(-time.Minute)},
+					{ProcessId: 888, CreateTimestamp: startTime},
+				}, nil)
+				hcsClient.OpenContainerReturns(container, nil)
+			})
+
+			It("sets the pid + start time HCS reports for the utility VM process", func() {
+				Expect(sm.SetSuccess(proc)).To(Succeed())
+
+				var s state.State
+				contents, err := os.ReadFile(stateFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &s)).To(Succeed())
+
+				Expect(s.PID).To(Equal(888))
+				Expect(s.StartTime).To(Equal(syscall.NsecToFiletime(startTime.UnixNano())))
+				Expect(s.ExecFailed).To(BeFalse())
+
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+
+			Context("the process is not in the process list", func() {
+				BeforeEach(func() {
+					container.ProcessListReturns([]hcsshim.ProcessListItem{{ProcessId: 4}}, nil)
+				})
+
+				It("sets exec failed in the state.json", func() {
+					Expect(sm.SetSuccess(proc)).To(MatchError("ProcessList: process 888 not found"))
+
+					var s state.State
+					contents, err := os.ReadFile(stateFile)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(json.Unmarshal(contents, &s)).To(Succeed())
+					Expect(s.ExecFailed).To(BeTrue())
+				})
+			})
+		})
+
 		Context("GetProcessStartTime fails", func() {
 			BeforeEach(func() {
 				sc.OpenProcessReturns(ph, nil)
@@ -202,6 +627,114 @@ var _ = Describe("State", func() {
 		})
 	})
 
+	Describe("OwnEndpointId", func() {
+		It("returns the id of the endpoint named after the container", func() {
+			hcsClient.GetHNSEndpointByNameReturns(&hcsshim.HNSEndpoint{Id: "own-endpoint-id"}, nil)
+
+			id, err := sm.OwnEndpointId()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal("own-endpoint-id"))
+			Expect(hcsClient.GetHNSEndpointByNameArgsForCall(0)).To(Equal(containerId))
+		})
+
+		Context("the container has no endpoint", func() {
+			BeforeEach(func() {
+				hcsClient.GetHNSEndpointByNameReturns(nil, errors.New("Endpoint some-container not found"))
+			})
+
+			It("returns the error", func() {
+				_, err := sm.OwnEndpointId()
+				Expect(err).To(MatchError("Endpoint some-container not found"))
+			})
+		})
+	})
+
+	Describe("ExitCode", func() {
+		var startTime syscall.Filetime
+
+		BeforeEach(func() {
+			startTime = syscall.Filetime{HighDateTime: 123, LowDateTime: 456}
+			c, err := json.Marshal(state.State{PID: 1234, Bundle: bundlePath, StartTime: startTime})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Dir(stateFile), 0755)).To(Succeed())
+			Expect(os.WriteFile(stateFile, c, 0644)).To(Succeed())
+
+			sc.OpenProcessReturns(0xbeef, nil)
+			sc.GetProcessStartTimeReturns(startTime, nil)
+			sc.GetExitCodeProcessReturns(7, nil)
+		})
+
+		It("returns the exit code of the init process", func() {
+			exitCode, err := sm.ExitCode()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitCode).To(Equal(7))
+
+			_, _, pid := sc.OpenProcessArgsForCall(0)
+			Expect(pid).To(Equal(uint32(1234)))
+			Expect(sc.CloseHandleArgsForCall(0)).To(Equal(syscall.Handle(0xbeef)))
+		})
+
+		Context("the init process is still running", func() {
+			BeforeEach(func() {
+				sc.GetExitCodeProcessReturns(state.STILL_ACTIVE_EXIT_CODE, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := sm.ExitCode()
+				Expect(err).To(MatchError("init process is still running"))
+			})
+		})
+
+		Context("the pid has been reused by another process", func() {
+			BeforeEach(func() {
+				sc.GetProcessStartTimeReturns(syscall.Filetime{HighDateTime: 1, LowDateTime: 2}, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := sm.ExitCode()
+				Expect(err).To(MatchError("init process is no longer available"))
+			})
+		})
+
+		Context("the init process cannot be opened", func() {
+			BeforeEach(func() {
+				sc.OpenProcessReturns(0, errors.New("couldn't open"))
+			})
+
+			It("returns an error", func() {
+				_, err := sm.ExitCode()
+				Expect(err).To(MatchError("OpenProcess: couldn't open"))
+			})
+		})
+
+		Context("the container is hyper-v isolated", func() {
+			BeforeEach(func() {
+				c, err := json.Marshal(state.State{PID: 1234, Bundle: bundlePath, StartTime: startTime, Isolation: state.IsolationHyperV})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(stateFile, c, 0644)).To(Succeed())
+			})
+
+			It("returns an error without opening the utility VM pid on the host", func() {
+				_, err := sm.ExitCode()
+				Expect(err).To(MatchError("exit code of a hyper-v isolated container's init process is not available"))
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the container has no init process", func() {
+			BeforeEach(func() {
+				Expect(sm.Initialize(bundlePath, &specs.Spec{})).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				_, err := sm.ExitCode()
+				Expect(err).To(MatchError("container has no init process"))
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Describe("State", func() {
 		var (
 			s state.State
@@ -219,6 +752,8 @@ var _ = Describe("State", func() {
 
 			Expect(os.MkdirAll(filepath.Dir(stateFile), 0755)).To(Succeed())
 			Expect(os.WriteFile(stateFile, c, 0644)).To(Succeed())
+
+			hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{ID: containerId}, nil)
 		})
 
 		It("includes the necessary fields in the oci state", func() {
@@ -228,6 +763,130 @@ var _ = Describe("State", func() {
 			Expect(ociState.Pid).To(Equal(1234))
 			Expect(ociState.ID).To(Equal(containerId))
 			Expect(ociState.Version).To(Equal(specs.Version))
+			Expect(ociState.Annotations).To(Equal(map[string]string{
+				state.IsolationAnnotation:   state.IsolationProcess,
+				state.HCSSystemIdAnnotation: containerId,
+			}))
+		})
+
+		Context("state.json records annotations, a volume, an endpoint and the creation time", func() {
+			var created time.Time
+
+			BeforeEach(func() {
+				created = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+				s.Annotations = map[string]string{
+					"some-key":                "some-value",
+					state.IsolationAnnotation: "spoofed",
+				}
+				s.VolumePath = "some-volume"
+				s.EndpointId = "some-endpoint-id"
+				s.Created = created
+				c, err := json.Marshal(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(stateFile, c, 0644)).To(Succeed())
+			})
+
+			It("reports them alongside the winc annotations, which take precedence", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Annotations).To(Equal(map[string]string{
+					"some-key":                  "some-value",
+					state.IsolationAnnotation:   state.IsolationProcess,
+					state.HCSSystemIdAnnotation: containerId,
+					state.EndpointIdAnnotation:  "some-endpoint-id",
+					state.VolumePathAnnotation:  "some-volume",
+					state.CreatedAnnotation:     "2020-01-02T03:04:05.000000006Z",
+				}))
+				Expect(hcsClient.GetHNSEndpointByNameCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the container has an endpoint of its own", func() {
+			BeforeEach(func() {
+				hcsClient.GetHNSEndpointByNameReturns(&hcsshim.HNSEndpoint{Id: "own-endpoint-id"}, nil)
+			})
+
+			It("doesn't look it up", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Annotations).NotTo(HaveKey(state.EndpointIdAnnotation))
+				Expect(hcsClient.GetHNSEndpointByNameCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("state.json records the container as hyper-v isolated", func() {
+			var (
+				container *hcsfakes.Container
+				startTime time.Time
+			)
+
+			BeforeEach(func() {
+				startTime = time.Date(2020, 1, 2, 3, 4, 5, 600, time.UTC)
+				s.Isolation = state.IsolationHyperV
+				s.StartTime = syscall.NsecToFiletime(startTime.UnixNano())
+				c, err := json.Marshal(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(stateFile, c, 0644)).To(Succeed())
+
+				container = &hcsfakes.Container{}
+				container.ProcessListReturns([]hcsshim.ProcessListItem{
+					{ProcessId: 1234, CreateTimestamp: startTime},
+				}, nil)
+				hcsClient.OpenContainerReturns(container, nil)
+			})
+
+			It("reports the isolation in the annotations", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Annotations).To(HaveKeyWithValue(state.IsolationAnnotation, state.IsolationHyperV))
+			})
+
+			It("reports the status of the init process from HCS, without opening the utility VM pid on the host", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Status).To(Equal(specs.StateRunning))
+
+				Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+				Expect(container.CloseCallCount()).To(Equal(1))
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+
+			Context("the init process is no longer in the process list", func() {
+				BeforeEach(func() {
+					container.ProcessListReturns([]hcsshim.ProcessListItem{{ProcessId: 4}}, nil)
+				})
+
+				It("reports the container is stopped", func() {
+					ociState, err := sm.State()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ociState.Status).To(Equal(specs.StateStopped))
+				})
+			})
+
+			Context("the pid has been reused by another process", func() {
+				BeforeEach(func() {
+					container.ProcessListReturns([]hcsshim.ProcessListItem{
+						{ProcessId: 1234, CreateTimestamp: startTime.Add(time.Second)},
+					}, nil)
+				})
+
+				It("reports the container is stopped", func() {
+					ociState, err := sm.State()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ociState.Status).To(Equal(specs.StateStopped))
+				})
+			})
+
+			Context("listing the processes fails", func() {
+				BeforeEach(func() {
+					container.ProcessListReturns(nil, errors.New("couldn't list"))
+				})
+
+				It("returns an error", func() {
+					_, err := sm.State()
+					Expect(err).To(MatchError("ProcessList: couldn't list"))
+				})
+			})
 		})
 
 		Context("hcsshim reports the container as stopped", func() {
@@ -289,6 +948,37 @@ var _ = Describe("State", func() {
 			})
 		})
 
+		Context("container init process is running and the container is paused", func() {
+			BeforeEach(func() {
+				s.Paused = true
+				c, err := json.Marshal(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(stateFile, c, 0644)).To(Succeed())
+
+				sc.OpenProcessReturns(0xf00d, nil)
+				sc.GetProcessStartTimeReturns(syscall.Filetime{HighDateTime: 123, LowDateTime: 456}, nil)
+				sc.GetExitCodeProcessReturns(259, nil)
+			})
+
+			It("reports the container is paused", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Status).To(Equal(state.StatePaused))
+			})
+
+			Context("hcsshim reports the container as stopped", func() {
+				BeforeEach(func() {
+					hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{Stopped: true}, nil)
+				})
+
+				It("reports the container is stopped", func() {
+					ociState, err := sm.State()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ociState.Status).To(Equal(specs.StateStopped))
+				})
+			})
+		})
+
 		Context("no process with container init pid is running", func() {
 			BeforeEach(func() {
 				sc.OpenProcessReturns(0, syscall.Errno(0x57))
@@ -393,6 +1083,22 @@ var _ = Describe("State", func() {
 			})
 		})
 
+		Context("the container was stopped by winc", func() {
+			BeforeEach(func() {
+				s.Stopped = true
+				c, err := json.Marshal(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.WriteFile(stateFile, c, 0644)).To(Succeed())
+			})
+
+			It("reports the container is stopped", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Status).To(Equal(specs.StateStopped))
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("getting container properties fails", func() {
 			BeforeEach(func() {
 				hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{}, errors.New("hcsshim failed"))
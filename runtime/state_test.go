@@ -6,6 +6,7 @@ import (
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -26,6 +27,7 @@ var _ = Describe("State", func() {
 		containerFactory   *fakes.ContainerFactory
 		cm                 *fakes.ContainerManager
 		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
 		hcsQuery           *fakes.HCSQuery
 		credentialSpecPath string
 		r                  *runtime.Runtime
@@ -40,6 +42,7 @@ var _ = Describe("State", func() {
 		containerFactory = &fakes.ContainerFactory{}
 		cm = &fakes.ContainerManager{}
 		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
 
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
@@ -47,7 +50,7 @@ var _ = Describe("State", func() {
 		output = gbytes.NewBuffer()
 
 		config := runtime.Config{}
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath, config)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
 	})
 
 	Context("state succeeds", func() {
@@ -76,6 +79,73 @@ var _ = Describe("State", func() {
 		})
 	})
 
+	Context("the container has an endpoint of its own", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{ID: containerId, Annotations: map[string]string{"some-key": "some-value"}}, nil)
+			sm.OwnEndpointIdReturns("own-endpoint-id", nil)
+		})
+
+		It("reports it", func() {
+			Expect(r.State(containerId, output)).To(Succeed())
+			Expect(sm.OwnEndpointIdCallCount()).To(Equal(1))
+			Expect(string(output.Contents())).To(ContainSubstring(`"org.cloudfoundry.winc.endpoint-id": "own-endpoint-id"`))
+			Expect(string(output.Contents())).To(ContainSubstring(`"some-key": "some-value"`))
+		})
+	})
+
+	Context("the container shares the endpoint of another container", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{ID: containerId, Annotations: map[string]string{
+				state.EndpointIdAnnotation: "shared-endpoint-id",
+			}}, nil)
+		})
+
+		It("reports it without looking up an endpoint of its own", func() {
+			Expect(r.State(containerId, output)).To(Succeed())
+			Expect(sm.OwnEndpointIdCallCount()).To(Equal(0))
+			Expect(string(output.Contents())).To(ContainSubstring(`"org.cloudfoundry.winc.endpoint-id": "shared-endpoint-id"`))
+		})
+	})
+
+	Context("looking up the container's own endpoint fails", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{ID: containerId}, nil)
+			sm.OwnEndpointIdReturns("", errors.New("Endpoint container-for-state not found"))
+		})
+
+		It("reports the state without an endpoint", func() {
+			Expect(r.State(containerId, output)).To(Succeed())
+			Expect(string(output.Contents())).NotTo(ContainSubstring("endpoint-id"))
+		})
+	})
+
+	Context("the resource limits have been updated", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{ID: containerId}, nil)
+			limit := uint64(1024 * 1024 * 1024)
+			sm.LoadReturns(state.State{
+				Resources: &specs.WindowsResources{
+					Memory: &specs.WindowsMemoryResources{Limit: &limit},
+				},
+			}, nil)
+		})
+
+		It("includes the effective limits in the output", func() {
+			Expect(r.State(containerId, output)).To(Succeed())
+			Expect(string(output.Contents())).To(Equal(`{
+  "ociVersion": "",
+  "id": "container-for-state",
+  "status": "",
+  "bundle": "",
+  "resources": {
+    "memory": {
+      "limit": 1073741824
+    }
+  }
+}`))
+		})
+	})
+
 	Context("state fails", func() {
 		BeforeEach(func() {
 			sm.StateReturns(&specs.State{}, errors.New("couldn't get state"))
@@ -0,0 +1,206 @@
+package runtime_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/config"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Update", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-update"
+		bundlePath  = "some/bundle"
+	)
+	var (
+		mounter            *fakes.Mounter
+		stateFactory       *fakes.StateFactory
+		sm                 *fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		cm                 *fakes.ContainerManager
+		processWrapper     *fakes.ProcessWrapper
+		hookRunner         *fakes.HookRunner
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		r                  *runtime.Runtime
+		memoryLimit        uint64
+		cpuShares          uint16
+		resources          *specs.WindowsResources
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		hookRunner = &fakes.HookRunner{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		config := runtime.Config{}
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir, credentialSpecPath, config)
+
+		createdMemoryLimit := uint64(1024 * 1024 * 1024)
+		createdCpuMaximum := uint16(8000)
+		cm.SpecReturns(&specs.Spec{
+			Windows: &specs.Windows{
+				Resources: &specs.WindowsResources{
+					Memory: &specs.WindowsMemoryResources{Limit: &createdMemoryLimit},
+					CPU:    &specs.WindowsCPUResources{Maximum: &createdCpuMaximum},
+				},
+			},
+		}, nil)
+		sm.StateReturns(&specs.State{Status: specs.StateRunning, Bundle: bundlePath}, nil)
+		sm.LoadReturns(state.State{}, nil)
+
+		memoryLimit = 512 * 1024 * 1024
+		resources = &specs.WindowsResources{
+			Memory: &specs.WindowsMemoryResources{Limit: &memoryLimit},
+		}
+	})
+
+	It("updates the container and records the effective limits in the state", func() {
+		Expect(r.Update(containerId, resources)).To(Succeed())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+		Expect(id).To(Equal(containerId))
+		Expect(rd).To(Equal(rootDir))
+
+		Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
+		Expect(cm.UpdateArgsForCall(0)).To(Equal(resources))
+
+		recorded := sm.SetResourcesArgsForCall(0)
+		Expect(*recorded.Memory.Limit).To(Equal(memoryLimit))
+		Expect(*recorded.CPU.Maximum).To(Equal(uint16(8000)))
+	})
+
+	Context("limits have already been updated", func() {
+		BeforeEach(func() {
+			previousShares := uint16(100)
+			sm.LoadReturns(state.State{
+				Resources: &specs.WindowsResources{
+					CPU: &specs.WindowsCPUResources{Shares: &previousShares},
+				},
+			}, nil)
+
+			cpuShares = 200
+			resources = &specs.WindowsResources{
+				CPU: &specs.WindowsCPUResources{Shares: &cpuShares},
+			}
+		})
+
+		It("merges the update with the previously recorded limits", func() {
+			Expect(r.Update(containerId, resources)).To(Succeed())
+
+			recorded := sm.SetResourcesArgsForCall(0)
+			Expect(recorded.Memory).To(BeNil())
+			Expect(*recorded.CPU.Shares).To(Equal(uint16(200)))
+			Expect(recorded.CPU.Maximum).To(BeNil())
+		})
+	})
+
+	Context("the update changes one of the recorded limits", func() {
+		BeforeEach(func() {
+			previousLimit := uint64(256 * 1024 * 1024)
+			previousMaximum := uint16(5000)
+			sm.LoadReturns(state.State{
+				Resources: &specs.WindowsResources{
+					Memory: &specs.WindowsMemoryResources{Limit: &previousLimit},
+					CPU:    &specs.WindowsCPUResources{Maximum: &previousMaximum},
+				},
+			}, nil)
+
+			cpuMaximum := uint16(6000)
+			resources = &specs.WindowsResources{
+				CPU: &specs.WindowsCPUResources{Maximum: &cpuMaximum},
+			}
+		})
+
+		It("keeps the other recorded limit", func() {
+			Expect(r.Update(containerId, resources)).To(Succeed())
+
+			recorded := sm.SetResourcesArgsForCall(0)
+			Expect(*recorded.Memory.Limit).To(Equal(uint64(256 * 1024 * 1024)))
+			Expect(*recorded.CPU.Maximum).To(Equal(uint16(6000)))
+		})
+	})
+
+	Context("the update conflicts with a limit the container already has", func() {
+		BeforeEach(func() {
+			cpuShares = 200
+			resources = &specs.WindowsResources{
+				CPU: &specs.WindowsCPUResources{Shares: &cpuShares},
+			}
+		})
+
+		It("returns a validation error without updating the container", func() {
+			err := r.Update(containerId, resources)
+			Expect(err).To(BeAssignableToTypeOf(&config.ResourcesValidationError{}))
+			Expect(err).To(MatchError(ContainSubstring("cpu shares and cpu maximum cannot both be set")))
+			Expect(cm.UpdateCallCount()).To(Equal(0))
+			Expect(sm.SetResourcesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the container is stopped", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: specs.StateStopped}, nil)
+		})
+
+		It("returns an error", func() {
+			Expect(r.Update(containerId, resources)).To(MatchError("cannot update a container in the stopped state"))
+			Expect(cm.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the resources are invalid", func() {
+		BeforeEach(func() {
+			resources = &specs.WindowsResources{}
+		})
+
+		It("returns a validation error", func() {
+			err := r.Update(containerId, resources)
+			Expect(err).To(BeAssignableToTypeOf(&config.ResourcesValidationError{}))
+			Expect(cm.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("updating the container fails", func() {
+		BeforeEach(func() {
+			cm.UpdateReturns(errors.New("couldn't update"))
+		})
+
+		It("returns the error without recording the limits", func() {
+			Expect(r.Update(containerId, resources)).To(MatchError("couldn't update"))
+			Expect(sm.SetResourcesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("recording the limits fails", func() {
+		BeforeEach(func() {
+			sm.SetResourcesReturns(errors.New("couldn't write state"))
+		})
+
+		It("returns the error", func() {
+			Expect(r.Update(containerId, resources)).To(MatchError("couldn't write state"))
+		})
+	})
+})